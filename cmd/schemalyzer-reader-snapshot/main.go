@@ -0,0 +1,24 @@
+// Command schemalyzer-reader-snapshot is the reference reader plugin. It
+// serves schemas from files written by `schemalyzer export`, so an exported
+// snapshot can stand in for a live database:
+//
+//	schemalyzer compare --source-type snapshot --source-conn ./exports --source-schema public ...
+//
+// The connection string is a directory of .yaml, .yml or .json exports, or a
+// single export file. Both single-schema and whole-database exports are read.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nechja/schemalyzer/pkg/plugin"
+)
+
+func main() {
+	if err := plugin.Serve(context.Background(), os.Stdin, os.Stdout, "snapshot", newSnapshotReader()); err != nil {
+		fmt.Fprintf(os.Stderr, "schemalyzer-reader-snapshot: %v\n", err)
+		os.Exit(1)
+	}
+}
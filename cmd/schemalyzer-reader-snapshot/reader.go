@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/internal/schema"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// snapshotReader serves schemas loaded from export files
+type snapshotReader struct {
+	schemas map[string]*models.Schema
+}
+
+func newSnapshotReader() *snapshotReader {
+	return &snapshotReader{}
+}
+
+func (r *snapshotReader) Connect(ctx context.Context, connectionString string) error {
+	info, err := os.Stat(connectionString)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	files := []string{connectionString}
+	if info.IsDir() {
+		entries, err := os.ReadDir(connectionString)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(connectionString, entry.Name()))
+				}
+			}
+		}
+	}
+
+	r.schemas = make(map[string]*models.Schema)
+	for _, file := range files {
+		if err := r.load(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load adds the schemas of one export file, which holds either a whole
+// database or a single schema
+func (r *snapshotReader) load(file string) error {
+	loader := schema.NewLoader()
+
+	db, err := loader.LoadDatabaseFromFile(file)
+	if err == nil && len(db.Schemas) > 0 {
+		for i := range db.Schemas {
+			s := db.Schemas[i]
+			if s.DatabaseType == "" {
+				s.DatabaseType = db.DatabaseType
+			}
+			if err := r.add(file, &s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	s, err := loader.LoadFromFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", file, err)
+	}
+	return r.add(file, s)
+}
+
+func (r *snapshotReader) add(file string, s *models.Schema) error {
+	if s.Name == "" {
+		return fmt.Errorf("%s contains a schema with no name", file)
+	}
+	if _, exists := r.schemas[s.Name]; exists {
+		return fmt.Errorf("schema %s is defined more than once (again in %s)", s.Name, file)
+	}
+	r.schemas[s.Name] = s
+	return nil
+}
+
+func (r *snapshotReader) ListSchemas(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, len(r.schemas))
+	for name := range r.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (r *snapshotReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	s, ok := r.schemas[schemaName]
+	if !ok {
+		return nil, fmt.Errorf("schema %s not found in snapshot", schemaName)
+	}
+	return s, nil
+}
+
+func (r *snapshotReader) Close() error {
+	return nil
+}
+
+// GetTableRowCount returns the row count captured by `export --with-row-count`
+func (r *snapshotReader) GetTableRowCount(ctx context.Context, schemaName, tableName string) (int64, error) {
+	table, err := r.table(schemaName, tableName)
+	if err != nil {
+		return 0, err
+	}
+	if table.RowCount == nil {
+		return 0, fmt.Errorf("snapshot has no row count for table %s", tableName)
+	}
+	return *table.RowCount, nil
+}
+
+// GetColumnSamples returns the samples captured by `export --with-samples`
+func (r *snapshotReader) GetColumnSamples(ctx context.Context, schemaName, tableName, columnName string, limit int) ([]string, error) {
+	table, err := r.table(schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range table.Columns {
+		if column.Name == columnName {
+			samples := column.Samples
+			if limit > 0 && len(samples) > limit {
+				samples = samples[:limit]
+			}
+			return samples, nil
+		}
+	}
+	return nil, fmt.Errorf("column %s not found in table %s", columnName, tableName)
+}
+
+func (r *snapshotReader) table(schemaName, tableName string) (*models.Table, error) {
+	s, err := r.GetSchema(context.Background(), schemaName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.Tables {
+		if s.Tables[i].Name == tableName {
+			return &s.Tables[i], nil
+		}
+	}
+	return nil, fmt.Errorf("table %s not found in schema %s", tableName, schemaName)
+}
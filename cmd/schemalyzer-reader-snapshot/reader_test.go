@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/nechja/schemalyzer/internal/schema"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/plugin"
+	"github.com/nechja/schemalyzer/pkg/plugin/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	dir := t.TempDir()
+	rowCount := int64(42)
+	exported := &models.Schema{
+		Name:         "public",
+		DatabaseType: models.PostgreSQL,
+		Tables: []models.Table{
+			{
+				Name:     "users",
+				RowCount: &rowCount,
+				Columns: []models.Column{
+					{Name: "id", DataType: "integer", Position: 1},
+					{Name: "email", DataType: "text", Position: 2},
+				},
+				Constraints: []models.Constraint{
+					{Name: "users_pkey", Type: models.PrimaryKey, Columns: []string{"id"}},
+				},
+			},
+		},
+	}
+	if err := schema.NewLoader().SaveToFile(exported, filepath.Join(dir, "public.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run the plugin in-process, wired to the client through pipes
+	requests, requestWriter := io.Pipe()
+	responseReader, responses := io.Pipe()
+	go func() {
+		plugin.Serve(context.Background(), requests, responses, "snapshot", newSnapshotReader())
+		responses.Close()
+	}()
+
+	conformance.Run(t, plugin.NewStreamClient(responseReader, requestWriter), dir)
+}
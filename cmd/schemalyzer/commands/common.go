@@ -4,96 +4,101 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
+	"strings"
 
-	"github.com/nechja/schemalyzer/internal/database"
-	"github.com/nechja/schemalyzer/internal/database/mysql"
-	"github.com/nechja/schemalyzer/internal/database/oracle"
-	"github.com/nechja/schemalyzer/internal/database/postgres"
 	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"github.com/spf13/cobra"
 )
 
-func createReader(dbType string) (database.SchemaReader, error) {
-	switch models.DatabaseType(dbType) {
-	case models.PostgreSQL:
-		return postgres.NewPostgresReader(), nil
-	case models.MySQL:
-		return mysql.NewMySQLReader(), nil
-	case models.Oracle:
-		return oracle.NewOracleReader(), nil
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+// openReader creates and connects a reader with the global pool, timeout
+// and --consistent settings
+func openReader(ctx context.Context, dbType, connectionString string) (schemalyzer.Reader, error) {
+	return schemalyzer.Open(ctx, dbType, connectionString, readerOptions())
+}
+
+// readerOptions builds the reader pool and timeout settings from the global flags
+func readerOptions() schemalyzer.ReaderOptions {
+	return schemalyzer.ReaderOptions{
+		MaxOpenConns:     maxOpenConns,
+		MaxIdleConns:     maxIdleConns,
+		ConnMaxLifetime:  connMaxLifetime,
+		StatementTimeout: statementTimeout,
+		Consistent:       consistentSnapshot,
 	}
 }
 
-// filterTablesOnly returns a copy of the schema with only tables and views
-func filterTablesOnly(schema *models.Schema) *models.Schema {
-	filtered := &models.Schema{
-		Name:         schema.Name,
-		DatabaseType: schema.DatabaseType,
-		Tables:       schema.Tables,
-		Views:        schema.Views,
-		// Exclude these when --tables-only is set:
-		Sequences:  []models.Sequence{},
-		Functions:  []models.Function{},
-		Procedures: []models.Procedure{},
-		Triggers:   []models.Trigger{},
+// commandContext returns the command's signal-aware context, bounded by --timeout when set
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	return filtered
+	if commandTimeout > 0 {
+		return context.WithTimeout(ctx, commandTimeout)
+	}
+	return context.WithCancel(ctx)
 }
 
-// collectStatistics collects additional statistics for the schema
-func collectStatistics(ctx context.Context, reader database.SchemaReader, schema *models.Schema) error {
-	// Add overall statistics if requested
-	if withStats {
-		totalColumns := 0
-		for _, table := range schema.Tables {
-			totalColumns += len(table.Columns)
-		}
+// readDatabase reads every schema matching pattern into a database snapshot,
+// reporting progress on stderr
+func readDatabase(ctx context.Context, reader schemalyzer.Reader, pattern string, onlyTables bool) (*models.Database, error) {
+	fmt.Fprintf(os.Stderr, "Reading schemas matching: %s\n", pattern)
+	db, err := schemalyzer.ReadDatabase(ctx, reader, pattern, schemalyzer.ReadOptions{
+		TablesOnly:  onlyTables,
+		Concurrency: schemaConcurrency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Read %d schemas\n", len(db.Schemas))
+	return db, nil
+}
 
-		schema.Stats = &models.SchemaStats{
-			TableCount:   len(schema.Tables),
-			ViewCount:    len(schema.Views),
-			TotalColumns: totalColumns,
-			IndexCount:   len(schema.Indexes),
-			GeneratedAt:  time.Now(),
-		}
+// statisticsOptions builds the statistics selection from the export flags
+func statisticsOptions() schemalyzer.StatisticsOptions {
+	return schemalyzer.StatisticsOptions{
+		Summary:    withStats,
+		RowCounts:  withRowCount,
+		Samples:    withSamples,
+		SampleSize: sampleSize,
 	}
+}
 
-	// Collect row counts and samples if requested
-	if withRowCount || withSamples {
-		statsReader, ok := reader.(database.StatisticsReader)
+// expandIgnorePatterns reads the @file entries of --ignore, such as the
+// file written by compare --tui, as one pattern per line
+func expandIgnorePatterns(patterns []string) ([]string, error) {
+	var expanded []string
+	for _, pattern := range patterns {
+		file, ok := strings.CutPrefix(pattern, "@")
 		if !ok {
-			return fmt.Errorf("statistics not supported for this database type")
+			expanded = append(expanded, pattern)
+			continue
 		}
 
-		for i := range schema.Tables {
-			table := &schema.Tables[i]
-
-			// Get row count
-			if withRowCount {
-				count, err := statsReader.GetTableRowCount(ctx, schema.Name, table.Name)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to get row count for table %s: %v\n", table.Name, err)
-				} else {
-					table.RowCount = &count
-				}
-			}
-
-			// Get sample values
-			if withSamples {
-				for j := range table.Columns {
-					column := &table.Columns[j]
-					samples, err := statsReader.GetColumnSamples(ctx, schema.Name, table.Name, column.Name, sampleSize)
-					if err != nil {
-						// Silently skip columns where we can't get samples
-						continue
-					}
-					column.Samples = samples
-				}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ignore patterns: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				expanded = append(expanded, line)
 			}
 		}
 	}
+	return expanded, nil
+}
 
-	return nil
-}
\ No newline at end of file
+// renderResult formats a comparison result with --template when given,
+// otherwise in --format
+func renderResult(result *models.ComparisonResult) ([]byte, error) {
+	if templateFile != "" {
+		tmpl, err := schemalyzer.LoadTemplate(templateFile)
+		if err != nil {
+			return nil, err
+		}
+		return tmpl.ExecuteComparison(result)
+	}
+	return schemalyzer.FormatResultWithOptions(result, schemalyzer.OutputFormat(outputFormat), schemalyzer.FormatOptions{MaxBytes: outputMaxBytes})
+}
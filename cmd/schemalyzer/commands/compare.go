@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"os"
 	
-	"github.com/nechja/schemalyzer/internal/compare"
-	"github.com/nechja/schemalyzer/internal/output"
+	"github.com/nechja/schemalyzer/internal/tui"
 	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
@@ -20,12 +20,17 @@ var (
 	targetSchema string
 	outputFormat string
 	outputFile   string
+	outputMaxBytes int
+	templateFile   string
 	ignorePatterns []string
+	schemaPattern  string
 	tablesOnly   bool
 	withStats    bool
 	withRowCount bool
 	withSamples  bool
 	sampleSize   int
+	browse       bool
+	ignoreOut    string
 )
 
 var compareCmd = &cobra.Command{
@@ -42,89 +47,82 @@ func init() {
 	compareCmd.Flags().StringVar(&targetType, "target-type", "", "Target database type (postgresql, mysql, oracle)")
 	compareCmd.Flags().StringVar(&targetConn, "target-conn", "", "Target database connection string")
 	compareCmd.Flags().StringVar(&targetSchema, "target-schema", "", "Target schema name")
-	compareCmd.Flags().StringVar(&outputFormat, "format", "text", "Output format (json, yaml, text, summary)")
+	compareCmd.Flags().StringVar(&schemaPattern, "schemas", "", "Compare every schema matching a glob (e.g. '*', 'tenant_*') in both databases")
+	compareCmd.Flags().StringVar(&outputFormat, "format", "text", "Output format (json, yaml, text, summary, html, junit, sarif, markdown, json-tree, yaml-tree)")
+	compareCmd.Flags().StringVar(&templateFile, "template", "", "Render the result with a Go text/template or html/template file instead of --format")
+	compareCmd.Flags().IntVar(&outputMaxBytes, "max-size", schemalyzer.DefaultMarkdownMaxBytes, "Truncate markdown reports to this many bytes, e.g. for pull request comment limits")
 	compareCmd.Flags().StringVar(&outputFile, "output", "", "Output file path (default: stdout)")
-	compareCmd.Flags().StringSliceVar(&ignorePatterns, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit')")
+	compareCmd.Flags().StringSliceVar(&ignorePatterns, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit'); @file reads one per line")
 	compareCmd.Flags().BoolVar(&tablesOnly, "tables-only", false, "Compare only tables and their structure (no procedures, functions, triggers)")
+	compareCmd.Flags().BoolVar(&browse, "tui", false, "Browse the differences in an interactive terminal UI")
+	compareCmd.Flags().StringVar(&ignoreOut, "ignore-out", "schemalyzer.ignore", "File the terminal UI writes ignore patterns to, for use with --ignore @file")
 	
 	_ = compareCmd.MarkFlagRequired("source-type")
 	_ = compareCmd.MarkFlagRequired("source-conn")
-	_ = compareCmd.MarkFlagRequired("source-schema")
 	_ = compareCmd.MarkFlagRequired("target-type")
 	_ = compareCmd.MarkFlagRequired("target-conn")
-	_ = compareCmd.MarkFlagRequired("target-schema")
+	compareCmd.MarkFlagsOneRequired("source-schema", "schemas")
+	compareCmd.MarkFlagsOneRequired("target-schema", "schemas")
+	compareCmd.MarkFlagsMutuallyExclusive("source-schema", "schemas")
+	compareCmd.MarkFlagsMutuallyExclusive("target-schema", "schemas")
+	compareCmd.MarkFlagsMutuallyExclusive("template", "format")
+	compareCmd.MarkFlagsMutuallyExclusive("tui", "format")
+	compareCmd.MarkFlagsMutuallyExclusive("tui", "template")
+	compareCmd.MarkFlagsMutuallyExclusive("tui", "output")
 }
 
 func runCompare(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	
-	// Create source reader
-	sourceReader, err := createReader(sourceType)
-	if err != nil {
-		return fmt.Errorf("failed to create source reader: %w", err)
+	// Fail before reading anything when there is no terminal to browse on
+	if browse {
+		if err := tui.CheckTerminal(os.Stdin, os.Stdout); err != nil {
+			return err
+		}
 	}
-	defer sourceReader.Close()
-	
+
 	// Connect to source
-	if err := sourceReader.Connect(ctx, sourceConn); err != nil {
-		return fmt.Errorf("failed to connect to source database: %w", err)
-	}
-	
-	// Get source schema
-	fmt.Fprintf(os.Stderr, "Reading source schema: %s\n", sourceSchema)
-	sourceSchemaData, err := sourceReader.GetSchema(ctx, sourceSchema)
+	sourceReader, err := openReader(ctx, sourceType, sourceConn)
 	if err != nil {
-		return fmt.Errorf("failed to read source schema: %w", err)
+		return fmt.Errorf("source: %w", err)
 	}
+	defer sourceReader.Close()
 	
-	// Create target reader
-	targetReader, err := createReader(targetType)
+	// Connect to target
+	targetReader, err := openReader(ctx, targetType, targetConn)
 	if err != nil {
-		return fmt.Errorf("failed to create target reader: %w", err)
+		return fmt.Errorf("target: %w", err)
 	}
 	defer targetReader.Close()
 	
-	// Connect to target
-	if err := targetReader.Connect(ctx, targetConn); err != nil {
-		return fmt.Errorf("failed to connect to target database: %w", err)
-	}
-	
-	// Get target schema
-	fmt.Fprintf(os.Stderr, "Reading target schema: %s\n", targetSchema)
-	targetSchemaData, err := targetReader.GetSchema(ctx, targetSchema)
+	ignore, err := expandIgnorePatterns(ignorePatterns)
 	if err != nil {
-		return fmt.Errorf("failed to read target schema: %w", err)
+		return err
 	}
-	
-	// Filter schemas if --tables-only is set
-	if tablesOnly {
-		sourceSchemaData = filterTablesOnly(sourceSchemaData)
-		targetSchemaData = filterTablesOnly(targetSchemaData)
+	opts := schemalyzer.CompareOptions{
+		Ignore:     ignore,
+		TablesOnly: tablesOnly,
 	}
 	
-	// Compare schemas
-	fmt.Fprintf(os.Stderr, "Comparing schemas...\n")
-	
-	var comparer *compare.Comparer
-	if len(ignorePatterns) > 0 {
-		ignoreConfig, err := models.NewIgnoreConfig(ignorePatterns)
-		if err != nil {
-			return fmt.Errorf("failed to parse ignore patterns: %w", err)
-		}
-		comparer = compare.NewComparerWithIgnore(ignoreConfig)
+	var result *models.ComparisonResult
+	if schemaPattern != "" {
+		result, err = compareDatabases(ctx, opts, sourceReader, targetReader)
 	} else {
-		comparer = compare.NewComparer()
+		result, err = compareSchemas(ctx, opts, sourceReader, targetReader)
+	}
+	if err != nil {
+		return err
 	}
 	
-	result := comparer.Compare(sourceSchemaData, targetSchemaData)
-	result.SourceDatabase = fmt.Sprintf("%s://%s", sourceType, sourceSchema)
-	result.TargetDatabase = fmt.Sprintf("%s://%s", targetType, targetSchema)
-	
+	if browse {
+		return tui.Run(tui.NewBrowser(result, ignoreOut), os.Stdin, os.Stdout)
+	}
+
 	// Format output
-	formatter := output.NewFormatter(output.OutputFormat(outputFormat))
-	outputData, err := formatter.Format(result)
+	outputData, err := renderResult(result)
 	if err != nil {
-		return fmt.Errorf("failed to format output: %w", err)
+		return err
 	}
 	
 	// Write output
@@ -139,10 +137,55 @@ func runCompare(cmd *cobra.Command, args []string) error {
 	
 	// Exit with code 2 if differences found (informational, not an error)
 	if len(result.Differences) > 0 {
-		fmt.Fprintf(os.Stderr, "Found %d differences between schemas\n", len(result.Differences))
-		os.Exit(ExitCodeMismatch)
+		return mismatchError(cmd, "Found %d differences between schemas", len(result.Differences))
 	}
 
 	fmt.Fprintf(os.Stderr, "Schemas are identical\n")
 	return nil
-}
\ No newline at end of file
+}
+
+func compareSchemas(ctx context.Context, opts schemalyzer.CompareOptions, sourceReader, targetReader schemalyzer.Reader) (*models.ComparisonResult, error) {
+	// Get source schema
+	fmt.Fprintf(os.Stderr, "Reading source schema: %s\n", sourceSchema)
+	sourceSchemaData, err := schemalyzer.ReadSchema(ctx, sourceReader, sourceSchema, schemalyzer.ReadOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+
+	// Get target schema
+	fmt.Fprintf(os.Stderr, "Reading target schema: %s\n", targetSchema)
+	targetSchemaData, err := schemalyzer.ReadSchema(ctx, targetReader, targetSchema, schemalyzer.ReadOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Comparing schemas...\n")
+	result, err := schemalyzer.Compare(sourceSchemaData, targetSchemaData, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.SourceDatabase = fmt.Sprintf("%s://%s", sourceType, sourceSchema)
+	result.TargetDatabase = fmt.Sprintf("%s://%s", targetType, targetSchema)
+	return result, nil
+}
+
+func compareDatabases(ctx context.Context, opts schemalyzer.CompareOptions, sourceReader, targetReader schemalyzer.Reader) (*models.ComparisonResult, error) {
+	sourceDB, err := readDatabase(ctx, sourceReader, schemaPattern, false)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+
+	targetDB, err := readDatabase(ctx, targetReader, schemaPattern, false)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Comparing databases...\n")
+	result, err := schemalyzer.CompareDatabases(sourceDB, targetDB, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.SourceDatabase = fmt.Sprintf("%s://%s", sourceType, schemaPattern)
+	result.TargetDatabase = fmt.Sprintf("%s://%s", targetType, schemaPattern)
+	return result, nil
+}
@@ -3,17 +3,21 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
-	"github.com/nechja/schemalyzer/internal/fingerprint"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
 var (
 	sourceFingerprint string
 	targetFingerprint string
+	sourceManifest    string
+	targetManifest    string
 	cfSourceType      string
 	cfSourceConn      string
 	cfSourceSchema    string
@@ -27,13 +31,17 @@ var (
 var compareFingerprintsCmd = &cobra.Command{
 	Use:   "compare-fingerprints",
 	Short: "Compare fingerprints of two database schemas",
-	Long:  `Quickly compare two database schemas by generating and comparing their SHA256 fingerprints`,
-	RunE:  runCompareFingerprints,
+	Long: `Quickly compare two database schemas by generating and comparing their SHA256 fingerprints.
+When both sides are manifests (from 'fingerprint --manifest') or live databases,
+the objects that changed are listed as well.`,
+	RunE: runCompareFingerprints,
 }
 
 func init() {
 	compareFingerprintsCmd.Flags().StringVar(&sourceFingerprint, "source-hash", "", "Pre-computed source fingerprint (optional)")
 	compareFingerprintsCmd.Flags().StringVar(&targetFingerprint, "target-hash", "", "Pre-computed target fingerprint (optional)")
+	compareFingerprintsCmd.Flags().StringVar(&sourceManifest, "source-manifest", "", "Source manifest file written by 'fingerprint --manifest' (optional)")
+	compareFingerprintsCmd.Flags().StringVar(&targetManifest, "target-manifest", "", "Target manifest file written by 'fingerprint --manifest' (optional)")
 	compareFingerprintsCmd.Flags().StringVar(&cfSourceType, "source-type", "", "Source database type")
 	compareFingerprintsCmd.Flags().StringVar(&cfSourceConn, "source-conn", "", "Source database connection")
 	compareFingerprintsCmd.Flags().StringVar(&cfSourceSchema, "source-schema", "", "Source schema name")
@@ -42,51 +50,98 @@ func init() {
 	compareFingerprintsCmd.Flags().StringVar(&cfTargetSchema, "target-schema", "", "Target schema name")
 	compareFingerprintsCmd.Flags().BoolVar(&cfJSON, "json", false, "Output in JSON format")
 	compareFingerprintsCmd.Flags().BoolVar(&cfTablesOnly, "tables-only", false, "Include only tables in fingerprints")
+	addHashOptionFlags(compareFingerprintsCmd)
+	compareFingerprintsCmd.MarkFlagsMutuallyExclusive("source-hash", "source-manifest")
+	compareFingerprintsCmd.MarkFlagsMutuallyExclusive("target-hash", "target-manifest")
+}
+
+// fingerprintSide is one side of a comparison: a bare hash, or a hash
+// together with the manifest it came from
+type fingerprintSide struct {
+	hash     string
+	parsed   schemalyzer.ParsedFingerprint
+	manifest *schemalyzer.Manifest
 }
 
 func runCompareFingerprints(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	
-	var sourceHash, targetHash string
-	var err error
+	options, err := fingerprintOptions(cfTablesOnly)
+	if err != nil {
+		return err
+	}
 	
-	if sourceFingerprint != "" {
-		sourceHash = sourceFingerprint
-	} else {
-		if cfSourceType == "" || cfSourceConn == "" || cfSourceSchema == "" {
-			return fmt.Errorf("source database connection details required when source-hash not provided")
+	source, err := loadFingerprint("source", sourceFingerprint, sourceManifest)
+	if err != nil {
+		return err
+	}
+	
+	target, err := loadFingerprint("target", targetFingerprint, targetManifest)
+	if err != nil {
+		return err
+	}
+	
+	// A live database is hashed with the settings of a pre-computed
+	// fingerprint on the other side, so the two stay comparable
+	for _, stored := range []*fingerprintSide{source, target} {
+		if stored == nil || stored.parsed.Options.String() == options.Hash.String() {
+			continue
 		}
-		sourceHash, err = generateFingerprint(ctx, cfSourceType, cfSourceConn, cfSourceSchema, cfTablesOnly)
-		if err != nil {
-			return fmt.Errorf("failed to generate source fingerprint: %w", err)
+		if source == nil || target == nil {
+			fmt.Fprintf(os.Stderr, "Hashing live database with the stored fingerprint's settings: %s\n", stored.parsed.Options)
 		}
+		options.Hash = stored.parsed.Options
+		break
 	}
 	
-	if targetFingerprint != "" {
-		targetHash = targetFingerprint
-	} else {
-		if cfTargetType == "" || cfTargetConn == "" || cfTargetSchema == "" {
-			return fmt.Errorf("target database connection details required when target-hash not provided")
+	if source == nil {
+		if source, err = liveFingerprint(ctx, "source", cfSourceType, cfSourceConn, cfSourceSchema, options); err != nil {
+			return err
 		}
-		targetHash, err = generateFingerprint(ctx, cfTargetType, cfTargetConn, cfTargetSchema, cfTablesOnly)
-		if err != nil {
-			return fmt.Errorf("failed to generate target fingerprint: %w", err)
+	}
+	if target == nil {
+		if target, err = liveFingerprint(ctx, "target", cfTargetType, cfTargetConn, cfTargetSchema, options); err != nil {
+			return err
 		}
 	}
 	
-	match := sourceHash == targetHash
+	match, err := schemalyzer.CompareFingerprints(source.hash, target.hash)
+	if err != nil {
+		var incompatible *schemalyzer.IncompatibleFingerprintsError
+		if errors.As(err, &incompatible) && incompatible.SourceVersion == incompatible.TargetVersion {
+			return fmt.Errorf("%w; regenerate one side with matching --include-comments, --ignore-column-order, --bodies and --include-stats flags", err)
+		}
+		return err
+	}
+	
+	// Manifests on both sides pinpoint the changed objects offline
+	var differences []models.Difference
+	if !match && source.manifest != nil && target.manifest != nil {
+		differences, err = schemalyzer.DiffManifests(source.manifest, target.manifest)
+		if err != nil {
+			return fmt.Errorf("failed to diff manifests: %w", err)
+		}
+	}
 	
 	if cfJSON {
+		type changedObject struct {
+			Change     models.DifferenceType `json:"change"`
+			ObjectType string                `json:"object_type"`
+			ObjectName string                `json:"object_name"`
+		}
+		
 		output := struct {
-			SourceFingerprint string    `json:"source_fingerprint"`
-			TargetFingerprint string    `json:"target_fingerprint"`
-			Match             bool      `json:"match"`
-			Timestamp         time.Time `json:"timestamp"`
-			SourceSchema      string    `json:"source_schema,omitempty"`
-			TargetSchema      string    `json:"target_schema,omitempty"`
+			SourceFingerprint string          `json:"source_fingerprint"`
+			TargetFingerprint string          `json:"target_fingerprint"`
+			Match             bool            `json:"match"`
+			Timestamp         time.Time       `json:"timestamp"`
+			SourceSchema      string          `json:"source_schema,omitempty"`
+			TargetSchema      string          `json:"target_schema,omitempty"`
+			Changed           []changedObject `json:"changed,omitempty"`
 		}{
-			SourceFingerprint: sourceHash,
-			TargetFingerprint: targetHash,
+			SourceFingerprint: source.hash,
+			TargetFingerprint: target.hash,
 			Match:             match,
 			Timestamp:         time.Now(),
 		}
@@ -97,6 +152,13 @@ func runCompareFingerprints(cmd *cobra.Command, args []string) error {
 		if cfTargetSchema != "" {
 			output.TargetSchema = fmt.Sprintf("%s://%s", cfTargetType, cfTargetSchema)
 		}
+		for _, diff := range differences {
+			output.Changed = append(output.Changed, changedObject{
+				Change:     diff.Type,
+				ObjectType: diff.ObjectType,
+				ObjectName: diff.ObjectName,
+			})
+		}
 		
 		jsonData, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
@@ -104,44 +166,97 @@ func runCompareFingerprints(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Println(string(jsonData))
 	} else {
-		fmt.Printf("Source Fingerprint: %s\n", sourceHash)
-		fmt.Printf("Target Fingerprint: %s\n", targetHash)
+		fmt.Printf("Source Fingerprint: %s\n", source.hash)
+		fmt.Printf("Target Fingerprint: %s\n", target.hash)
 		if match {
 			fmt.Println("\n✓ Schemas match!")
 		} else {
 			fmt.Println("\n✗ Schemas differ")
-			fmt.Println("\nRun 'schemalyzer compare' for detailed differences")
+			if len(differences) > 0 {
+				fmt.Printf("\nChanged objects (%d):\n", len(differences))
+				for _, diff := range differences {
+					fmt.Printf("  %s %s: %s\n", changeMarker(diff.Type), diff.ObjectType, diff.ObjectName)
+				}
+			} else {
+				fmt.Println("\nRun 'schemalyzer compare' for detailed differences")
+			}
 		}
 	}
 
 	if !match {
 		// Exit with code 2 if schemas don't match
-		os.Exit(ExitCodeMismatch)
+		return mismatchError(cmd, "")
 	}
 
 	return nil
 }
 
-func generateFingerprint(ctx context.Context, dbType, conn, schema string, tablesOnly bool) (string, error) {
-	reader, err := createReader(dbType)
-	if err != nil {
-		return "", fmt.Errorf("failed to create reader: %w", err)
+// loadFingerprint returns one side's pre-computed fingerprint from a hash
+// or a manifest file, or nil when that side is a live database
+func loadFingerprint(side, hash, manifestPath string) (*fingerprintSide, error) {
+	var stored fingerprintSide
+	switch {
+	case hash != "":
+		stored.hash = hash
+	case manifestPath != "":
+		manifest, err := schemalyzer.LoadManifest(manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		stored.hash = manifest.Fingerprint
+		stored.manifest = manifest
+	default:
+		return nil, nil
 	}
-	defer reader.Close()
 	
-	if err := reader.Connect(ctx, conn); err != nil {
-		return "", fmt.Errorf("failed to connect to database: %w", err)
+	parsed, err := schemalyzer.ParseFingerprint(stored.hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s fingerprint: %w", side, err)
+	}
+	stored.parsed = parsed
+	return &stored, nil
+}
+
+// liveFingerprint reads one side's schema and hashes it into a manifest,
+// so differences can be listed
+func liveFingerprint(ctx context.Context, side, dbType, conn, schema string, options schemalyzer.FingerprintOptions) (*fingerprintSide, error) {
+	if dbType == "" || conn == "" || schema == "" {
+		return nil, fmt.Errorf("%s database connection details required when %s-hash or %s-manifest not provided", side, side, side)
 	}
+	manifest, err := generateManifest(ctx, dbType, conn, schema, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s fingerprint: %w", side, err)
+	}
+	return &fingerprintSide{hash: manifest.Fingerprint, manifest: manifest}, nil
+}
+
+func generateManifest(ctx context.Context, dbType, conn, schema string, options schemalyzer.FingerprintOptions) (*schemalyzer.Manifest, error) {
+	reader, err := openReader(ctx, dbType, conn)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
 	
-	schemaData, err := reader.GetSchema(ctx, schema)
+	schemaData, err := schemalyzer.ReadSchema(ctx, reader, schema, schemalyzer.ReadOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to read schema: %w", err)
+		return nil, err
 	}
 	
-	if tablesOnly {
-		schemaData = filterTablesOnly(schemaData)
+	if err := collectHashStatistics(ctx, reader, schemaData, options); err != nil {
+		return nil, err
 	}
 	
-	hasher := fingerprint.NewHasher()
-	return hasher.GenerateFingerprint(schemaData)
-}
\ No newline at end of file
+	return schemalyzer.FingerprintManifest(schemaData, options)
+}
+
+// changeMarker is the +/-/~ prefix used for a difference in listings
+func changeMarker(change models.DifferenceType) string {
+	switch change {
+	case models.Added:
+		return "+"
+	case models.Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
@@ -1,13 +1,11 @@
 package commands
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	
-	"github.com/nechja/schemalyzer/internal/docs"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
@@ -24,63 +22,84 @@ func init() {
 	documentCmd.Flags().StringVar(&sourceType, "type", "", "Database type (postgresql, mysql, oracle)")
 	documentCmd.Flags().StringVar(&sourceConn, "conn", "", "Database connection string")
 	documentCmd.Flags().StringVar(&sourceSchema, "schema", "", "Schema name to document")
-	documentCmd.Flags().StringVar(&docFormat, "format", "markdown", "Documentation format (markdown, plantuml, mermaid, graphviz, d2)")
-	documentCmd.Flags().StringVar(&outputFile, "output", "", "Output file path (required)")
+	documentCmd.Flags().StringVar(&docFormat, "format", "markdown", "Documentation format (markdown, plantuml, mermaid, graphviz, d2, html-site)")
+	documentCmd.Flags().StringVar(&templateFile, "template", "", "Render the schema with a Go text/template or html/template file instead of --format")
+	documentCmd.Flags().StringVar(&outputFile, "output", "", "Output file path, or directory for html-site (required)")
 	documentCmd.Flags().BoolVar(&tablesOnly, "tables-only", false, "Document only tables and their structure (no procedures, functions, triggers)")
+	documentCmd.Flags().BoolVar(&withStats, "with-stats", false, "Include schema statistics (html-site)")
+	documentCmd.Flags().BoolVar(&withRowCount, "with-row-count", false, "Include row counts for each table (html-site)")
+	documentCmd.Flags().BoolVar(&withSamples, "with-samples", false, "Include sample values for each column (html-site)")
+	documentCmd.Flags().IntVar(&sampleSize, "sample-size", 3, "Number of sample values to collect per column (default: 3)")
 	_ = documentCmd.MarkFlagRequired("type")
 	_ = documentCmd.MarkFlagRequired("conn")
 	_ = documentCmd.MarkFlagRequired("schema")
 	_ = documentCmd.MarkFlagRequired("output")
+	documentCmd.MarkFlagsMutuallyExclusive("template", "format")
 }
 
 func runDocument(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	
-	// Create reader
-	reader, err := createReader(sourceType)
+	format, err := schemalyzer.ParseDocumentFormat(docFormat)
 	if err != nil {
-		return fmt.Errorf("failed to create reader: %w", err)
+		return err
+	}
+	
+	// Parse the template up front so a broken one fails before reading
+	var tmpl *schemalyzer.Template
+	if templateFile != "" {
+		if tmpl, err = schemalyzer.LoadTemplate(templateFile); err != nil {
+			return err
+		}
 	}
-	defer reader.Close()
 	
 	// Connect to database
-	if err := reader.Connect(ctx, sourceConn); err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+	reader, err := openReader(ctx, sourceType, sourceConn)
+	if err != nil {
+		return err
 	}
+	defer reader.Close()
 	
 	// Get schema
 	fmt.Fprintf(os.Stderr, "Reading schema: %s\n", sourceSchema)
-	schemaData, err := reader.GetSchema(ctx, sourceSchema)
+	schemaData, err := schemalyzer.ReadSchema(ctx, reader, sourceSchema, schemalyzer.ReadOptions{TablesOnly: tablesOnly})
 	if err != nil {
-		return fmt.Errorf("failed to read schema: %w", err)
+		return err
 	}
 	
-	// Filter schema if --tables-only is set
-	if tablesOnly {
-		schemaData = filterTablesOnly(schemaData)
+	// Collect statistics if requested
+	if withStats || withRowCount || withSamples {
+		if err := schemalyzer.CollectStatistics(ctx, reader, schemaData, statisticsOptions()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to collect some statistics: %v\n", err)
+		}
 	}
 	
-	// Generate documentation
-	var generator docs.DocumentGenerator
-	switch strings.ToLower(docFormat) {
-	case "plantuml":
-		generator = docs.NewPlantUMLGenerator()
-	case "mermaid":
-		generator = docs.NewMermaidGenerator()
-	case "graphviz", "dot":
-		generator = docs.NewGraphVizGenerator()
-	case "d2":
-		generator = docs.NewD2Generator()
-	case "markdown", "md":
-		generator = docs.NewMarkdownDocGenerator()
-	default:
-		return fmt.Errorf("unsupported documentation format: %s", docFormat)
+	if tmpl == nil && format == schemalyzer.DocumentHTMLSite {
+		fmt.Fprintf(os.Stderr, "Generating %s documentation...\n", format)
+		if err := schemalyzer.WriteSite(schemaData, outputFile, schemalyzer.DocumentOptions{Format: format}); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Documentation site written to: %s\n", filepath.Join(outputFile, "index.html"))
+		return nil
 	}
 	
-	fmt.Fprintf(os.Stderr, "Generating %s documentation...\n", docFormat)
-	docContent, err := generator.Generate(schemaData)
-	if err != nil {
-		return fmt.Errorf("failed to generate documentation: %w", err)
+	// Generate documentation
+	var docContent string
+	if tmpl != nil {
+		fmt.Fprintf(os.Stderr, "Rendering template %s...\n", templateFile)
+		data, err := tmpl.ExecuteSchema(schemaData)
+		if err != nil {
+			return err
+		}
+		docContent = string(data)
+		format = ""
+	} else {
+		fmt.Fprintf(os.Stderr, "Generating %s documentation...\n", docFormat)
+		docContent, err = schemalyzer.GenerateDocs(schemaData, schemalyzer.DocumentOptions{Format: format})
+		if err != nil {
+			return err
+		}
 	}
 	
 	// Write to file
@@ -95,20 +114,20 @@ func runDocument(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(os.Stderr, "Documentation written to: %s\n", outputFile)
 	
 	// Provide usage instructions based on format
-	switch strings.ToLower(docFormat) {
-	case "plantuml":
+	switch format {
+	case schemalyzer.DocumentPlantUML:
 		fmt.Fprintf(os.Stderr, "\nTo generate an image:\n")
 		fmt.Fprintf(os.Stderr, "  plantuml %s\n", outputFile)
 		fmt.Fprintf(os.Stderr, "  # or use online: http://www.plantuml.com/plantuml\n")
-	case "graphviz", "dot":
+	case schemalyzer.DocumentGraphViz:
 		fmt.Fprintf(os.Stderr, "\nTo generate an image:\n")
 		fmt.Fprintf(os.Stderr, "  dot -Tpng %s -o schema.png\n", outputFile)
 		fmt.Fprintf(os.Stderr, "  dot -Tsvg %s -o schema.svg\n", outputFile)
-	case "d2":
+	case schemalyzer.DocumentD2:
 		fmt.Fprintf(os.Stderr, "\nTo generate an image:\n")
 		fmt.Fprintf(os.Stderr, "  d2 %s schema.png\n", outputFile)
 		fmt.Fprintf(os.Stderr, "  # Install: https://d2lang.com/tour/install\n")
-	case "mermaid":
+	case schemalyzer.DocumentMermaid:
 		fmt.Fprintf(os.Stderr, "\nView in GitHub/GitLab or use:\n")
 		fmt.Fprintf(os.Stderr, "  mmdc -i %s -o schema.png\n", outputFile)
 		fmt.Fprintf(os.Stderr, "  # Install: npm install -g @mermaid-js/mermaid-cli\n")
@@ -1,6 +1,10 @@
 package commands
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
 
 // ExitError is an error that indicates the command should exit with a specific code
 type ExitError struct {
@@ -31,4 +35,13 @@ func NewExitErrorf(code int, format string, args ...interface{}) *ExitError {
 const (
 	// ExitCodeMismatch is returned when schemas don't match
 	ExitCodeMismatch = 2
-)
\ No newline at end of file
+)
+
+// mismatchError ends cmd with ExitCodeMismatch once its output is written.
+// Differences are a result rather than a failure, so cobra prints neither
+// usage nor "Error:"; Execute prints the message instead when not empty.
+func mismatchError(cmd *cobra.Command, format string, args ...interface{}) *ExitError {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return NewExitErrorf(ExitCodeMismatch, format, args...)
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"os"
 	
-	"github.com/nechja/schemalyzer/internal/schema"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +20,7 @@ func init() {
 	exportCmd.Flags().StringVar(&sourceType, "type", "", "Database type (postgresql, mysql, oracle)")
 	exportCmd.Flags().StringVar(&sourceConn, "conn", "", "Database connection string")
 	exportCmd.Flags().StringVar(&sourceSchema, "schema", "", "Schema name to export")
+	exportCmd.Flags().StringVar(&schemaPattern, "schemas", "", "Export every schema matching a glob (e.g. '*', 'tenant_*') as one database snapshot")
 	exportCmd.Flags().StringVar(&outputFile, "output", "", "Output file path (required)")
 	exportCmd.Flags().BoolVar(&tablesOnly, "tables-only", false, "Export only tables and their structure (no procedures, functions, triggers)")
 	exportCmd.Flags().BoolVar(&withStats, "with-stats", false, "Include schema statistics (table count, column count, etc.)")
@@ -28,51 +29,69 @@ func init() {
 	exportCmd.Flags().IntVar(&sampleSize, "sample-size", 3, "Number of sample values to collect per column (default: 3)")
 	_ = exportCmd.MarkFlagRequired("type")
 	_ = exportCmd.MarkFlagRequired("conn")
-	_ = exportCmd.MarkFlagRequired("schema")
+	exportCmd.MarkFlagsOneRequired("schema", "schemas")
+	exportCmd.MarkFlagsMutuallyExclusive("schema", "schemas")
 	_ = exportCmd.MarkFlagRequired("output")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
-	// Create reader
-	reader, err := createReader(sourceType)
+	// Connect
+	reader, err := openReader(ctx, sourceType, sourceConn)
 	if err != nil {
-		return fmt.Errorf("failed to create reader: %w", err)
+		return err
 	}
 	defer reader.Close()
 
-	// Connect
-	if err := reader.Connect(ctx, sourceConn); err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+	if schemaPattern != "" {
+		return exportDatabase(ctx, reader)
 	}
 
 	// Get schema
 	fmt.Fprintf(os.Stderr, "Reading schema: %s\n", sourceSchema)
-	schemaData, err := reader.GetSchema(ctx, sourceSchema)
+	schemaData, err := schemalyzer.ReadSchema(ctx, reader, sourceSchema, schemalyzer.ReadOptions{TablesOnly: tablesOnly})
 	if err != nil {
-		return fmt.Errorf("failed to read schema: %w", err)
-	}
-
-	// Filter schema if --tables-only is set
-	if tablesOnly {
-		schemaData = filterTablesOnly(schemaData)
+		return err
 	}
 
 	// Collect statistics if requested
 	if withStats || withRowCount || withSamples {
-		if err := collectStatistics(ctx, reader, schemaData); err != nil {
+		if err := schemalyzer.CollectStatistics(ctx, reader, schemaData, statisticsOptions()); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to collect some statistics: %v\n", err)
 			// Continue even if statistics collection fails
 		}
 	}
 
 	// Save to file
-	loader := schema.NewLoader()
-	if err := loader.SaveToFile(schemaData, outputFile); err != nil {
-		return fmt.Errorf("failed to save schema: %w", err)
+	if err := schemalyzer.SaveSchema(schemaData, outputFile); err != nil {
+		return err
 	}
 
 	fmt.Fprintf(os.Stderr, "Schema exported to: %s\n", outputFile)
 	return nil
-}
\ No newline at end of file
+}
+
+// exportDatabase exports every schema matching --schemas into one snapshot file
+func exportDatabase(ctx context.Context, reader schemalyzer.Reader) error {
+	db, err := readDatabase(ctx, reader, schemaPattern, tablesOnly)
+	if err != nil {
+		return err
+	}
+
+	if withStats || withRowCount || withSamples {
+		for i := range db.Schemas {
+			if err := schemalyzer.CollectStatistics(ctx, reader, &db.Schemas[i], statisticsOptions()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to collect some statistics for schema %s: %v\n", db.Schemas[i].Name, err)
+			}
+		}
+	}
+
+	if err := schemalyzer.SaveDatabase(db, outputFile); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Database snapshot with %d schemas exported to: %s\n", len(db.Schemas), outputFile)
+	return nil
+}
@@ -7,7 +7,8 @@ import (
 	"os"
 	"time"
 
-	"github.com/nechja/schemalyzer/internal/fingerprint"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
@@ -15,9 +16,13 @@ var (
 	fingerprintType   string
 	fingerprintConn   string
 	fingerprintSchema string
+	fingerprintSchemas string
 	fingerprintVerbose bool
 	fingerprintJSON   bool
 	fingerprintTablesOnly bool
+	fingerprintManifest bool
+	hashOptions       schemalyzer.HashOptions
+	hashBodies        string
 )
 
 var fingerprintCmd = &cobra.Command{
@@ -31,43 +36,61 @@ func init() {
 	fingerprintCmd.Flags().StringVar(&fingerprintType, "type", "", "Database type (postgresql, mysql, oracle)")
 	fingerprintCmd.Flags().StringVar(&fingerprintConn, "conn", "", "Database connection string")
 	fingerprintCmd.Flags().StringVar(&fingerprintSchema, "schema", "", "Schema name")
+	fingerprintCmd.Flags().StringVar(&fingerprintSchemas, "schemas", "", "Fingerprint every schema matching a glob as one database snapshot")
 	fingerprintCmd.Flags().BoolVar(&fingerprintVerbose, "verbose", false, "Show detailed information about what's included in the hash")
 	fingerprintCmd.Flags().BoolVar(&fingerprintJSON, "json", false, "Output in JSON format with metadata")
 	fingerprintCmd.Flags().BoolVar(&fingerprintTablesOnly, "tables-only", false, "Include only tables in the fingerprint (no procedures, functions, triggers)")
+	fingerprintCmd.Flags().BoolVar(&fingerprintManifest, "manifest", false, "Output a JSON manifest of per-object hashes for compare-fingerprints")
+	addHashOptionFlags(fingerprintCmd)
 	
 	_ = fingerprintCmd.MarkFlagRequired("type")
 	_ = fingerprintCmd.MarkFlagRequired("conn")
-	_ = fingerprintCmd.MarkFlagRequired("schema")
+	fingerprintCmd.MarkFlagsOneRequired("schema", "schemas")
+	fingerprintCmd.MarkFlagsMutuallyExclusive("schema", "schemas")
+	fingerprintCmd.MarkFlagsMutuallyExclusive("manifest", "json")
 }
 
 func runFingerprint(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	
-	reader, err := createReader(fingerprintType)
+	options, err := fingerprintOptions(fingerprintTablesOnly)
 	if err != nil {
-		return fmt.Errorf("failed to create reader: %w", err)
+		return err
+	}
+	
+	reader, err := openReader(ctx, fingerprintType, fingerprintConn)
+	if err != nil {
+		return err
 	}
 	defer reader.Close()
 	
-	if err := reader.Connect(ctx, fingerprintConn); err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+	if fingerprintSchemas != "" {
+		return runDatabaseFingerprint(ctx, reader, options)
 	}
 	
 	if fingerprintVerbose {
 		fmt.Fprintf(os.Stderr, "Reading schema: %s\n", fingerprintSchema)
 	}
 	
-	schema, err := reader.GetSchema(ctx, fingerprintSchema)
+	schema, err := schemalyzer.ReadSchema(ctx, reader, fingerprintSchema, schemalyzer.ReadOptions{TablesOnly: fingerprintTablesOnly})
 	if err != nil {
-		return fmt.Errorf("failed to read schema: %w", err)
+		return err
 	}
 	
-	if fingerprintTablesOnly {
-		schema = filterTablesOnly(schema)
+	if err := collectHashStatistics(ctx, reader, schema, options); err != nil {
+		return err
 	}
 	
-	hasher := fingerprint.NewHasher().WithVerbose(fingerprintVerbose)
-	hash, err := hasher.GenerateFingerprint(schema)
+	if fingerprintManifest {
+		manifest, err := schemalyzer.FingerprintManifest(schema, options)
+		if err != nil {
+			return fmt.Errorf("failed to generate manifest: %w", err)
+		}
+		return printManifest(manifest)
+	}
+	
+	hash, err := schemalyzer.Fingerprint(schema, options)
 	if err != nil {
 		return fmt.Errorf("failed to generate fingerprint: %w", err)
 	}
@@ -79,6 +102,7 @@ func runFingerprint(cmd *cobra.Command, args []string) error {
 			Schema       string    `json:"schema"`
 			Fingerprint  string    `json:"fingerprint"`
 			Algorithm    string    `json:"algorithm"`
+			Options      string    `json:"options"`
 			Timestamp    time.Time `json:"timestamp"`
 			TablesOnly   bool      `json:"tables_only"`
 			Statistics   struct {
@@ -95,7 +119,8 @@ func runFingerprint(cmd *cobra.Command, args []string) error {
 			DatabaseType: fingerprintType,
 			Schema:       fingerprintSchema,
 			Fingerprint:  hash,
-			Algorithm:    "SHA256",
+			Algorithm:    schemalyzer.FingerprintAlgorithm,
+			Options:      options.Hash.String(),
 			Timestamp:    time.Now(),
 			TablesOnly:   fingerprintTablesOnly,
 		}
@@ -116,7 +141,8 @@ func runFingerprint(cmd *cobra.Command, args []string) error {
 	} else if fingerprintVerbose {
 		fmt.Printf("Database Type: %s\n", fingerprintType)
 		fmt.Printf("Schema: %s\n", fingerprintSchema)
-		fmt.Printf("Algorithm: SHA256\n")
+		fmt.Printf("Algorithm: %s\n", schemalyzer.FingerprintAlgorithm)
+		fmt.Printf("Options: %s\n", options.Hash)
 		fmt.Printf("Tables: %d\n", len(schema.Tables))
 		fmt.Printf("Views: %d\n", len(schema.Views))
 		fmt.Printf("Indexes: %d\n", len(schema.Indexes))
@@ -130,4 +156,115 @@ func runFingerprint(cmd *cobra.Command, args []string) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// runDatabaseFingerprint fingerprints every schema matching --schemas as one snapshot
+func runDatabaseFingerprint(ctx context.Context, reader schemalyzer.Reader, options schemalyzer.FingerprintOptions) error {
+	db, err := schemalyzer.ReadDatabase(ctx, reader, fingerprintSchemas, schemalyzer.ReadOptions{
+		TablesOnly:  fingerprintTablesOnly,
+		Concurrency: schemaConcurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range db.Schemas {
+		if err := collectHashStatistics(ctx, reader, &db.Schemas[i], options); err != nil {
+			return err
+		}
+	}
+
+	if fingerprintManifest {
+		manifest, err := schemalyzer.FingerprintDatabaseManifest(db, options)
+		if err != nil {
+			return fmt.Errorf("failed to generate manifest: %w", err)
+		}
+		return printManifest(manifest)
+	}
+
+	hash, err := schemalyzer.FingerprintDatabase(db, options)
+	if err != nil {
+		return fmt.Errorf("failed to generate fingerprint: %w", err)
+	}
+
+	if fingerprintJSON {
+		schemaNames := make([]string, 0, len(db.Schemas))
+		for _, s := range db.Schemas {
+			schemaNames = append(schemaNames, s.Name)
+		}
+
+		output := struct {
+			DatabaseType string    `json:"database_type"`
+			Schemas      []string  `json:"schemas"`
+			Fingerprint  string    `json:"fingerprint"`
+			Algorithm    string    `json:"algorithm"`
+			Options      string    `json:"options"`
+			Timestamp    time.Time `json:"timestamp"`
+			TablesOnly   bool      `json:"tables_only"`
+		}{
+			DatabaseType: fingerprintType,
+			Schemas:      schemaNames,
+			Fingerprint:  hash,
+			Algorithm:    schemalyzer.FingerprintAlgorithm,
+			Options:      options.Hash.String(),
+			Timestamp:    time.Now(),
+			TablesOnly:   fingerprintTablesOnly,
+		}
+
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	} else if fingerprintVerbose {
+		fmt.Printf("Database Type: %s\n", fingerprintType)
+		fmt.Printf("Schemas: %d\n", len(db.Schemas))
+		fmt.Printf("Algorithm: %s\n", schemalyzer.FingerprintAlgorithm)
+		fmt.Printf("Options: %s\n", options.Hash)
+		fmt.Printf("\nFingerprint: %s\n", hash)
+	} else {
+		fmt.Println(hash)
+	}
+
+	return nil
+}
+
+// printManifest writes a fingerprint manifest to stdout as indented JSON
+func printManifest(manifest *schemalyzer.Manifest) error {
+	jsonData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
+// addHashOptionFlags registers the flags that select what a fingerprint covers
+func addHashOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&hashOptions.IncludeComments, "include-comments", false, "Include table and column comments in the fingerprint")
+	cmd.Flags().BoolVar(&hashOptions.IgnoreColumnOrder, "ignore-column-order", false, "Leave column positions out of the fingerprint")
+	cmd.Flags().StringVar(&hashBodies, "bodies", string(schemalyzer.BodiesRaw), "How routine bodies and view definitions are hashed (raw, normalized, none)")
+	cmd.Flags().BoolVar(&hashOptions.IncludeStatistics, "include-stats", false, "Include table row counts in the fingerprint")
+}
+
+// fingerprintOptions builds the fingerprint settings from the flags
+func fingerprintOptions(tablesOnly bool) (schemalyzer.FingerprintOptions, error) {
+	options := hashOptions
+	options.Bodies = schemalyzer.BodyMode(hashBodies)
+	if err := options.Validate(); err != nil {
+		return schemalyzer.FingerprintOptions{}, err
+	}
+	return schemalyzer.FingerprintOptions{TablesOnly: tablesOnly, Hash: options}, nil
+}
+
+// collectHashStatistics reads the row counts a fingerprint with
+// --include-stats covers
+func collectHashStatistics(ctx context.Context, reader schemalyzer.Reader, schema *models.Schema, options schemalyzer.FingerprintOptions) error {
+	if !options.Hash.IncludeStatistics {
+		return nil
+	}
+	if err := schemalyzer.CollectStatistics(ctx, reader, schema, schemalyzer.StatisticsOptions{RowCounts: true}); err != nil {
+		return fmt.Errorf("failed to collect row counts for schema %s: %w", schema.Name, err)
+	}
+	return nil
+}
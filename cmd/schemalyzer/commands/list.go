@@ -1,9 +1,9 @@
 package commands
 
 import (
-	"context"
 	"fmt"
 	
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
@@ -22,24 +22,20 @@ func init() {
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	
-	// Create reader
-	reader, err := createReader(sourceType)
+	// Connect
+	reader, err := openReader(ctx, sourceType, sourceConn)
 	if err != nil {
-		return fmt.Errorf("failed to create reader: %w", err)
+		return err
 	}
 	defer reader.Close()
 	
-	// Connect
-	if err := reader.Connect(ctx, sourceConn); err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
-	}
-	
 	// List schemas
-	schemas, err := reader.ListSchemas(ctx)
+	schemas, err := schemalyzer.ListSchemas(ctx, reader)
 	if err != nil {
-		return fmt.Errorf("failed to list schemas: %w", err)
+		return err
 	}
 	
 	fmt.Println("Available schemas:")
@@ -1,11 +1,28 @@
 package commands
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
-	
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
+var (
+	commandTimeout     time.Duration
+	statementTimeout   time.Duration
+	maxOpenConns       int
+	maxIdleConns       int
+	connMaxLifetime    time.Duration
+	schemaConcurrency  int
+	consistentSnapshot bool
+)
+
 var RootCmd = &cobra.Command{
 	Use:   "schemalyzer",
 	Short: "A schema comparison tool for PostgreSQL, MySQL, and Oracle databases",
@@ -15,9 +32,19 @@ to be used in CI/CD pipelines for database change detection.`,
 }
 
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
+	// Ctrl-C and SIGTERM cancel in-flight catalog queries so readers can
+	// close their sessions instead of leaving them open on the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := RootCmd.ExecuteContext(ctx)
+	stop()
+
+	if err != nil {
 		// Check if it's an ExitError with a specific exit code
-		if exitErr, ok := err.(*ExitError); ok {
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Message != "" {
+				fmt.Fprintln(os.Stderr, exitErr.Message)
+			}
 			// Exit with the specified code
 			os.Exit(exitErr.Code)
 		}
@@ -27,6 +54,15 @@ func Execute() {
 }
 
 func init() {
+	defaults := schemalyzer.DefaultReaderOptions()
+	RootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", 0, "Abort the command after this long (e.g. 30s, 5m); 0 waits indefinitely")
+	RootCmd.PersistentFlags().DurationVar(&statementTimeout, "statement-timeout", 0, "Abort any single catalog query after this long; 0 disables")
+	RootCmd.PersistentFlags().IntVar(&maxOpenConns, "max-open-conns", defaults.MaxOpenConns, "Maximum open connections per database; 0 is unlimited")
+	RootCmd.PersistentFlags().IntVar(&maxIdleConns, "max-idle-conns", defaults.MaxIdleConns, "Maximum idle connections kept per database")
+	RootCmd.PersistentFlags().DurationVar(&connMaxLifetime, "conn-max-lifetime", defaults.ConnMaxLifetime, "Recycle connections older than this; 0 keeps them forever")
+	RootCmd.PersistentFlags().BoolVar(&consistentSnapshot, "consistent", false, "Read the whole catalog from one point-in-time snapshot")
+	RootCmd.PersistentFlags().IntVar(&schemaConcurrency, "concurrency", schemalyzer.DefaultSchemaConcurrency, "Number of schemas read in parallel with --schemas")
+
 	RootCmd.AddCommand(compareCmd)
 	RootCmd.AddCommand(listCmd)
 	RootCmd.AddCommand(exportCmd)
@@ -34,4 +70,9 @@ func init() {
 	RootCmd.AddCommand(documentCmd)
 	RootCmd.AddCommand(fingerprintCmd)
 	RootCmd.AddCommand(compareFingerprintsCmd)
-}
\ No newline at end of file
+	RootCmd.AddCommand(snapshotCmd)
+	RootCmd.AddCommand(watchCmd)
+	RootCmd.AddCommand(serveCmd)
+	RootCmd.AddCommand(validateFleetCmd)
+	RootCmd.AddCommand(validateTenantsCmd)
+}
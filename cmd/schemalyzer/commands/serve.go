@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nechja/schemalyzer/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveConfig string
+	serveListen string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve schemas, fingerprints, comparisons and docs over HTTP",
+	Long: `Run an HTTP API for listing schemas, exporting snapshots, fingerprinting,
+comparing schemas and rendering documentation. Databases are named in the
+server configuration file, so clients never send connection strings.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveConfig, "config", "", "Server configuration file (required)")
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "Address to listen on, overriding the configuration (e.g. :8080)")
+	_ = serveCmd.MarkFlagRequired("config")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	config, err := server.LoadConfig(serveConfig)
+	if err != nil {
+		return err
+	}
+	if serveListen != "" {
+		config.Listen = serveListen
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	fmt.Fprintf(os.Stderr, "Serving %d connections on %s\n", len(config.Connections), config.Listen)
+	return server.New(config, openReader).ListenAndServe(ctx)
+}
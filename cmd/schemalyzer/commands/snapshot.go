@@ -0,0 +1,312 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyDir         string
+	snapshotEnv        string
+	snapshotType       string
+	snapshotConn       string
+	snapshotSchema     string
+	snapshotFile       string
+	snapshotMessage    string
+	snapshotTablesOnly bool
+	snapshotJSON       bool
+	snapshotFormat     string
+	snapshotOutput     string
+	snapshotIgnore     []string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Keep a local history of schema snapshots",
+	Long: `Save schema snapshots of each environment into a local content-addressed
+store and query their history.
+
+Revisions are named by ID (or a unique prefix of at least four characters),
+by environment for its newest revision, or as env~N for the Nth revision
+before the newest.`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save a schema snapshot as an environment's newest revision",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotSave,
+}
+
+var snapshotLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List saved revisions, newest first",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotLog,
+}
+
+var snapshotShowCmd = &cobra.Command{
+	Use:   "show <rev>",
+	Short: "Print the schema saved in a revision",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnapshotShow,
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff <rev> <rev>",
+	Short: "Compare the schemas saved in two revisions",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSnapshotDiff,
+}
+
+var snapshotBlameCmd = &cobra.Command{
+	Use:   "blame <object>",
+	Short: "Show the revisions in which an object changed",
+	Long: `Walk an environment's revisions oldest first and list every revision in
+which an object such as a table or "orders.status" was added, removed or changed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotBlame,
+}
+
+func init() {
+	snapshotCmd.PersistentFlags().StringVar(&historyDir, "store", schemalyzer.DefaultHistoryDir, "Snapshot store directory")
+
+	snapshotSaveCmd.Flags().StringVar(&snapshotEnv, "env", "", "Environment the snapshot belongs to (e.g. prod, staging)")
+	snapshotSaveCmd.Flags().StringVar(&snapshotType, "type", "", "Database type (postgresql, mysql, oracle)")
+	snapshotSaveCmd.Flags().StringVar(&snapshotConn, "conn", "", "Database connection string")
+	snapshotSaveCmd.Flags().StringVar(&snapshotSchema, "schema", "", "Schema name")
+	snapshotSaveCmd.Flags().StringVar(&snapshotFile, "file", "", "Save a schema file written by 'export' instead of reading a database")
+	snapshotSaveCmd.Flags().StringVarP(&snapshotMessage, "message", "m", "", "Note recorded with the revision")
+	snapshotSaveCmd.Flags().BoolVar(&snapshotTablesOnly, "tables-only", false, "Save only tables and their structure (no procedures, functions, triggers)")
+	_ = snapshotSaveCmd.MarkFlagRequired("env")
+	snapshotSaveCmd.MarkFlagsOneRequired("conn", "file")
+	snapshotSaveCmd.MarkFlagsMutuallyExclusive("conn", "file")
+	snapshotSaveCmd.MarkFlagsRequiredTogether("type", "conn", "schema")
+
+	snapshotLogCmd.Flags().StringVar(&snapshotEnv, "env", "", "Only list revisions of this environment")
+	snapshotLogCmd.Flags().BoolVar(&snapshotJSON, "json", false, "Output in JSON format")
+
+	snapshotShowCmd.Flags().StringVar(&snapshotOutput, "output", "", "Write the schema to a .json or .yaml file instead of stdout")
+
+	snapshotDiffCmd.Flags().StringVar(&snapshotFormat, "format", "text", "Output format (json, yaml, text, summary, html, junit, sarif, markdown, json-tree, yaml-tree)")
+	snapshotDiffCmd.Flags().StringSliceVar(&snapshotIgnore, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit'); @file reads one per line")
+
+	snapshotBlameCmd.Flags().StringVar(&snapshotEnv, "env", "", "Environment whose history is searched")
+	snapshotBlameCmd.Flags().BoolVar(&snapshotJSON, "json", false, "Output in JSON format")
+	_ = snapshotBlameCmd.MarkFlagRequired("env")
+
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotLogCmd)
+	snapshotCmd.AddCommand(snapshotShowCmd)
+	snapshotCmd.AddCommand(snapshotDiffCmd)
+	snapshotCmd.AddCommand(snapshotBlameCmd)
+}
+
+func runSnapshotSave(cmd *cobra.Command, args []string) error {
+	store, err := schemalyzer.OpenHistory(historyDir)
+	if err != nil {
+		return err
+	}
+
+	var schemaData *models.Schema
+	if snapshotFile != "" {
+		schemaData, err = schemalyzer.LoadSchema(snapshotFile)
+		if err != nil {
+			return err
+		}
+	} else {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		reader, err := openReader(ctx, snapshotType, snapshotConn)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		fmt.Fprintf(os.Stderr, "Reading schema: %s\n", snapshotSchema)
+		schemaData, err = schemalyzer.ReadSchema(ctx, reader, snapshotSchema, schemalyzer.ReadOptions{TablesOnly: snapshotTablesOnly})
+		if err != nil {
+			return err
+		}
+	}
+
+	rev, err := store.Save(snapshotEnv, schemaData, snapshotMessage)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(rev.ID)
+	fmt.Fprintf(os.Stderr, "Saved %s snapshot of schema %s as revision %s\n", rev.Environment, rev.Schema, rev.ShortID())
+	return nil
+}
+
+func runSnapshotLog(cmd *cobra.Command, args []string) error {
+	store, err := schemalyzer.OpenHistory(historyDir)
+	if err != nil {
+		return err
+	}
+
+	log, err := store.Log(snapshotEnv)
+	if err != nil {
+		return err
+	}
+
+	if snapshotJSON {
+		if log == nil {
+			log = []*schemalyzer.Revision{}
+		}
+		jsonData, err := json.MarshalIndent(log, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(log) == 0 {
+		fmt.Fprintf(os.Stderr, "No revisions in %s\n", store.Dir())
+		return nil
+	}
+	for _, rev := range log {
+		fmt.Printf("%s  %s  %-12s %s", rev.ShortID(), rev.Time.Local().Format(time.DateTime), rev.Environment, rev.Schema)
+		if rev.Message != "" {
+			fmt.Printf("  %s", rev.Message)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runSnapshotShow(cmd *cobra.Command, args []string) error {
+	store, err := schemalyzer.OpenHistory(historyDir)
+	if err != nil {
+		return err
+	}
+
+	_, schemaData, err := loadRevision(store, args[0])
+	if err != nil {
+		return err
+	}
+
+	if snapshotOutput != "" {
+		if err := schemalyzer.SaveSchema(schemaData, snapshotOutput); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Schema written to: %s\n", snapshotOutput)
+		return nil
+	}
+
+	jsonData, err := json.MarshalIndent(schemaData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
+func runSnapshotDiff(cmd *cobra.Command, args []string) error {
+	store, err := schemalyzer.OpenHistory(historyDir)
+	if err != nil {
+		return err
+	}
+
+	sourceRev, source, err := loadRevision(store, args[0])
+	if err != nil {
+		return err
+	}
+	targetRev, target, err := loadRevision(store, args[1])
+	if err != nil {
+		return err
+	}
+
+	ignore, err := expandIgnorePatterns(snapshotIgnore)
+	if err != nil {
+		return err
+	}
+	result, err := schemalyzer.Compare(source, target, schemalyzer.CompareOptions{Ignore: ignore})
+	if err != nil {
+		return err
+	}
+	result.SourceDatabase = fmt.Sprintf("%s@%s", sourceRev.Environment, sourceRev.ShortID())
+	result.TargetDatabase = fmt.Sprintf("%s@%s", targetRev.Environment, targetRev.ShortID())
+
+	outputData, err := schemalyzer.FormatResult(result, schemalyzer.OutputFormat(snapshotFormat))
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(outputData))
+	return nil
+}
+
+func runSnapshotBlame(cmd *cobra.Command, args []string) error {
+	store, err := schemalyzer.OpenHistory(historyDir)
+	if err != nil {
+		return err
+	}
+
+	changes, err := store.Blame(snapshotEnv, args[0])
+	if err != nil {
+		return err
+	}
+
+	if snapshotJSON {
+		type blameEntry struct {
+			Revision    string    `json:"revision"`
+			Time        time.Time `json:"time"`
+			Message     string    `json:"message,omitempty"`
+			Change      string    `json:"change"`
+			ObjectType  string    `json:"object_type"`
+			ObjectName  string    `json:"object_name"`
+			Description string    `json:"description"`
+		}
+		entries := []blameEntry{}
+		for _, change := range changes {
+			entries = append(entries, blameEntry{
+				Revision:    change.Revision.ID,
+				Time:        change.Revision.Time,
+				Message:     change.Revision.Message,
+				Change:      string(change.Difference.Type),
+				ObjectType:  change.Difference.ObjectType,
+				ObjectName:  change.Difference.ObjectName,
+				Description: change.Difference.Description,
+			})
+		}
+		jsonData, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(os.Stderr, "No changes to %s in %s\n", args[0], snapshotEnv)
+		return nil
+	}
+	for _, change := range changes {
+		diff := change.Difference
+		fmt.Printf("%s  %s  %s %s %s: %s\n", change.Revision.ShortID(), change.Revision.Time.Local().Format(time.DateTime),
+			changeMarker(diff.Type), diff.ObjectType, diff.ObjectName, diff.Description)
+	}
+	return nil
+}
+
+// loadRevision resolves a revision reference and loads its schema
+func loadRevision(store *schemalyzer.History, ref string) (*schemalyzer.Revision, *models.Schema, error) {
+	rev, err := store.Resolve(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	schemaData, err := store.Load(rev)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rev, schemaData, nil
+}
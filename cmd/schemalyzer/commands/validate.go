@@ -1,13 +1,10 @@
 package commands
 
 import (
-	"context"
 	"fmt"
-	"os"
 	
-	"github.com/nechja/schemalyzer/internal/compare"
-	"github.com/nechja/schemalyzer/internal/schema"
 	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
 	"github.com/spf13/cobra"
 )
 
@@ -28,62 +25,97 @@ func init() {
 	validateCmd.Flags().StringVar(&sourceType, "type", "", "Database type (postgresql, mysql, oracle)")
 	validateCmd.Flags().StringVar(&sourceConn, "conn", "", "Database connection string")
 	validateCmd.Flags().StringVar(&sourceSchema, "schema", "", "Schema name to validate")
+	validateCmd.Flags().StringVar(&schemaPattern, "schemas", "", "Validate every schema matching a glob against a database snapshot golden file")
 	validateCmd.Flags().StringVar(&goldenFile, "golden", "", "Golden schema file (JSON or YAML)")
 	validateCmd.Flags().BoolVar(&pipelineMode, "pipeline", false, "Pipeline mode: minimal output, only exit codes")
-	validateCmd.Flags().StringSliceVar(&ignorePatterns, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit')")
+	validateCmd.Flags().StringVar(&outputFormat, "format", "text", "Output format (text, json, yaml, summary, html, junit, sarif, markdown, json-tree, yaml-tree)")
+	validateCmd.Flags().StringVar(&templateFile, "template", "", "Render the result with a Go text/template or html/template file instead of --format")
+	validateCmd.Flags().IntVar(&outputMaxBytes, "max-size", schemalyzer.DefaultMarkdownMaxBytes, "Truncate markdown reports to this many bytes")
+	validateCmd.Flags().StringSliceVar(&ignorePatterns, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit'); @file reads one per line")
 	_ = validateCmd.MarkFlagRequired("type")
 	_ = validateCmd.MarkFlagRequired("conn")
-	_ = validateCmd.MarkFlagRequired("schema")
+	validateCmd.MarkFlagsOneRequired("schema", "schemas")
+	validateCmd.MarkFlagsMutuallyExclusive("schema", "schemas")
 	_ = validateCmd.MarkFlagRequired("golden")
+	validateCmd.MarkFlagsMutuallyExclusive("template", "format")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	
-	// Load golden schema from file
-	loader := schema.NewLoader()
-	goldenSchema, err := loader.LoadFromFile(goldenFile)
+	// Connect to database
+	reader, err := openReader(ctx, sourceType, sourceConn)
 	if err != nil {
-		return fmt.Errorf("failed to load golden schema: %w", err)
+		return err
 	}
+	defer reader.Close()
 	
-	// Create reader for current database
-	reader, err := createReader(sourceType)
+	ignore, err := expandIgnorePatterns(ignorePatterns)
 	if err != nil {
-		return fmt.Errorf("failed to create reader: %w", err)
+		return err
 	}
-	defer reader.Close()
-	
-	// Connect to database
-	if err := reader.Connect(ctx, sourceConn); err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+	opts := schemalyzer.CompareOptions{Ignore: ignore}
+	var result *models.ComparisonResult
+	if schemaPattern != "" {
+		// Load golden database snapshot and read every matching schema
+		goldenDB, err := schemalyzer.LoadDatabase(goldenFile)
+		if err != nil {
+			return err
+		}
+		
+		currentDB, err := schemalyzer.ReadDatabase(ctx, reader, schemaPattern, schemalyzer.ReadOptions{Concurrency: schemaConcurrency})
+		if err != nil {
+			return err
+		}
+		
+		result, err = schemalyzer.CompareDatabases(goldenDB, currentDB, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Load golden schema from file
+		goldenSchema, err := schemalyzer.LoadSchema(goldenFile)
+		if err != nil {
+			return err
+		}
+		
+		// Get current schema
+		currentSchema, err := schemalyzer.ReadSchema(ctx, reader, sourceSchema, schemalyzer.ReadOptions{})
+		if err != nil {
+			return err
+		}
+		
+		result, err = schemalyzer.Compare(goldenSchema, currentSchema, opts)
+		if err != nil {
+			return err
+		}
 	}
 	
-	// Get current schema
-	currentSchema, err := reader.GetSchema(ctx, sourceSchema)
-	if err != nil {
-		return fmt.Errorf("failed to read schema: %w", err)
+	result.SourceDatabase = goldenFile
+	if schemaPattern != "" {
+		result.TargetDatabase = fmt.Sprintf("%s://%s", sourceType, schemaPattern)
+	} else {
+		result.TargetDatabase = fmt.Sprintf("%s://%s", sourceType, sourceSchema)
 	}
 	
-	// Compare schemas
-	var comparer *compare.Comparer
-	if len(ignorePatterns) > 0 {
-		ignoreConfig, err := models.NewIgnoreConfig(ignorePatterns)
+	// Report formats for CI systems are written whether or not schemas match
+	if outputFormat != "text" || templateFile != "" {
+		outputData, err := renderResult(result)
 		if err != nil {
-			return fmt.Errorf("failed to parse ignore patterns: %w", err)
+			return err
 		}
-		comparer = compare.NewComparerWithIgnore(ignoreConfig)
-	} else {
-		comparer = compare.NewComparer()
+		fmt.Print(string(outputData))
+		if len(result.Differences) > 0 {
+			return mismatchError(cmd, "Validation failed: %d differences found", len(result.Differences))
+		}
+		return nil
 	}
 	
-	result := comparer.Compare(goldenSchema, currentSchema)
-	
 	// In pipeline mode, only output if there are differences
 	if pipelineMode {
 		if len(result.Differences) > 0 {
-			fmt.Fprintf(os.Stderr, "Validation failed: %d differences found\n", len(result.Differences))
-			os.Exit(ExitCodeMismatch)
+			return mismatchError(cmd, "Validation failed: %d differences found", len(result.Differences))
 		}
 		// Success - no output
 		return nil
@@ -140,6 +172,5 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Exit with code 2 for differences (informational, not an error)
-	os.Exit(ExitCodeMismatch)
-	return nil
+	return mismatchError(cmd, "")
 }
\ No newline at end of file
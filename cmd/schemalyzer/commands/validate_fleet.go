@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nechja/schemalyzer/internal/fleet"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fleetConnections  string
+	fleetSelect       string
+	fleetConnTemplate string
+	fleetTenants      string
+	fleetWorkers      int
+	fleetFormat       string
+)
+
+var validateFleetCmd = &cobra.Command{
+	Use:   "validate-fleet",
+	Short: "Validate many databases against a golden file in parallel",
+	Long: `Validate every database of a fleet against one golden file with a bounded
+pool of workers, then report which members deviate and how. Members with
+identical differences are grouped, so one migration missing on forty tenants
+shows up as a single group.
+
+The fleet is either the connections section of a YAML file (optionally
+narrowed with --select), or a connection template in which {tenant} is
+replaced by each name from --tenants.
+
+Returns exit code 0 if every member matches, 2 if any deviate and 1 if any
+could not be read.`,
+	Args: cobra.NoArgs,
+	RunE: runValidateFleet,
+}
+
+func init() {
+	validateFleetCmd.Flags().StringVar(&fleetConnections, "connections", "", "YAML file with a connections section of name: {type, conn, schema}")
+	validateFleetCmd.Flags().StringVar(&fleetSelect, "select", "", "Only validate connections whose name matches this glob (e.g. 'tenant_*')")
+	validateFleetCmd.Flags().StringVar(&fleetConnTemplate, "conn-template", "", "Connection string template containing {tenant}")
+	validateFleetCmd.Flags().StringVar(&fleetTenants, "tenants", "", "Tenant names for --conn-template: a comma-separated list or @file with one per line")
+	validateFleetCmd.Flags().StringVar(&sourceType, "type", "", "Database type for template members and connections without one")
+	validateFleetCmd.Flags().StringVar(&sourceSchema, "schema", "", "Schema to validate; may contain {tenant} with --conn-template")
+	validateFleetCmd.Flags().StringVar(&goldenFile, "golden", "", "Golden schema file (JSON or YAML)")
+	validateFleetCmd.Flags().StringSliceVar(&ignorePatterns, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit'); @file reads one per line")
+	validateFleetCmd.Flags().BoolVar(&tablesOnly, "tables-only", false, "Validate only tables and their structure")
+	validateFleetCmd.Flags().IntVar(&fleetWorkers, "workers", 8, "Maximum number of databases validated at once")
+	validateFleetCmd.Flags().StringVar(&fleetFormat, "format", "text", "Report format (text, json, csv)")
+	validateFleetCmd.MarkFlagsOneRequired("connections", "conn-template")
+	validateFleetCmd.MarkFlagsMutuallyExclusive("connections", "conn-template")
+	validateFleetCmd.MarkFlagsRequiredTogether("conn-template", "tenants")
+	_ = validateFleetCmd.MarkFlagRequired("golden")
+}
+
+func runValidateFleet(cmd *cobra.Command, args []string) error {
+	members, err := fleetMembers()
+	if err != nil {
+		return err
+	}
+
+	golden, err := schemalyzer.LoadSchema(goldenFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	byName := make(map[string]fleet.Member, len(members))
+	names := make([]string, len(members))
+	for i, member := range members {
+		byName[member.Name] = member
+		names[i] = member.Name
+	}
+
+	ignore, err := expandIgnorePatterns(ignorePatterns)
+	if err != nil {
+		return err
+	}
+	opts := schemalyzer.CompareOptions{Ignore: ignore, TablesOnly: tablesOnly}
+	results := fleet.Run(ctx, names, fleetWorkers, func(ctx context.Context, name string) ([]models.Difference, error) {
+		current, err := readFleetMember(ctx, byName[name])
+		if err != nil {
+			return nil, err
+		}
+		result, err := schemalyzer.Compare(golden, current, opts)
+		if err != nil {
+			return nil, err
+		}
+		return result.Differences, nil
+	})
+
+	return writeFleetReport(cmd, fleet.NewReport(goldenFile, results), "databases")
+}
+
+// writeFleetReport prints report in --format and sets the exit status:
+// 1 when members could not be checked, 2 when any deviate
+func writeFleetReport(cmd *cobra.Command, report *fleet.Report, noun string) error {
+	var err error
+	switch fleetFormat {
+	case "text":
+		err = report.WriteText(os.Stdout)
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	case "csv":
+		err = report.WriteMatrix(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported report format: %s", fleetFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if len(report.Failed) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d %s could not be validated", len(report.Failed), report.Total, noun)
+	}
+	if deviating := report.Deviating(); deviating > 0 {
+		return mismatchError(cmd, "%d of %d %s deviate from %s", deviating, report.Total, noun, report.Reference)
+	}
+	return nil
+}
+
+// fleetMembers builds the fleet from either the connections file or the
+// connection template
+func fleetMembers() ([]fleet.Member, error) {
+	if fleetConnections != "" {
+		return fleet.LoadMembers(fleetConnections, fleetSelect, sourceType, sourceSchema)
+	}
+
+	var tenants []string
+	if file, ok := strings.CutPrefix(fleetTenants, "@"); ok {
+		var err error
+		if tenants, err = fleet.ReadTenants(file); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, tenant := range strings.Split(fleetTenants, ",") {
+			if tenant = strings.TrimSpace(tenant); tenant != "" {
+				tenants = append(tenants, tenant)
+			}
+		}
+	}
+	return fleet.ExpandTemplate(sourceType, fleetConnTemplate, sourceSchema, tenants)
+}
+
+// readFleetMember connects to one member for the duration of its check, so
+// the worker count bounds open connections
+func readFleetMember(ctx context.Context, member fleet.Member) (*models.Schema, error) {
+	reader, err := openReader(ctx, member.Type, member.Conn)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return schemalyzer.ReadSchema(ctx, reader, member.Schema, schemalyzer.ReadOptions{TablesOnly: tablesOnly})
+}
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nechja/schemalyzer/internal/fleet"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"github.com/spf13/cobra"
+)
+
+var tenantReference string
+
+var validateTenantsCmd = &cobra.Command{
+	Use:   "validate-tenants",
+	Short: "Check that every tenant schema in one database is consistent",
+	Long: `Compare every schema matching --schemas on one connection against a
+reference, either another schema on the same connection (--reference) or a
+golden file (--golden), and report the outliers. Tenants with identical
+differences are grouped, so the report shows the few distinct kinds of drift
+rather than hundreds of individual diffs. References to a schema's own name,
+such as nextval('tenant_001.orders_id_seq') in a column default, are ignored.
+
+Schemas are read --concurrency at a time. Returns exit code 0 if every tenant
+matches, 2 if any deviate and 1 if any could not be read.`,
+	Args: cobra.NoArgs,
+	RunE: runValidateTenants,
+}
+
+func init() {
+	validateTenantsCmd.Flags().StringVar(&sourceType, "type", "", "Database type (postgresql, mysql, oracle)")
+	validateTenantsCmd.Flags().StringVar(&sourceConn, "conn", "", "Database connection string")
+	validateTenantsCmd.Flags().StringVar(&schemaPattern, "schemas", "", "Glob selecting the tenant schemas (e.g. 'tenant_*')")
+	validateTenantsCmd.Flags().StringVar(&tenantReference, "reference", "", "Schema on the same connection every tenant is compared against")
+	validateTenantsCmd.Flags().StringVar(&goldenFile, "golden", "", "Golden schema file every tenant is compared against")
+	validateTenantsCmd.Flags().StringSliceVar(&ignorePatterns, "ignore", []string{}, "Ignore patterns (e.g., 'table:temp_*', 'constraint:SYS_*', '*_audit'); @file reads one per line")
+	validateTenantsCmd.Flags().BoolVar(&tablesOnly, "tables-only", false, "Validate only tables and their structure")
+	validateTenantsCmd.Flags().StringVar(&fleetFormat, "format", "text", "Report format (text, json, csv)")
+	_ = validateTenantsCmd.MarkFlagRequired("type")
+	_ = validateTenantsCmd.MarkFlagRequired("conn")
+	_ = validateTenantsCmd.MarkFlagRequired("schemas")
+	validateTenantsCmd.MarkFlagsOneRequired("reference", "golden")
+	validateTenantsCmd.MarkFlagsMutuallyExclusive("reference", "golden")
+}
+
+func runValidateTenants(cmd *cobra.Command, args []string) error {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	reader, err := openReader(ctx, sourceType, sourceConn)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	report, err := validateTenants(ctx, reader)
+	if err != nil {
+		return err
+	}
+	return writeFleetReport(cmd, report, "tenant schemas")
+}
+
+// validateTenants compares every schema matching --schemas against the
+// reference
+func validateTenants(ctx context.Context, reader schemalyzer.Reader) (*fleet.Report, error) {
+	readOpts := schemalyzer.ReadOptions{TablesOnly: tablesOnly}
+	var reference *models.Schema
+	var err error
+	referenceName := goldenFile
+	if tenantReference != "" {
+		referenceName = tenantReference
+		reference, err = schemalyzer.ReadSchema(ctx, reader, tenantReference, readOpts)
+	} else {
+		reference, err = schemalyzer.LoadSchema(goldenFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	reference = unqualified(reference)
+
+	schemas, err := schemalyzer.ListSchemasMatching(ctx, reader, schemaPattern)
+	if err != nil {
+		return nil, err
+	}
+	tenants := make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		// The reference trivially matches itself
+		if !strings.EqualFold(schema, tenantReference) {
+			tenants = append(tenants, schema)
+		}
+	}
+	if len(tenants) == 0 {
+		return nil, fmt.Errorf("no schemas match %q", schemaPattern)
+	}
+
+	ignore, err := expandIgnorePatterns(ignorePatterns)
+	if err != nil {
+		return nil, err
+	}
+	opts := schemalyzer.CompareOptions{Ignore: ignore, TablesOnly: tablesOnly}
+	results := fleet.Run(ctx, tenants, schemaConcurrency, func(ctx context.Context, tenant string) ([]models.Difference, error) {
+		current, err := schemalyzer.ReadSchema(ctx, reader, tenant, readOpts)
+		if err != nil {
+			return nil, err
+		}
+		result, err := schemalyzer.Compare(reference, unqualified(current), opts)
+		if err != nil {
+			return nil, err
+		}
+		return result.Differences, nil
+	})
+
+	return fleet.NewReport(referenceName, results), nil
+}
+
+// unqualified returns a copy of schema with references to its own name
+// stripped from defaults, types, expressions, definitions and bodies, and
+// blanked in synonym owners and extension schemas.
+// Catalogs spell out a tenant's own schema where it is not on the search
+// path, e.g. nextval('tenant_001.orders_id_seq'::regclass), which would
+// otherwise make every tenant differ from the reference.
+func unqualified(schema *models.Schema) *models.Schema {
+	name := regexp.QuoteMeta(schema.Name)
+	qualifier := regexp.MustCompile("(?i)(^|[^\\w$.\"`])(\"" + name + "\"|`" + name + "`|" + name + ")\\.")
+	strip := func(s string) string {
+		return qualifier.ReplaceAllString(s, "${1}")
+	}
+
+	copied := *schema
+	copied.Tables = make([]models.Table, len(schema.Tables))
+	for i, table := range schema.Tables {
+		table.Columns = append([]models.Column(nil), table.Columns...)
+		for j := range table.Columns {
+			col := &table.Columns[j]
+			col.DataType = strip(col.DataType)
+			if col.DefaultValue != nil {
+				def := strip(*col.DefaultValue)
+				col.DefaultValue = &def
+			}
+		}
+		table.Constraints = append([]models.Constraint(nil), table.Constraints...)
+		for j := range table.Constraints {
+			c := &table.Constraints[j]
+			c.CheckExpression = strip(c.CheckExpression)
+			c.ExclusionDefinition = strip(c.ExclusionDefinition)
+		}
+		table.Policies = append([]models.Policy(nil), table.Policies...)
+		for j := range table.Policies {
+			p := &table.Policies[j]
+			p.Using = strip(p.Using)
+			p.WithCheck = strip(p.WithCheck)
+		}
+		copied.Tables[i] = table
+	}
+
+	copied.Views = append([]models.View(nil), schema.Views...)
+	for i := range copied.Views {
+		copied.Views[i].Definition = strip(copied.Views[i].Definition)
+	}
+	copied.Procedures = append([]models.Procedure(nil), schema.Procedures...)
+	for i := range copied.Procedures {
+		proc := &copied.Procedures[i]
+		proc.Parameters = unqualifiedParameters(proc.Parameters, strip)
+		proc.Body = strip(proc.Body)
+	}
+	copied.Functions = append([]models.Function(nil), schema.Functions...)
+	for i := range copied.Functions {
+		fn := &copied.Functions[i]
+		fn.Parameters = unqualifiedParameters(fn.Parameters, strip)
+		fn.ReturnType = strip(fn.ReturnType)
+		fn.Body = strip(fn.Body)
+	}
+	copied.Triggers = append([]models.Trigger(nil), schema.Triggers...)
+	for i := range copied.Triggers {
+		copied.Triggers[i].Body = strip(copied.Triggers[i].Body)
+	}
+	copied.Events = append([]models.Event(nil), schema.Events...)
+	for i := range copied.Events {
+		copied.Events[i].Body = strip(copied.Events[i].Body)
+	}
+	copied.Synonyms = append([]models.Synonym(nil), schema.Synonyms...)
+	for i := range copied.Synonyms {
+		if strings.EqualFold(copied.Synonyms[i].TargetOwner, schema.Name) {
+			copied.Synonyms[i].TargetOwner = ""
+		}
+	}
+	copied.Extensions = append([]models.Extension(nil), schema.Extensions...)
+	for i := range copied.Extensions {
+		if strings.EqualFold(copied.Extensions[i].Schema, schema.Name) {
+			copied.Extensions[i].Schema = ""
+		}
+	}
+	return &copied
+}
+
+func unqualifiedParameters(params []models.Parameter, strip func(string) string) []models.Parameter {
+	params = append([]models.Parameter(nil), params...)
+	for i := range params {
+		params[i].DataType = strip(params[i].DataType)
+	}
+	return params
+}
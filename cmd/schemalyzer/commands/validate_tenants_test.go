@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantReader serves fixed schemas by name
+type tenantReader map[string]*models.Schema
+
+func (r tenantReader) Connect(ctx context.Context, connectionString string) error { return nil }
+func (r tenantReader) Close() error                                               { return nil }
+
+func (r tenantReader) ListSchemas(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range r {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (r tenantReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	schema, ok := r[schemaName]
+	if !ok {
+		return nil, errors.New("schema not found")
+	}
+	return schema, nil
+}
+
+// tenantSchema is a tenant whose catalog spells out its own schema name,
+// as PostgreSQL does for schemas not on the search path
+func tenantSchema(name, sequenceSchema string) *models.Schema {
+	def := "nextval('" + sequenceSchema + ".orders_id_seq'::regclass)"
+	return &models.Schema{
+		Name:         name,
+		DatabaseType: models.PostgreSQL,
+		Tables: []models.Table{{
+			Name:   "orders",
+			Schema: name,
+			Columns: []models.Column{
+				{Name: "id", DataType: "integer", DefaultValue: &def, Position: 1},
+				{Name: "status", DataType: name + ".order_status", Position: 2},
+			},
+		}},
+		Views: []models.View{{
+			Name:       "open_orders",
+			Schema:     name,
+			Definition: `SELECT id FROM "` + name + `".orders WHERE status = 'open'`,
+		}},
+		Functions: []models.Function{{
+			Name:       "order_count",
+			Schema:     name,
+			ReturnType: "bigint",
+			Body:       "SELECT count(*) FROM " + name + ".orders",
+		}},
+		Synonyms:   []models.Synonym{{Name: "all_orders", Schema: name, TargetOwner: name, TargetObject: "orders"}},
+		Extensions: []models.Extension{{Name: "pgcrypto", Version: "1.3", Schema: name}},
+	}
+}
+
+func TestValidateTenantsIgnoresOwnSchemaQualifiers(t *testing.T) {
+	reference, golden, pattern, ignore, tables := tenantReference, goldenFile, schemaPattern, ignorePatterns, tablesOnly
+	t.Cleanup(func() {
+		tenantReference, goldenFile, schemaPattern, ignorePatterns, tablesOnly = reference, golden, pattern, ignore, tables
+	})
+	tenantReference, goldenFile, schemaPattern, ignorePatterns, tablesOnly = "template", "", "*", nil, false
+
+	reader := tenantReader{
+		"template":   tenantSchema("template", "template"),
+		"tenant_001": tenantSchema("tenant_001", "tenant_001"),
+		"tenant_002": tenantSchema("tenant_002", "tenant_002"),
+		// Using another tenant's sequence is real drift
+		"tenant_003": tenantSchema("tenant_003", "tenant_001"),
+	}
+
+	report, err := validateTenants(context.Background(), reader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant_001", "tenant_002"}, report.Matching)
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, []string{"tenant_003"}, report.Groups[0].Members)
+	assert.Equal(t, "orders.id", report.Groups[0].Differences[0].ObjectName)
+}
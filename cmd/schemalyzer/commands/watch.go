@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/watch"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchConfig        string
+	watchOnce          bool
+	watchMetricsListen string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Monitor schemas for drift and notify webhooks",
+	Long: `Periodically fingerprint every target in a watch configuration file and
+compare it with its golden file whenever the fingerprint changes. Drift,
+recovery and read failures are posted as JSON events to the configured
+webhooks; an unchanged drift is reported only once.`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchConfig, "config", "", "Watch configuration file (required)")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "Check every target once and exit")
+	watchCmd.Flags().StringVar(&watchMetricsListen, "metrics-listen", "", "Serve Prometheus metrics on this address at /metrics (e.g. :9100), overriding the configuration")
+	_ = watchCmd.MarkFlagRequired("config")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	config, err := watch.LoadConfig(watchConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	watcher := watch.New(config, readWatchTarget, watch.NewNotifier(config), os.Stderr)
+	if watchOnce {
+		watcher.CheckAll(ctx)
+		return nil
+	}
+
+	if watchMetricsListen != "" {
+		config.MetricsListen = watchMetricsListen
+	}
+	if config.MetricsListen != "" {
+		if err := serveMetrics(ctx, config.MetricsListen, watcher); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Watching %d targets every %s\n", len(config.Targets), config.Interval)
+	return watcher.Run(ctx)
+}
+
+// serveMetrics serves the watcher's metrics at /metrics until ctx is cancelled
+func serveMetrics(ctx context.Context, addr string, watcher *watch.Watcher) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", watcher.Metrics())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Metrics server stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", listener.Addr())
+	return nil
+}
+
+// readWatchTarget connects to a target for one check; connections aren't
+// held open between checks
+func readWatchTarget(ctx context.Context, target watch.Target) (*models.Schema, error) {
+	reader, err := openReader(ctx, target.Type, target.Conn)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return schemalyzer.ReadSchema(ctx, reader, target.Schema, schemalyzer.ReadOptions{TablesOnly: target.TablesOnly})
+}
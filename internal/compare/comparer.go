@@ -50,6 +50,90 @@ func (c *Comparer) Compare(source, target *models.Schema) *models.ComparisonResu
 	// Compare triggers
 	result.Differences = append(result.Differences, c.compareTriggers(source.Triggers, target.Triggers)...)
 
+	// Compare synonyms
+	result.Differences = append(result.Differences, c.compareSynonyms(source.Synonyms, target.Synonyms)...)
+
+	// Compare extensions
+	result.Differences = append(result.Differences, c.compareExtensions(source.Extensions, target.Extensions)...)
+
+	// Compare events
+	result.Differences = append(result.Differences, c.compareEvents(source.Events, target.Events)...)
+
+	// The comparisons above walk maps, so put the differences in a stable order
+	models.SortDifferences(result.Differences)
+
+	return result
+}
+
+// CompareDatabases compares two database snapshots schema by schema. Schemas
+// are matched by name and every difference is qualified with its schema.
+func (c *Comparer) CompareDatabases(source, target *models.Database) *models.ComparisonResult {
+	result := &models.ComparisonResult{
+		Differences:    []models.Difference{},
+		ComparisonTime: time.Now(),
+	}
+
+	sourceSchemas := source.Schemas
+	targetSchemas := target.Schemas
+	if c.ignoreConfig != nil {
+		sourceSchemas = c.filterSchemas(sourceSchemas)
+		targetSchemas = c.filterSchemas(targetSchemas)
+	}
+
+	sourceMap := make(map[string]*models.Schema)
+	for i := range sourceSchemas {
+		sourceMap[sourceSchemas[i].Name] = &sourceSchemas[i]
+	}
+
+	targetMap := make(map[string]*models.Schema)
+	for i := range targetSchemas {
+		targetMap[targetSchemas[i].Name] = &targetSchemas[i]
+	}
+
+	// Check for removed schemas
+	for name, schema := range sourceMap {
+		if _, exists := targetMap[name]; !exists {
+			result.Differences = append(result.Differences, models.Difference{
+				Type:        models.Removed,
+				ObjectType:  "Schema",
+				ObjectName:  name,
+				Identity:    models.ObjectIdentity{Name: name},
+				Source:      schema,
+				Description: "Schema exists in source but not in target",
+			})
+		}
+	}
+
+	// Check for added schemas
+	for name, schema := range targetMap {
+		if _, exists := sourceMap[name]; !exists {
+			result.Differences = append(result.Differences, models.Difference{
+				Type:        models.Added,
+				ObjectType:  "Schema",
+				ObjectName:  name,
+				Identity:    models.ObjectIdentity{Name: name},
+				Target:      schema,
+				Description: "Schema exists in target but not in source",
+			})
+		}
+	}
+
+	// Compare schemas present on both sides
+	for name, sourceSchema := range sourceMap {
+		if targetSchema, exists := targetMap[name]; exists {
+			for _, diff := range c.Compare(sourceSchema, targetSchema).Differences {
+				diff.Identity.Schema = name
+				diff.ObjectName = diff.Identity.String()
+				result.Differences = append(result.Differences, diff)
+			}
+		}
+	}
+
+	// Compare database-wide objects
+	result.Differences = append(result.Differences, c.compareExtensions(source.Extensions, target.Extensions)...)
+
+	models.SortDifferences(result.Differences)
+
 	return result
 }
 
@@ -62,23 +146,24 @@ func (c *Comparer) compareTables(source, target []models.Table) []models.Differe
 		target = c.filterTables(target)
 	}
 
-	sourceMap := make(map[string]*models.Table)
+	sourceMap := make(map[models.ObjectIdentity]*models.Table)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Table)
+	targetMap := make(map[models.ObjectIdentity]*models.Table)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed tables
-	for name, table := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, table := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Table",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      table,
 				Description: "Table exists in source but not in target",
 			})
@@ -86,12 +171,13 @@ func (c *Comparer) compareTables(source, target []models.Table) []models.Differe
 	}
 
 	// Check for added tables
-	for name, table := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, table := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Table",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      table,
 				Description: "Table exists in target but not in source",
 			})
@@ -99,8 +185,8 @@ func (c *Comparer) compareTables(source, target []models.Table) []models.Differe
 	}
 
 	// Check for modified tables
-	for name, sourceTable := range sourceMap {
-		if targetTable, exists := targetMap[name]; exists {
+	for id, sourceTable := range sourceMap {
+		if targetTable, exists := targetMap[id]; exists {
 			tableDiffs := c.compareTable(sourceTable, targetTable)
 			differences = append(differences, tableDiffs...)
 		}
@@ -124,12 +210,30 @@ func (c *Comparer) compareTable(source, target *models.Table) []models.Differenc
 	indexDiffs := c.compareTableIndexes(source.Name, source.Indexes, target.Indexes)
 	differences = append(differences, indexDiffs...)
 
+	// Compare row-level security policies
+	policyDiffs := c.comparePolicies(source.Name, source.Policies, target.Policies)
+	differences = append(differences, policyDiffs...)
+
+	// Compare row-level security flags
+	if source.RowSecurityEnabled != target.RowSecurityEnabled || source.RowSecurityForced != target.RowSecurityForced {
+		differences = append(differences, models.Difference{
+			Type:        models.Modified,
+			ObjectType:  "Row Security",
+			ObjectName:  source.Name,
+			Identity:    models.ObjectIdentity{Name: source.Name},
+			Source:      rowSecurityState(source),
+			Target:      rowSecurityState(target),
+			Description: "Table row-level security changed",
+		})
+	}
+
 	// Compare comment
 	if source.Comment != target.Comment {
 		differences = append(differences, models.Difference{
 			Type:        models.Modified,
 			ObjectType:  "Table Comment",
 			ObjectName:  source.Name,
+			Identity:    models.ObjectIdentity{Name: source.Name},
 			Source:      source.Comment,
 			Target:      target.Comment,
 			Description: "Table comment changed",
@@ -142,23 +246,24 @@ func (c *Comparer) compareTable(source, target *models.Table) []models.Differenc
 func (c *Comparer) compareColumns(tableName string, source, target []models.Column) []models.Difference {
 	var differences []models.Difference
 
-	sourceMap := make(map[string]*models.Column)
+	sourceMap := make(map[models.ObjectIdentity]*models.Column)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Table: tableName, Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Column)
+	targetMap := make(map[models.ObjectIdentity]*models.Column)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Table: tableName, Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed columns
-	for name, column := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, column := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Column",
-				ObjectName:  tableName + "." + name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      column,
 				Description: "Column removed from table",
 			})
@@ -166,12 +271,13 @@ func (c *Comparer) compareColumns(tableName string, source, target []models.Colu
 	}
 
 	// Check for added columns
-	for name, column := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, column := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Column",
-				ObjectName:  tableName + "." + name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      column,
 				Description: "Column added to table",
 			})
@@ -179,13 +285,14 @@ func (c *Comparer) compareColumns(tableName string, source, target []models.Colu
 	}
 
 	// Check for modified columns
-	for name, sourceCol := range sourceMap {
-		if targetCol, exists := targetMap[name]; exists {
+	for id, sourceCol := range sourceMap {
+		if targetCol, exists := targetMap[id]; exists {
 			if !c.columnsEqual(sourceCol, targetCol) {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Column",
-					ObjectName:  tableName + "." + name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceCol,
 					Target:      targetCol,
 					Description: "Column definition changed",
@@ -235,23 +342,24 @@ func (c *Comparer) stringPointersEqual(a, b *string) bool {
 func (c *Comparer) compareConstraints(tableName string, source, target []models.Constraint) []models.Difference {
 	var differences []models.Difference
 
-	sourceMap := make(map[string]*models.Constraint)
+	sourceMap := make(map[models.ObjectIdentity]*models.Constraint)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Table: tableName, Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Constraint)
+	targetMap := make(map[models.ObjectIdentity]*models.Constraint)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Table: tableName, Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed constraints
-	for name, constraint := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, constraint := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Constraint",
-				ObjectName:  tableName + "." + name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      constraint,
 				Description: "Constraint removed from table",
 			})
@@ -259,12 +367,13 @@ func (c *Comparer) compareConstraints(tableName string, source, target []models.
 	}
 
 	// Check for added constraints
-	for name, constraint := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, constraint := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Constraint",
-				ObjectName:  tableName + "." + name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      constraint,
 				Description: "Constraint added to table",
 			})
@@ -272,17 +381,28 @@ func (c *Comparer) compareConstraints(tableName string, source, target []models.
 	}
 
 	// Check for modified constraints
-	for name, sourceConstraint := range sourceMap {
-		if targetConstraint, exists := targetMap[name]; exists {
+	for id, sourceConstraint := range sourceMap {
+		if targetConstraint, exists := targetMap[id]; exists {
 			if !c.constraintsEqual(sourceConstraint, targetConstraint) {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Constraint",
-					ObjectName:  tableName + "." + name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceConstraint,
 					Target:      targetConstraint,
 					Description: "Constraint definition changed",
 				})
+			} else if !c.constraintStatesEqual(sourceConstraint, targetConstraint) {
+				differences = append(differences, models.Difference{
+					Type:        models.Modified,
+					ObjectType:  "Constraint",
+					ObjectName:  id.String(),
+					Identity:    id,
+					Source:      sourceConstraint,
+					Target:      targetConstraint,
+					Description: "Constraint state changed: " + constraintStateChange(sourceConstraint, targetConstraint),
+				})
 			}
 		}
 	}
@@ -304,6 +424,9 @@ func (c *Comparer) constraintsEqual(source, target *models.Constraint) bool {
 	if !c.stringSlicesEqualAsSet(source.Columns, target.Columns) {
 		return false
 	}
+	if source.ReferencedSchema != target.ReferencedSchema {
+		return false
+	}
 	if source.ReferencedTable != target.ReferencedTable {
 		return false
 	}
@@ -314,9 +437,45 @@ func (c *Comparer) constraintsEqual(source, target *models.Constraint) bool {
 	if source.CheckExpression != target.CheckExpression {
 		return false
 	}
+	if source.ExclusionDefinition != target.ExclusionDefinition {
+		return false
+	}
 	return true
 }
 
+// constraintStatesEqual compares enforcement state, which is kept apart from
+// the definition so a disabled or unvalidated constraint is reported as such
+func (c *Comparer) constraintStatesEqual(source, target *models.Constraint) bool {
+	return source.IsDeferrable == target.IsDeferrable &&
+		source.IsInitiallyDeferred == target.IsInitiallyDeferred &&
+		source.IsDisabled == target.IsDisabled &&
+		source.IsNotValidated == target.IsNotValidated
+}
+
+func constraintStateChange(source, target *models.Constraint) string {
+	var changes []string
+	if source.IsDisabled != target.IsDisabled {
+		changes = append(changes, constraintStateLabel(source.IsDisabled, "DISABLED", "ENABLED")+" -> "+constraintStateLabel(target.IsDisabled, "DISABLED", "ENABLED"))
+	}
+	if source.IsNotValidated != target.IsNotValidated {
+		changes = append(changes, constraintStateLabel(source.IsNotValidated, "NOT VALIDATED", "VALIDATED")+" -> "+constraintStateLabel(target.IsNotValidated, "NOT VALIDATED", "VALIDATED"))
+	}
+	if source.IsDeferrable != target.IsDeferrable {
+		changes = append(changes, constraintStateLabel(source.IsDeferrable, "DEFERRABLE", "NOT DEFERRABLE")+" -> "+constraintStateLabel(target.IsDeferrable, "DEFERRABLE", "NOT DEFERRABLE"))
+	}
+	if source.IsInitiallyDeferred != target.IsInitiallyDeferred {
+		changes = append(changes, constraintStateLabel(source.IsInitiallyDeferred, "INITIALLY DEFERRED", "INITIALLY IMMEDIATE")+" -> "+constraintStateLabel(target.IsInitiallyDeferred, "INITIALLY DEFERRED", "INITIALLY IMMEDIATE"))
+	}
+	return strings.Join(changes, ", ")
+}
+
+func constraintStateLabel(flag bool, whenSet, whenUnset string) string {
+	if flag {
+		return whenSet
+	}
+	return whenUnset
+}
+
 func (c *Comparer) stringSlicesEqualAsSet(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -343,26 +502,110 @@ func (c *Comparer) stringSlicesEqualAsSet(a, b []string) bool {
 	return true
 }
 
+func (c *Comparer) comparePolicies(tableName string, source, target []models.Policy) []models.Difference {
+	var differences []models.Difference
+
+	sourceMap := make(map[models.ObjectIdentity]*models.Policy)
+	for i := range source {
+		sourceMap[models.ObjectIdentity{Table: tableName, Name: source[i].Name}] = &source[i]
+	}
+
+	targetMap := make(map[models.ObjectIdentity]*models.Policy)
+	for i := range target {
+		targetMap[models.ObjectIdentity{Table: tableName, Name: target[i].Name}] = &target[i]
+	}
+
+	// Check for removed policies
+	for id, policy := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Removed,
+				ObjectType:  "Policy",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Source:      policy,
+				Description: "Policy removed from table",
+			})
+		}
+	}
+
+	// Check for added policies
+	for id, policy := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Added,
+				ObjectType:  "Policy",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Target:      policy,
+				Description: "Policy added to table",
+			})
+		}
+	}
+
+	// Check for modified policies
+	for id, sourcePolicy := range sourceMap {
+		if targetPolicy, exists := targetMap[id]; exists {
+			if !c.policiesEqual(sourcePolicy, targetPolicy) {
+				differences = append(differences, models.Difference{
+					Type:        models.Modified,
+					ObjectType:  "Policy",
+					ObjectName:  id.String(),
+					Identity:    id,
+					Source:      sourcePolicy,
+					Target:      targetPolicy,
+					Description: "Policy definition changed",
+				})
+			}
+		}
+	}
+
+	return differences
+}
+
+func (c *Comparer) policiesEqual(source, target *models.Policy) bool {
+	return strings.EqualFold(source.Command, target.Command) &&
+		source.IsRestrictive == target.IsRestrictive &&
+		c.stringSlicesEqualAsSet(source.Roles, target.Roles) &&
+		source.Using == target.Using &&
+		source.WithCheck == target.WithCheck
+}
+
+// rowSecurityState describes a table's RLS flags for difference output
+func rowSecurityState(table *models.Table) string {
+	switch {
+	case table.RowSecurityEnabled && table.RowSecurityForced:
+		return "ENABLED, FORCED"
+	case table.RowSecurityEnabled:
+		return "ENABLED"
+	case table.RowSecurityForced:
+		return "DISABLED, FORCED"
+	default:
+		return "DISABLED"
+	}
+}
+
 func (c *Comparer) compareTableIndexes(tableName string, source, target []models.Index) []models.Difference {
 	var differences []models.Difference
 
-	sourceMap := make(map[string]*models.Index)
+	sourceMap := make(map[models.ObjectIdentity]*models.Index)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Table: tableName, Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Index)
+	targetMap := make(map[models.ObjectIdentity]*models.Index)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Table: tableName, Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed indexes
-	for name, index := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, index := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Index",
-				ObjectName:  tableName + "." + name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      index,
 				Description: "Index removed from table",
 			})
@@ -370,12 +613,13 @@ func (c *Comparer) compareTableIndexes(tableName string, source, target []models
 	}
 
 	// Check for added indexes
-	for name, index := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, index := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Index",
-				ObjectName:  tableName + "." + name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      index,
 				Description: "Index added to table",
 			})
@@ -383,13 +627,14 @@ func (c *Comparer) compareTableIndexes(tableName string, source, target []models
 	}
 
 	// Check for modified indexes
-	for name, sourceIndex := range sourceMap {
-		if targetIndex, exists := targetMap[name]; exists {
+	for id, sourceIndex := range sourceMap {
+		if targetIndex, exists := targetMap[id]; exists {
 			if !c.indexesEqual(sourceIndex, targetIndex) {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Index",
-					ObjectName:  tableName + "." + name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceIndex,
 					Target:      targetIndex,
 					Description: "Index definition changed",
@@ -424,23 +669,24 @@ func (c *Comparer) compareViews(source, target []models.View) []models.Differenc
 		target = c.filterViews(target)
 	}
 
-	sourceMap := make(map[string]*models.View)
+	sourceMap := make(map[models.ObjectIdentity]*models.View)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.View)
+	targetMap := make(map[models.ObjectIdentity]*models.View)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed views
-	for name, view := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, view := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "View",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      view,
 				Description: "View exists in source but not in target",
 			})
@@ -448,12 +694,13 @@ func (c *Comparer) compareViews(source, target []models.View) []models.Differenc
 	}
 
 	// Check for added views
-	for name, view := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, view := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "View",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      view,
 				Description: "View exists in target but not in source",
 			})
@@ -461,13 +708,14 @@ func (c *Comparer) compareViews(source, target []models.View) []models.Differenc
 	}
 
 	// Check for modified views
-	for name, sourceView := range sourceMap {
-		if targetView, exists := targetMap[name]; exists {
+	for id, sourceView := range sourceMap {
+		if targetView, exists := targetMap[id]; exists {
 			if sourceView.Definition != targetView.Definition {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "View",
-					ObjectName:  name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceView,
 					Target:      targetView,
 					Description: "View definition changed",
@@ -488,23 +736,24 @@ func (c *Comparer) compareIndexes(source, target []models.Index) []models.Differ
 		target = c.filterIndexes(target)
 	}
 
-	sourceMap := make(map[string]*models.Index)
+	sourceMap := make(map[models.ObjectIdentity]*models.Index)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Table: source[i].TableName, Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Index)
+	targetMap := make(map[models.ObjectIdentity]*models.Index)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Table: target[i].TableName, Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed indexes
-	for name, index := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, index := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Index",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      index,
 				Description: "Index exists in source but not in target",
 			})
@@ -512,12 +761,13 @@ func (c *Comparer) compareIndexes(source, target []models.Index) []models.Differ
 	}
 
 	// Check for added indexes
-	for name, index := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, index := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Index",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      index,
 				Description: "Index exists in target but not in source",
 			})
@@ -525,13 +775,14 @@ func (c *Comparer) compareIndexes(source, target []models.Index) []models.Differ
 	}
 
 	// Check for modified indexes
-	for name, sourceIndex := range sourceMap {
-		if targetIndex, exists := targetMap[name]; exists {
+	for id, sourceIndex := range sourceMap {
+		if targetIndex, exists := targetMap[id]; exists {
 			if !c.indexesEqual(sourceIndex, targetIndex) {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Index",
-					ObjectName:  name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceIndex,
 					Target:      targetIndex,
 					Description: "Index definition changed",
@@ -552,23 +803,24 @@ func (c *Comparer) compareSequences(source, target []models.Sequence) []models.D
 		target = c.filterSequences(target)
 	}
 
-	sourceMap := make(map[string]*models.Sequence)
+	sourceMap := make(map[models.ObjectIdentity]*models.Sequence)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Sequence)
+	targetMap := make(map[models.ObjectIdentity]*models.Sequence)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed sequences
-	for name, sequence := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, sequence := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Sequence",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      sequence,
 				Description: "Sequence exists in source but not in target",
 			})
@@ -576,12 +828,13 @@ func (c *Comparer) compareSequences(source, target []models.Sequence) []models.D
 	}
 
 	// Check for added sequences
-	for name, sequence := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, sequence := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Sequence",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      sequence,
 				Description: "Sequence exists in target but not in source",
 			})
@@ -589,13 +842,14 @@ func (c *Comparer) compareSequences(source, target []models.Sequence) []models.D
 	}
 
 	// Check for modified sequences
-	for name, sourceSeq := range sourceMap {
-		if targetSeq, exists := targetMap[name]; exists {
+	for id, sourceSeq := range sourceMap {
+		if targetSeq, exists := targetMap[id]; exists {
 			if !c.sequencesEqual(sourceSeq, targetSeq) {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Sequence",
-					ObjectName:  name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceSeq,
 					Target:      targetSeq,
 					Description: "Sequence definition changed",
@@ -624,23 +878,24 @@ func (c *Comparer) compareProcedures(source, target []models.Procedure) []models
 		target = c.filterProcedures(target)
 	}
 
-	sourceMap := make(map[string]*models.Procedure)
+	sourceMap := make(map[models.ObjectIdentity]*models.Procedure)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Name: source[i].Name, Signature: models.RoutineSignature(source[i].Parameters)}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Procedure)
+	targetMap := make(map[models.ObjectIdentity]*models.Procedure)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Name: target[i].Name, Signature: models.RoutineSignature(target[i].Parameters)}] = &target[i]
 	}
 
 	// Check for removed procedures
-	for name, procedure := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, procedure := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Procedure",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      procedure,
 				Description: "Procedure exists in source but not in target",
 			})
@@ -648,12 +903,13 @@ func (c *Comparer) compareProcedures(source, target []models.Procedure) []models
 	}
 
 	// Check for added procedures
-	for name, procedure := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, procedure := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Procedure",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      procedure,
 				Description: "Procedure exists in target but not in source",
 			})
@@ -661,13 +917,14 @@ func (c *Comparer) compareProcedures(source, target []models.Procedure) []models
 	}
 
 	// Check for modified procedures
-	for name, sourceProc := range sourceMap {
-		if targetProc, exists := targetMap[name]; exists {
+	for id, sourceProc := range sourceMap {
+		if targetProc, exists := targetMap[id]; exists {
 			if sourceProc.Body != targetProc.Body {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Procedure",
-					ObjectName:  name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceProc,
 					Target:      targetProc,
 					Description: "Procedure definition changed",
@@ -688,23 +945,24 @@ func (c *Comparer) compareFunctions(source, target []models.Function) []models.D
 		target = c.filterFunctions(target)
 	}
 
-	sourceMap := make(map[string]*models.Function)
+	sourceMap := make(map[models.ObjectIdentity]*models.Function)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Name: source[i].Name, Signature: models.RoutineSignature(source[i].Parameters)}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Function)
+	targetMap := make(map[models.ObjectIdentity]*models.Function)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Name: target[i].Name, Signature: models.RoutineSignature(target[i].Parameters)}] = &target[i]
 	}
 
 	// Check for removed functions
-	for name, function := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, function := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Function",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      function,
 				Description: "Function exists in source but not in target",
 			})
@@ -712,12 +970,13 @@ func (c *Comparer) compareFunctions(source, target []models.Function) []models.D
 	}
 
 	// Check for added functions
-	for name, function := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, function := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Function",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      function,
 				Description: "Function exists in target but not in source",
 			})
@@ -725,13 +984,14 @@ func (c *Comparer) compareFunctions(source, target []models.Function) []models.D
 	}
 
 	// Check for modified functions
-	for name, sourceFunc := range sourceMap {
-		if targetFunc, exists := targetMap[name]; exists {
+	for id, sourceFunc := range sourceMap {
+		if targetFunc, exists := targetMap[id]; exists {
 			if sourceFunc.Body != targetFunc.Body || sourceFunc.ReturnType != targetFunc.ReturnType {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Function",
-					ObjectName:  name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceFunc,
 					Target:      targetFunc,
 					Description: "Function definition changed",
@@ -752,23 +1012,24 @@ func (c *Comparer) compareTriggers(source, target []models.Trigger) []models.Dif
 		target = c.filterTriggers(target)
 	}
 
-	sourceMap := make(map[string]*models.Trigger)
+	sourceMap := make(map[models.ObjectIdentity]*models.Trigger)
 	for i := range source {
-		sourceMap[source[i].Name] = &source[i]
+		sourceMap[models.ObjectIdentity{Table: source[i].TableName, Name: source[i].Name}] = &source[i]
 	}
 
-	targetMap := make(map[string]*models.Trigger)
+	targetMap := make(map[models.ObjectIdentity]*models.Trigger)
 	for i := range target {
-		targetMap[target[i].Name] = &target[i]
+		targetMap[models.ObjectIdentity{Table: target[i].TableName, Name: target[i].Name}] = &target[i]
 	}
 
 	// Check for removed triggers
-	for name, trigger := range sourceMap {
-		if _, exists := targetMap[name]; !exists {
+	for id, trigger := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Removed,
 				ObjectType:  "Trigger",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Source:      trigger,
 				Description: "Trigger exists in source but not in target",
 			})
@@ -776,12 +1037,13 @@ func (c *Comparer) compareTriggers(source, target []models.Trigger) []models.Dif
 	}
 
 	// Check for added triggers
-	for name, trigger := range targetMap {
-		if _, exists := sourceMap[name]; !exists {
+	for id, trigger := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
 			differences = append(differences, models.Difference{
 				Type:        models.Added,
 				ObjectType:  "Trigger",
-				ObjectName:  name,
+				ObjectName:  id.String(),
+				Identity:    id,
 				Target:      trigger,
 				Description: "Trigger exists in target but not in source",
 			})
@@ -789,13 +1051,14 @@ func (c *Comparer) compareTriggers(source, target []models.Trigger) []models.Dif
 	}
 
 	// Check for modified triggers
-	for name, sourceTrigger := range sourceMap {
-		if targetTrigger, exists := targetMap[name]; exists {
+	for id, sourceTrigger := range sourceMap {
+		if targetTrigger, exists := targetMap[id]; exists {
 			if !c.triggersEqual(sourceTrigger, targetTrigger) {
 				differences = append(differences, models.Difference{
 					Type:        models.Modified,
 					ObjectType:  "Trigger",
-					ObjectName:  name,
+					ObjectName:  id.String(),
+					Identity:    id,
 					Source:      sourceTrigger,
 					Target:      targetTrigger,
 					Description: "Trigger definition changed",
@@ -814,7 +1077,236 @@ func (c *Comparer) triggersEqual(source, target *models.Trigger) bool {
 		source.Body == target.Body
 }
 
+func (c *Comparer) compareSynonyms(source, target []models.Synonym) []models.Difference {
+	var differences []models.Difference
+
+	// Filter out ignored synonyms
+	if c.ignoreConfig != nil {
+		source = c.filterSynonyms(source)
+		target = c.filterSynonyms(target)
+	}
+
+	sourceMap := make(map[models.ObjectIdentity]*models.Synonym)
+	for i := range source {
+		sourceMap[models.ObjectIdentity{Name: source[i].Name}] = &source[i]
+	}
+
+	targetMap := make(map[models.ObjectIdentity]*models.Synonym)
+	for i := range target {
+		targetMap[models.ObjectIdentity{Name: target[i].Name}] = &target[i]
+	}
+
+	// Check for removed synonyms
+	for id, synonym := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Removed,
+				ObjectType:  "Synonym",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Source:      synonym,
+				Description: "Synonym exists in source but not in target",
+			})
+		}
+	}
+
+	// Check for added synonyms
+	for id, synonym := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Added,
+				ObjectType:  "Synonym",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Target:      synonym,
+				Description: "Synonym exists in target but not in source",
+			})
+		}
+	}
+
+	// Check for modified synonyms
+	for id, sourceSynonym := range sourceMap {
+		if targetSynonym, exists := targetMap[id]; exists {
+			if !c.synonymsEqual(sourceSynonym, targetSynonym) {
+				differences = append(differences, models.Difference{
+					Type:        models.Modified,
+					ObjectType:  "Synonym",
+					ObjectName:  id.String(),
+					Identity:    id,
+					Source:      sourceSynonym,
+					Target:      targetSynonym,
+					Description: "Synonym definition changed",
+				})
+			}
+		}
+	}
+
+	return differences
+}
+
+func (c *Comparer) synonymsEqual(source, target *models.Synonym) bool {
+	return strings.EqualFold(source.TargetOwner, target.TargetOwner) &&
+		strings.EqualFold(source.TargetObject, target.TargetObject) &&
+		strings.EqualFold(source.DBLink, target.DBLink)
+}
+
+func (c *Comparer) compareExtensions(source, target []models.Extension) []models.Difference {
+	var differences []models.Difference
+
+	// Filter out ignored extensions
+	if c.ignoreConfig != nil {
+		source = c.filterExtensions(source)
+		target = c.filterExtensions(target)
+	}
+
+	sourceMap := make(map[models.ObjectIdentity]*models.Extension)
+	for i := range source {
+		sourceMap[models.ObjectIdentity{Name: source[i].Name}] = &source[i]
+	}
+
+	targetMap := make(map[models.ObjectIdentity]*models.Extension)
+	for i := range target {
+		targetMap[models.ObjectIdentity{Name: target[i].Name}] = &target[i]
+	}
+
+	// Check for removed extensions
+	for id, extension := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Removed,
+				ObjectType:  "Extension",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Source:      extension,
+				Description: "Extension exists in source but not in target",
+			})
+		}
+	}
+
+	// Check for added extensions
+	for id, extension := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Added,
+				ObjectType:  "Extension",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Target:      extension,
+				Description: "Extension exists in target but not in source",
+			})
+		}
+	}
+
+	// Check for modified extensions
+	for id, sourceExtension := range sourceMap {
+		if targetExtension, exists := targetMap[id]; exists {
+			if !c.extensionsEqual(sourceExtension, targetExtension) {
+				differences = append(differences, models.Difference{
+					Type:        models.Modified,
+					ObjectType:  "Extension",
+					ObjectName:  id.String(),
+					Identity:    id,
+					Source:      sourceExtension,
+					Target:      targetExtension,
+					Description: "Extension definition changed",
+				})
+			}
+		}
+	}
+
+	return differences
+}
+
+func (c *Comparer) extensionsEqual(source, target *models.Extension) bool {
+	return source.Version == target.Version &&
+		source.Schema == target.Schema
+}
+
+func (c *Comparer) compareEvents(source, target []models.Event) []models.Difference {
+	var differences []models.Difference
+
+	// Filter out ignored events
+	if c.ignoreConfig != nil {
+		source = c.filterEvents(source)
+		target = c.filterEvents(target)
+	}
+
+	sourceMap := make(map[models.ObjectIdentity]*models.Event)
+	for i := range source {
+		sourceMap[models.ObjectIdentity{Name: source[i].Name}] = &source[i]
+	}
+
+	targetMap := make(map[models.ObjectIdentity]*models.Event)
+	for i := range target {
+		targetMap[models.ObjectIdentity{Name: target[i].Name}] = &target[i]
+	}
+
+	// Check for removed events
+	for id, event := range sourceMap {
+		if _, exists := targetMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Removed,
+				ObjectType:  "Event",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Source:      event,
+				Description: "Event exists in source but not in target",
+			})
+		}
+	}
+
+	// Check for added events
+	for id, event := range targetMap {
+		if _, exists := sourceMap[id]; !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Added,
+				ObjectType:  "Event",
+				ObjectName:  id.String(),
+				Identity:    id,
+				Target:      event,
+				Description: "Event exists in target but not in source",
+			})
+		}
+	}
+
+	// Check for modified events
+	for id, sourceEvent := range sourceMap {
+		if targetEvent, exists := targetMap[id]; exists {
+			if !c.eventsEqual(sourceEvent, targetEvent) {
+				differences = append(differences, models.Difference{
+					Type:        models.Modified,
+					ObjectType:  "Event",
+					ObjectName:  id.String(),
+					Identity:    id,
+					Source:      sourceEvent,
+					Target:      targetEvent,
+					Description: "Event definition changed",
+				})
+			}
+		}
+	}
+
+	return differences
+}
+
+func (c *Comparer) eventsEqual(source, target *models.Event) bool {
+	return source.Schedule == target.Schedule &&
+		source.Status == target.Status &&
+		source.OnCompletion == target.OnCompletion &&
+		source.Body == target.Body
+}
+
 // Filter methods for ignore patterns
+func (c *Comparer) filterSchemas(schemas []models.Schema) []models.Schema {
+	var filtered []models.Schema
+	for _, schema := range schemas {
+		if !c.ignoreConfig.ShouldIgnore("schema", schema.Name) {
+			filtered = append(filtered, schema)
+		}
+	}
+	return filtered
+}
+
 func (c *Comparer) filterTables(tables []models.Table) []models.Table {
 	var filtered []models.Table
 	for _, table := range tables {
@@ -823,6 +1315,7 @@ func (c *Comparer) filterTables(tables []models.Table) []models.Table {
 			table.Columns = c.filterColumns(table.Columns)
 			table.Constraints = c.filterConstraints(table.Constraints)
 			table.Indexes = c.filterIndexes(table.Indexes)
+			table.Policies = c.filterPolicies(table.Policies)
 			filtered = append(filtered, table)
 		}
 	}
@@ -859,6 +1352,16 @@ func (c *Comparer) filterIndexes(indexes []models.Index) []models.Index {
 	return filtered
 }
 
+func (c *Comparer) filterPolicies(policies []models.Policy) []models.Policy {
+	var filtered []models.Policy
+	for _, policy := range policies {
+		if !c.ignoreConfig.ShouldIgnore("policy", policy.Name) {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
+}
+
 func (c *Comparer) filterViews(views []models.View) []models.View {
 	var filtered []models.View
 	for _, view := range views {
@@ -908,3 +1411,33 @@ func (c *Comparer) filterTriggers(triggers []models.Trigger) []models.Trigger {
 	}
 	return filtered
 }
+
+func (c *Comparer) filterSynonyms(synonyms []models.Synonym) []models.Synonym {
+	var filtered []models.Synonym
+	for _, synonym := range synonyms {
+		if !c.ignoreConfig.ShouldIgnore("synonym", synonym.Name) {
+			filtered = append(filtered, synonym)
+		}
+	}
+	return filtered
+}
+
+func (c *Comparer) filterExtensions(extensions []models.Extension) []models.Extension {
+	var filtered []models.Extension
+	for _, extension := range extensions {
+		if !c.ignoreConfig.ShouldIgnore("extension", extension.Name) {
+			filtered = append(filtered, extension)
+		}
+	}
+	return filtered
+}
+
+func (c *Comparer) filterEvents(events []models.Event) []models.Event {
+	var filtered []models.Event
+	for _, event := range events {
+		if !c.ignoreConfig.ShouldIgnore("event", event.Name) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// SerialTx shares one snapshot transaction between the parallel fetchers of
+// a reader. A connection streams one result set at a time, so fetchers take
+// turns holding the transaction instead of running concurrently.
+type SerialTx struct {
+	tx *sql.Tx
+	mu sync.Mutex
+}
+
+// NewSerialTx wraps a transaction for shared use
+func NewSerialTx(tx *sql.Tx) *SerialTx {
+	return &SerialTx{tx: tx}
+}
+
+// Acquire waits for exclusive use of the transaction and returns a function
+// that hands it back
+func (s *SerialTx) Acquire() (*sql.Tx, func()) {
+	s.mu.Lock()
+	return s.tx, s.mu.Unlock
+}
+
+// Rollback ends the transaction once every fetcher is done with it
+func (s *SerialTx) Rollback() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tx.Rollback()
+}
@@ -0,0 +1,197 @@
+// Package fakesql provides an in-memory database/sql driver that serves
+// canned result sets and counts round trips. It lets the catalog readers be
+// exercised and benchmarked without a live database server.
+package fakesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Result is a canned result set returned for matching queries
+type Result struct {
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+type route struct {
+	fragment string
+	result   Result
+}
+
+// Driver answers each query with the result of the first registered route
+// whose fragment appears in the query text. Unmatched queries return an
+// empty result set.
+type Driver struct {
+	mu         sync.Mutex
+	routes     []route
+	statements []string
+}
+
+// New creates an empty fake driver
+func New() *Driver {
+	return &Driver{}
+}
+
+// Handle registers a result for queries containing fragment. Whitespace in
+// both the fragment and the query is collapsed before matching.
+func (d *Driver) Handle(fragment string, result Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.routes = append(d.routes, route{fragment: normalize(fragment), result: result})
+}
+
+// DB opens a *sql.DB backed by the fake driver
+func (d *Driver) DB() *sql.DB {
+	return sql.OpenDB(d.Connector())
+}
+
+// Connector returns a driver.Connector for the fake driver
+func (d *Driver) Connector() driver.Connector {
+	return connector{driver: d}
+}
+
+// RoundTrips returns the number of queries and statements executed so far
+func (d *Driver) RoundTrips() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.statements)
+}
+
+// Statements returns the text of every query and statement executed so far
+func (d *Driver) Statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.statements...)
+}
+
+// Reset clears the recorded statements
+func (d *Driver) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = nil
+}
+
+func (d *Driver) record(query string) Result {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	query = normalize(query)
+	d.statements = append(d.statements, query)
+	for _, r := range d.routes {
+		if strings.Contains(query, r.fragment) {
+			return r.result
+		}
+	}
+	return Result{}
+}
+
+// Open implements driver.Driver
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	return &conn{driver: d}, nil
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+type connector struct {
+	driver *Driver
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &conn{driver: c.driver}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.driver
+}
+
+type conn struct {
+	driver *Driver
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := c.driver.record(query)
+	return &rows{result: result}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.driver.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("fakesql: use ExecContext")
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fakesql: use QueryContext")
+}
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type rows struct {
+	result Result
+	pos    int
+}
+
+func (r *rows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.pos])
+	r.pos++
+	return nil
+}
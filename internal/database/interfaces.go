@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"github.com/nechja/schemalyzer/pkg/models"
 )
 
@@ -23,4 +24,18 @@ type OutputFormatter interface {
 type StatisticsReader interface {
 	GetTableRowCount(ctx context.Context, schemaName, tableName string) (int64, error)
 	GetColumnSamples(ctx context.Context, schemaName, tableName, columnName string, limit int) ([]string, error)
-}
\ No newline at end of file
+}
+
+// Queryer is the query surface shared by *sql.DB and *sql.Tx, so readers can
+// run the same catalog queries inside or outside a snapshot transaction
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SnapshotReader is implemented by readers that can pin every read between
+// BeginSnapshot and EndSnapshot to a single point in time
+type SnapshotReader interface {
+	BeginSnapshot(ctx context.Context) error
+	EndSnapshot() error
+}
@@ -3,40 +3,104 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/nechja/schemalyzer/internal/database"
 	"github.com/nechja/schemalyzer/pkg/models"
 	"strings"
 	"sync"
 )
 
 type MySQLReader struct {
-	db *sql.DB
+	db      *sql.DB
+	options database.ReaderOptions
+	// q runs catalog queries: the pool itself, or a snapshot transaction
+	q        database.Queryer
+	snapshot *database.SerialTx
 }
 
 func NewMySQLReader() *MySQLReader {
-	return &MySQLReader{}
+	return NewMySQLReaderWithOptions(database.DefaultReaderOptions())
+}
+
+// NewMySQLReaderWithOptions creates a reader with custom pool and timeout settings
+func NewMySQLReaderWithOptions(options database.ReaderOptions) *MySQLReader {
+	return &MySQLReader{options: options}
 }
 
 func (r *MySQLReader) Connect(ctx context.Context, connectionString string) error {
-	db, err := sql.Open("mysql", connectionString)
+	cfg, err := mysql.ParseDSN(connectionString)
 	if err != nil {
 		return fmt.Errorf("failed to connect to mysql: %w", err)
 	}
 
-	// Configure connection pool for large databases
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(0) // No timeout
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to mysql: %w", err)
+	}
+
+	return r.open(ctx, connector)
+}
+
+func (r *MySQLReader) open(ctx context.Context, connector driver.Connector) error {
+	// max_execution_time bounds read-only SELECTs, which is all a reader issues
+	var session []string
+	if r.options.StatementTimeout > 0 {
+		session = append(session, fmt.Sprintf("SET SESSION max_execution_time = %d", r.options.StatementTimeout.Milliseconds()))
+	}
+
+	db := sql.OpenDB(database.SessionConnector(connector, session...))
+	database.ConfigurePool(db, r.options)
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return fmt.Errorf("failed to ping mysql: %w", err)
 	}
 
 	r.db = db
+	r.q = db
 	return nil
 }
 
+// BeginSnapshot pins every subsequent read to one repeatable-read, read-only
+// transaction. The fetchers share its single connection and take turns, so
+// a snapshot read is serial rather than parallel.
+func (r *MySQLReader) BeginSnapshot(ctx context.Context) error {
+	if r.snapshot != nil {
+		return fmt.Errorf("snapshot already in progress")
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+
+	r.snapshot = database.NewSerialTx(tx)
+	return nil
+}
+
+// EndSnapshot releases the snapshot transaction
+func (r *MySQLReader) EndSnapshot() error {
+	if r.snapshot == nil {
+		return nil
+	}
+	err := r.snapshot.Rollback()
+	r.snapshot = nil
+	return err
+}
+
+// fetcher returns the reader one GetSchema fetcher should query through,
+// waiting for its turn on the snapshot transaction when one is active
+func (r *MySQLReader) fetcher(ctx context.Context) (*MySQLReader, func(), error) {
+	if r.snapshot == nil {
+		return r, func() {}, nil
+	}
+
+	tx, release := r.snapshot.Acquire()
+	return &MySQLReader{db: r.db, options: r.options, q: tx}, release, nil
+}
+
 func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
 	schema := &models.Schema{
 		Name:         schemaName,
@@ -51,6 +115,7 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 		functions  []models.Function
 		procedures []models.Procedure
 		triggers   []models.Trigger
+		events     []models.Event
 		err        error
 	}
 
@@ -58,11 +123,17 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 	res := &result{}
 
 	// Fetch schema objects in parallel
-	wg.Add(5) // MySQL doesn't have sequences traditionally
+	wg.Add(6) // MySQL doesn't have sequences traditionally
 
 	go func() {
 		defer wg.Done()
-		tables, err := r.getTables(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		tables, err := reader.getTables(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get tables: %w", err)
 			return
@@ -72,7 +143,13 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 
 	go func() {
 		defer wg.Done()
-		views, err := r.getViews(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		views, err := reader.getViews(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get views: %w", err)
 			return
@@ -85,7 +162,13 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 
 	go func() {
 		defer wg.Done()
-		functions, err := r.getFunctions(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		functions, err := reader.getFunctions(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get functions: %w", err)
 			return
@@ -95,7 +178,13 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 
 	go func() {
 		defer wg.Done()
-		procedures, err := r.getProcedures(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		procedures, err := reader.getProcedures(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get procedures: %w", err)
 			return
@@ -105,7 +194,13 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 
 	go func() {
 		defer wg.Done()
-		triggers, err := r.getTriggers(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		triggers, err := reader.getTriggers(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get triggers: %w", err)
 			return
@@ -113,6 +208,22 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 		res.triggers = triggers
 	}()
 
+	go func() {
+		defer wg.Done()
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		events, err := reader.getEvents(ctx, schemaName)
+		if err != nil {
+			res.err = fmt.Errorf("failed to get events: %w", err)
+			return
+		}
+		res.events = events
+	}()
+
 	wg.Wait()
 
 	if res.err != nil {
@@ -125,6 +236,7 @@ func (r *MySQLReader) GetSchema(ctx context.Context, schemaName string) (*models
 	schema.Functions = res.functions
 	schema.Procedures = res.procedures
 	schema.Triggers = res.triggers
+	schema.Events = res.events
 
 	return schema, nil
 }
@@ -136,7 +248,7 @@ func (r *MySQLReader) ListSchemas(ctx context.Context) ([]string, error) {
 		WHERE schema_name NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
 		ORDER BY schema_name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list schemas: %w", err)
 	}
@@ -153,7 +265,6 @@ func (r *MySQLReader) ListSchemas(ctx context.Context) ([]string, error) {
 
 	return schemas, nil
 }
-
 func (r *MySQLReader) getTables(ctx context.Context, schemaName string) ([]models.Table, error) {
 	query := `
 		SELECT table_name, table_comment
@@ -161,7 +272,7 @@ func (r *MySQLReader) getTables(ctx context.Context, schemaName string) ([]model
 		WHERE table_schema = ? AND table_type = 'BASE TABLE'
 		ORDER BY table_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -176,34 +287,50 @@ func (r *MySQLReader) getTables(ctx context.Context, schemaName string) ([]model
 		}
 
 		table.Schema = schemaName
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		columns, err := r.getColumns(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get columns for table %s: %w", table.Name, err)
-		}
-		table.Columns = columns
+	if len(tables) == 0 {
+		return tables, nil
+	}
 
-		constraints, err := r.getConstraints(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get constraints for table %s: %w", table.Name, err)
-		}
-		table.Constraints = constraints
+	// Fetch each catalog category once for the whole schema and assemble
+	// the tables in memory, rather than issuing a round trip per table
+	columns, err := r.getColumns(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
 
-		indexes, err := r.getIndexes(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get indexes for table %s: %w", table.Name, err)
-		}
-		table.Indexes = indexes
+	constraints, err := r.getConstraints(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get constraints: %w", err)
+	}
 
-		tables = append(tables, table)
+	indexes, err := r.getIndexes(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+
+	for i := range tables {
+		name := tables[i].Name
+		tables[i].Columns = columns[name]
+		tables[i].Constraints = constraints[name]
+		tables[i].Indexes = indexes[name]
 	}
 
 	return tables, nil
 }
 
-func (r *MySQLReader) getColumns(ctx context.Context, schemaName, tableName string) ([]models.Column, error) {
+// getColumns returns the columns of every table and view in the schema,
+// keyed by table name
+func (r *MySQLReader) getColumns(ctx context.Context, schemaName string) (map[string][]models.Column, error) {
 	query := `
 		SELECT 
+			table_name,
 			column_name,
 			column_type,
 			is_nullable,
@@ -213,22 +340,23 @@ func (r *MySQLReader) getColumns(ctx context.Context, schemaName, tableName stri
 			column_key,
 			extra
 		FROM information_schema.columns
-		WHERE table_schema = ? AND table_name = ?
-		ORDER BY ordinal_position`
+		WHERE table_schema = ?
+		ORDER BY table_name, ordinal_position`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName, tableName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var columns []models.Column
+	columns := make(map[string][]models.Column)
 	for rows.Next() {
+		var tableName string
 		var col models.Column
 		var isNullable, columnKey, extra string
 		var defaultValue sql.NullString
 
-		if err := rows.Scan(&col.Name, &col.DataType, &isNullable, &defaultValue,
+		if err := rows.Scan(&tableName, &col.Name, &col.DataType, &isNullable, &defaultValue,
 			&col.Position, &col.Comment, &columnKey, &extra); err != nil {
 			return nil, err
 		}
@@ -248,80 +376,87 @@ func (r *MySQLReader) getColumns(ctx context.Context, schemaName, tableName stri
 			col.IsAutoIncrement = true
 		}
 
-		columns = append(columns, col)
+		columns[tableName] = append(columns[tableName], col)
 	}
 
-	return columns, nil
+	return columns, rows.Err()
 }
 
-func (r *MySQLReader) getConstraints(ctx context.Context, schemaName, tableName string) ([]models.Constraint, error) {
+// getConstraints returns the constraints of every table in the schema,
+// keyed by table name
+func (r *MySQLReader) getConstraints(ctx context.Context, schemaName string) (map[string][]models.Constraint, error) {
+	// Every MySQL primary key is named PRIMARY, so constraints are keyed by
+	// table as well as name
+	type constraintKey struct {
+		table, name string
+	}
+	constraints := make(map[string][]models.Constraint)
+
 	// Primary Key and Unique constraints
 	uniqueQuery := `
 		SELECT 
-			constraint_name,
-			constraint_type
-		FROM information_schema.table_constraints
-		WHERE table_schema = ? AND table_name = ?
-		AND constraint_type IN ('PRIMARY KEY', 'UNIQUE')`
-
-	rows, err := r.db.QueryContext(ctx, uniqueQuery, schemaName, tableName)
+			tc.table_name,
+			tc.constraint_name,
+			tc.constraint_type,
+			kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.table_name = tc.table_name
+			AND kcu.constraint_name = tc.constraint_name
+		WHERE tc.table_schema = ?
+		AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+		ORDER BY tc.table_name, tc.constraint_name, kcu.ordinal_position`
+
+	rows, err := r.q.QueryContext(ctx, uniqueQuery, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var constraints []models.Constraint
-	constraintMap := make(map[string]*models.Constraint)
+	constraintMap := make(map[constraintKey]*models.Constraint)
+	var order []constraintKey
 
 	for rows.Next() {
-		var constraint models.Constraint
-		var constraintType string
+		var tableName, constraintName, constraintType, columnName string
 
-		if err := rows.Scan(&constraint.Name, &constraintType); err != nil {
+		if err := rows.Scan(&tableName, &constraintName, &constraintType, &columnName); err != nil {
 			return nil, err
 		}
 
-		switch constraintType {
-		case "PRIMARY KEY":
-			constraint.Type = models.PrimaryKey
-		case "UNIQUE":
-			constraint.Type = models.Unique
-		}
-
-		constraintMap[constraint.Name] = &constraint
-	}
+		key := constraintKey{table: tableName, name: constraintName}
+		if _, exists := constraintMap[key]; !exists {
+			constraint := &models.Constraint{Name: constraintName}
 
-	// Get columns for each constraint
-	for name, constraint := range constraintMap {
-		columnQuery := `
-			SELECT column_name
-			FROM information_schema.key_column_usage
-			WHERE table_schema = ? AND table_name = ? AND constraint_name = ?
-			ORDER BY ordinal_position`
+			switch constraintType {
+			case "PRIMARY KEY":
+				constraint.Type = models.PrimaryKey
+			case "UNIQUE":
+				constraint.Type = models.Unique
+			}
 
-		colRows, err := r.db.QueryContext(ctx, columnQuery, schemaName, tableName, name)
-		if err != nil {
-			return nil, err
+			constraintMap[key] = constraint
+			order = append(order, key)
 		}
 
-		for colRows.Next() {
-			var columnName string
-			if err := colRows.Scan(&columnName); err != nil {
-				colRows.Close()
-				return nil, err
-			}
-			constraint.Columns = append(constraint.Columns, columnName)
-		}
-		colRows.Close()
+		constraintMap[key].Columns = append(constraintMap[key].Columns, columnName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		constraints = append(constraints, *constraint)
+	for _, key := range order {
+		constraints[key.table] = append(constraints[key.table], *constraintMap[key])
 	}
 
 	// Foreign Key constraints
 	fkQuery := `
 		SELECT 
+			kcu.table_name,
 			kcu.constraint_name,
 			kcu.column_name,
+			kcu.referenced_table_schema,
 			kcu.referenced_table_name,
 			kcu.referenced_column_name,
 			rc.update_rule,
@@ -331,142 +466,143 @@ func (r *MySQLReader) getConstraints(ctx context.Context, schemaName, tableName
 			ON rc.constraint_schema = kcu.constraint_schema
 			AND rc.constraint_name = kcu.constraint_name
 		WHERE kcu.table_schema = ? 
-		AND kcu.table_name = ?
 		AND kcu.referenced_table_name IS NOT NULL
-		ORDER BY kcu.constraint_name, kcu.ordinal_position`
+		ORDER BY kcu.table_name, kcu.constraint_name, kcu.ordinal_position`
 
-	fkRows, err := r.db.QueryContext(ctx, fkQuery, schemaName, tableName)
+	fkRows, err := r.q.QueryContext(ctx, fkQuery, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer fkRows.Close()
 
-	fkMap := make(map[string]*models.Constraint)
+	fkMap := make(map[constraintKey]*models.Constraint)
+	var fkOrder []constraintKey
 
 	for fkRows.Next() {
-		var constraintName, columnName, refTable, refColumn, updateRule, deleteRule string
+		var tableName, constraintName, columnName, refSchema, refTable, refColumn, updateRule, deleteRule string
 
-		if err := fkRows.Scan(&constraintName, &columnName, &refTable, &refColumn, &updateRule, &deleteRule); err != nil {
+		if err := fkRows.Scan(&tableName, &constraintName, &columnName, &refSchema, &refTable, &refColumn, &updateRule, &deleteRule); err != nil {
 			return nil, err
 		}
 
-		if _, exists := fkMap[constraintName]; !exists {
-			fkMap[constraintName] = &models.Constraint{
+		key := constraintKey{table: tableName, name: constraintName}
+		if _, exists := fkMap[key]; !exists {
+			if refSchema == schemaName {
+				refSchema = ""
+			}
+			fkMap[key] = &models.Constraint{
 				Name:             constraintName,
 				Type:             models.ForeignKey,
 				Columns:          []string{},
+				ReferencedSchema: refSchema,
 				ReferencedTable:  refTable,
 				ReferencedColumn: []string{},
 				OnUpdate:         strings.ToUpper(updateRule),
 				OnDelete:         strings.ToUpper(deleteRule),
 			}
+			fkOrder = append(fkOrder, key)
 		}
 
-		fkMap[constraintName].Columns = append(fkMap[constraintName].Columns, columnName)
-		fkMap[constraintName].ReferencedColumn = append(fkMap[constraintName].ReferencedColumn, refColumn)
+		fkMap[key].Columns = append(fkMap[key].Columns, columnName)
+		fkMap[key].ReferencedColumn = append(fkMap[key].ReferencedColumn, refColumn)
+	}
+	if err := fkRows.Err(); err != nil {
+		return nil, err
 	}
+	fkRows.Close()
 
-	for _, fk := range fkMap {
-		constraints = append(constraints, *fk)
+	for _, key := range fkOrder {
+		constraints[key.table] = append(constraints[key.table], *fkMap[key])
 	}
 
 	// Check constraints (MySQL 8.0.16+)
 	checkQuery := `
 		SELECT 
+			tc.table_name,
 			tc.constraint_name,
 			cc.check_clause
 		FROM information_schema.table_constraints tc
 		JOIN information_schema.check_constraints cc 
 			ON tc.constraint_name = cc.constraint_name 
 			AND tc.constraint_schema = cc.constraint_schema
-		WHERE tc.table_schema = ? AND tc.table_name = ?
-		AND tc.constraint_type = 'CHECK'`
+		WHERE tc.table_schema = ?
+		AND tc.constraint_type = 'CHECK'
+		ORDER BY tc.table_name, tc.constraint_name`
 
-	checkRows, err := r.db.QueryContext(ctx, checkQuery, schemaName, tableName)
+	checkRows, err := r.q.QueryContext(ctx, checkQuery, schemaName)
 	if err == nil {
 		defer checkRows.Close()
 
 		for checkRows.Next() {
+			var tableName string
 			var constraint models.Constraint
 
-			if err := checkRows.Scan(&constraint.Name, &constraint.CheckExpression); err != nil {
+			if err := checkRows.Scan(&tableName, &constraint.Name, &constraint.CheckExpression); err != nil {
 				continue
 			}
 
 			constraint.Type = models.Check
-			constraints = append(constraints, constraint)
+			constraints[tableName] = append(constraints[tableName], constraint)
 		}
 	}
 
 	return constraints, nil
 }
 
-func (r *MySQLReader) getIndexes(ctx context.Context, schemaName, tableName string) ([]models.Index, error) {
+// getIndexes returns the non-primary indexes of every table in the schema,
+// keyed by table name
+func (r *MySQLReader) getIndexes(ctx context.Context, schemaName string) (map[string][]models.Index, error) {
 	query := `
-		SELECT DISTINCT
+		SELECT 
+			table_name,
 			index_name,
 			non_unique,
-			index_type
+			index_type,
+			column_name
 		FROM information_schema.statistics
-		WHERE table_schema = ? AND table_name = ?
+		WHERE table_schema = ?
 		AND index_name != 'PRIMARY'
-		ORDER BY index_name`
+		ORDER BY table_name, index_name, seq_in_index`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName, tableName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	indexMap := make(map[string]*models.Index)
+	indexes := make(map[string][]models.Index)
 
 	for rows.Next() {
-		var indexName, indexType string
+		var tableName, indexName, indexType string
+		var columnName sql.NullString
 		var nonUnique int
 
-		if err := rows.Scan(&indexName, &nonUnique, &indexType); err != nil {
+		if err := rows.Scan(&tableName, &indexName, &nonUnique, &indexType, &columnName); err != nil {
 			return nil, err
 		}
 
-		indexMap[indexName] = &models.Index{
-			Name:      indexName,
-			TableName: tableName,
-			IsUnique:  nonUnique == 0,
-			Type:      indexType,
-			Columns:   []string{},
+		// Rows arrive grouped by table and index, so a new index starts
+		// whenever the name changes
+		tableIndexes := indexes[tableName]
+		if n := len(tableIndexes); n == 0 || tableIndexes[n-1].Name != indexName {
+			tableIndexes = append(tableIndexes, models.Index{
+				Name:      indexName,
+				TableName: tableName,
+				IsUnique:  nonUnique == 0,
+				Type:      indexType,
+				Columns:   []string{},
+			})
 		}
-	}
-
-	// Get columns for each index
-	for name, index := range indexMap {
-		colQuery := `
-			SELECT column_name
-			FROM information_schema.statistics
-			WHERE table_schema = ? AND table_name = ? AND index_name = ?
-			ORDER BY seq_in_index`
 
-		colRows, err := r.db.QueryContext(ctx, colQuery, schemaName, tableName, name)
-		if err != nil {
-			return nil, err
+		// Functional index parts have no column name
+		if columnName.Valid {
+			last := &tableIndexes[len(tableIndexes)-1]
+			last.Columns = append(last.Columns, columnName.String)
 		}
-
-		for colRows.Next() {
-			var columnName string
-			if err := colRows.Scan(&columnName); err != nil {
-				colRows.Close()
-				return nil, err
-			}
-			index.Columns = append(index.Columns, columnName)
-		}
-		colRows.Close()
-	}
-
-	var indexes []models.Index
-	for _, index := range indexMap {
-		indexes = append(indexes, *index)
+		indexes[tableName] = tableIndexes
 	}
 
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
 func (r *MySQLReader) getViews(ctx context.Context, schemaName string) ([]models.View, error) {
@@ -476,7 +612,7 @@ func (r *MySQLReader) getViews(ctx context.Context, schemaName string) ([]models
 		WHERE table_schema = ?
 		ORDER BY table_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -489,14 +625,23 @@ func (r *MySQLReader) getViews(ctx context.Context, schemaName string) ([]models
 			return nil, err
 		}
 		view.Schema = schemaName
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		columns, err := r.getColumns(ctx, schemaName, view.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get columns for view %s: %w", view.Name, err)
-		}
-		view.Columns = columns
+	if len(views) == 0 {
+		return views, nil
+	}
 
-		views = append(views, view)
+	columns, err := r.getColumns(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view columns: %w", err)
+	}
+	for i := range views {
+		views[i].Columns = columns[views[i].Name]
 	}
 
 	return views, nil
@@ -512,7 +657,7 @@ func (r *MySQLReader) getFunctions(ctx context.Context, schemaName string) ([]mo
 		WHERE routine_schema = ? AND routine_type = 'FUNCTION'
 		ORDER BY routine_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -547,7 +692,7 @@ func (r *MySQLReader) getProcedures(ctx context.Context, schemaName string) ([]m
 		WHERE routine_schema = ? AND routine_type = 'PROCEDURE'
 		ORDER BY routine_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -585,7 +730,7 @@ func (r *MySQLReader) getTriggers(ctx context.Context, schemaName string) ([]mod
 		WHERE trigger_schema = ?
 		ORDER BY trigger_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -624,8 +769,56 @@ func (r *MySQLReader) getTriggers(ctx context.Context, schemaName string) ([]mod
 	return triggers, nil
 }
 
+func (r *MySQLReader) getEvents(ctx context.Context, schemaName string) ([]models.Event, error) {
+	query := `
+		SELECT 
+			event_name,
+			event_type,
+			execute_at,
+			interval_value,
+			interval_field,
+			status,
+			on_completion,
+			event_definition
+		FROM information_schema.events
+		WHERE event_schema = ?
+		ORDER BY event_name`
+
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		var eventType string
+		var executeAt, intervalValue, intervalField sql.NullString
+
+		if err := rows.Scan(&event.Name, &eventType, &executeAt, &intervalValue, &intervalField,
+			&event.Status, &event.OnCompletion, &event.Body); err != nil {
+			return nil, err
+		}
+
+		event.Schema = schemaName
+
+		// Describe the schedule the way CREATE EVENT spells it
+		if strings.EqualFold(eventType, "ONE TIME") {
+			event.Schedule = "AT " + executeAt.String
+		} else {
+			event.Schedule = fmt.Sprintf("EVERY %s %s", intervalValue.String, intervalField.String)
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
 func (r *MySQLReader) Close() error {
 	if r.db != nil {
+		r.EndSnapshot()
 		return r.db.Close()
 	}
 	return nil
@@ -638,7 +831,7 @@ func (r *MySQLReader) GetTableRowCount(ctx context.Context, schemaName, tableNam
 	// MySQL uses backticks for identifier quoting
 	query := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`", schemaName, tableName)
 
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.q.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get row count: %w", err)
 	}
@@ -657,7 +850,7 @@ func (r *MySQLReader) GetColumnSamples(ctx context.Context, schemaName, tableNam
 		LIMIT %d
 	`, columnName, schemaName, tableName, columnName, limit)
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get column samples: %w", err)
 	}
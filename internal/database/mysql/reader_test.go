@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/database"
+	"github.com/nechja/schemalyzer/internal/database/fakesql"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeSchema serves a schema with the given number of tables, each with
+// a primary key and a two-column index
+func newFakeSchema(tables int) *fakesql.Driver {
+	fake := fakesql.New()
+
+	var tableRows, columnRows, keyRows, indexRows [][]driver.Value
+	for i := 0; i < tables; i++ {
+		name := fmt.Sprintf("table_%04d", i)
+		tableRows = append(tableRows, []driver.Value{name, ""})
+		columnRows = append(columnRows,
+			[]driver.Value{name, "id", "int", "NO", nil, int64(1), "", "PRI", "auto_increment"},
+			[]driver.Value{name, "code", "varchar(10)", "YES", nil, int64(2), "", "", ""})
+		keyRows = append(keyRows, []driver.Value{name, "PRIMARY", "PRIMARY KEY", "id"})
+		indexRows = append(indexRows,
+			[]driver.Value{name, name + "_idx", int64(1), "BTREE", "code"},
+			[]driver.Value{name, name + "_idx", int64(1), "BTREE", "id"})
+	}
+
+	fake.Handle("FROM information_schema.tables", fakesql.Result{
+		Columns: []string{"table_name", "table_comment"},
+		Rows:    tableRows,
+	})
+	fake.Handle("FROM information_schema.columns", fakesql.Result{
+		Columns: []string{"table_name", "column_name", "column_type", "is_nullable", "column_default", "ordinal_position", "column_comment", "column_key", "extra"},
+		Rows:    columnRows,
+	})
+	fake.Handle("constraint_type IN ('PRIMARY KEY', 'UNIQUE')", fakesql.Result{
+		Columns: []string{"table_name", "constraint_name", "constraint_type", "column_name"},
+		Rows:    keyRows,
+	})
+	fake.Handle("FROM information_schema.statistics", fakesql.Result{
+		Columns: []string{"table_name", "index_name", "non_unique", "index_type", "column_name"},
+		Rows:    indexRows,
+	})
+	return fake
+}
+
+func TestGetSchemaAssemblesBatchedCatalog(t *testing.T) {
+	fake := newFakeSchema(3)
+	reader := newTestReader(fake)
+
+	schema, err := reader.GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+	assert.Len(t, schema.Tables, 3)
+
+	for _, table := range schema.Tables {
+		if assert.Len(t, table.Columns, 2) {
+			assert.True(t, table.Columns[0].IsPrimaryKey)
+			assert.True(t, table.Columns[0].IsAutoIncrement)
+		}
+		if assert.Len(t, table.Constraints, 1) {
+			assert.Equal(t, "PRIMARY", table.Constraints[0].Name)
+			assert.Equal(t, []string{"id"}, table.Constraints[0].Columns)
+		}
+		if assert.Len(t, table.Indexes, 1) {
+			assert.Equal(t, table.Name, table.Indexes[0].TableName)
+			assert.Equal(t, []string{"code", "id"}, table.Indexes[0].Columns)
+		}
+	}
+}
+
+func TestGetSchemaRoundTripsIndependentOfTableCount(t *testing.T) {
+	small := newFakeSchema(2)
+	_, err := newTestReader(small).GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+
+	large := newFakeSchema(200)
+	_, err = newTestReader(large).GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+
+	assert.Equal(t, small.RoundTrips(), large.RoundTrips())
+}
+
+func BenchmarkGetSchema(b *testing.B) {
+	for _, tables := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("tables=%d", tables), func(b *testing.B) {
+			fake := newFakeSchema(tables)
+			reader := newTestReader(fake)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := reader.GetSchema(context.Background(), "app"); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(fake.RoundTrips())/float64(b.N), "roundtrips/op")
+		})
+	}
+}
+
+func TestConnectAppliesStatementTimeout(t *testing.T) {
+	fake := fakesql.New()
+	reader := NewMySQLReaderWithOptions(database.ReaderOptions{MaxOpenConns: 1, StatementTimeout: 2 * time.Second})
+
+	assert.NoError(t, reader.open(context.Background(), fake.Connector()))
+	defer reader.Close()
+
+	assert.Equal(t, []string{"SET SESSION max_execution_time = 2000"}, fake.Statements())
+}
+
+func newTestReader(fake *fakesql.Driver) *MySQLReader {
+	db := fake.DB()
+	return &MySQLReader{db: db, options: database.DefaultReaderOptions(), q: db}
+}
+
+func TestSnapshotReadsMatchPooledReads(t *testing.T) {
+	pooled, err := newTestReader(newFakeSchema(3)).GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+
+	reader := newTestReader(newFakeSchema(3))
+	assert.NoError(t, reader.BeginSnapshot(context.Background()))
+	snapshot, err := reader.GetSchema(context.Background(), "app")
+	assert.NoError(t, reader.EndSnapshot())
+	assert.NoError(t, err)
+
+	assert.Equal(t, pooled, snapshot)
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// ReaderOptions tunes the connection pool and query limits of a SchemaReader
+type ReaderOptions struct {
+	// MaxOpenConns caps the pool size; zero means unlimited
+	MaxOpenConns int
+	// MaxIdleConns is the number of idle connections kept in the pool
+	MaxIdleConns int
+	// ConnMaxLifetime recycles connections older than this; zero keeps them forever
+	ConnMaxLifetime time.Duration
+	// StatementTimeout aborts any single catalog query running longer than
+	// this; zero disables the limit
+	StatementTimeout time.Duration
+}
+
+// DefaultReaderOptions returns the pool settings used for large databases
+func DefaultReaderOptions() ReaderOptions {
+	return ReaderOptions{
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 0,
+	}
+}
+
+// ConfigurePool applies the pool settings to db
+func ConfigurePool(db *sql.DB, opts ReaderOptions) {
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+}
+
+// SessionConnector wraps a driver connector so that every new connection
+// runs the given statements before it is handed to the pool. Readers use it
+// to apply session settings such as statement timeouts to all pooled
+// connections rather than only the first.
+func SessionConnector(connector driver.Connector, statements ...string) driver.Connector {
+	if len(statements) == 0 {
+		return connector
+	}
+	return &sessionConnector{Connector: connector, statements: statements}
+}
+
+type sessionConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range c.statements {
+		if err := execSession(ctx, conn, statement); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to initialize session with %q: %w", statement, err)
+		}
+	}
+
+	return conn, nil
+}
+
+func execSession(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	stmt, err := conn.Prepare(statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = stmt.Exec(nil)
+	return err
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/nechja/schemalyzer/internal/database/fakesql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionConnectorInitializesEveryConnection(t *testing.T) {
+	fake := fakesql.New()
+	db := sql.OpenDB(SessionConnector(fake.Connector(), "SET statement_timeout = 1000"))
+	defer db.Close()
+	db.SetMaxIdleConns(0)
+
+	for i := 0; i < 2; i++ {
+		rows, err := db.QueryContext(context.Background(), "SELECT 1")
+		assert.NoError(t, err)
+		rows.Close()
+	}
+
+	// Without idle connections each query opens, and initializes, a new session
+	assert.Equal(t, []string{
+		"SET statement_timeout = 1000", "SELECT 1",
+		"SET statement_timeout = 1000", "SELECT 1",
+	}, fake.Statements())
+}
+
+func TestGetDatabaseStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := GetDatabase(ctx, &fakeReader{}, "*", 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -3,41 +3,124 @@ package oracle
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"github.com/nechja/schemalyzer/internal/database"
 	"github.com/nechja/schemalyzer/pkg/models"
-	_ "github.com/sijms/go-ora/v2"
+	go_ora "github.com/sijms/go-ora/v2"
 	"strconv"
 	"strings"
 	"sync"
 )
 
 type OracleReader struct {
-	db *sql.DB
+	db      *sql.DB
+	options database.ReaderOptions
+	// q runs catalog queries: the pool itself, or a snapshot transaction
+	q        database.Queryer
+	snapshot *database.SerialTx
 }
 
 func NewOracleReader() *OracleReader {
-	return &OracleReader{}
+	return NewOracleReaderWithOptions(database.DefaultReaderOptions())
+}
+
+// NewOracleReaderWithOptions creates a reader with custom pool and timeout settings
+func NewOracleReaderWithOptions(options database.ReaderOptions) *OracleReader {
+	return &OracleReader{options: options}
 }
 
 func (r *OracleReader) Connect(ctx context.Context, connectionString string) error {
-	db, err := sql.Open("oracle", connectionString)
-	if err != nil {
-		return fmt.Errorf("failed to connect to oracle: %w", err)
-	}
+	return r.open(ctx, go_ora.NewConnector(connectionString))
+}
 
-	// Configure connection pool for large databases
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(0) // No timeout
+func (r *OracleReader) open(ctx context.Context, connector driver.Connector) error {
+	db := sql.OpenDB(connector)
+	database.ConfigurePool(db, r.options)
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return fmt.Errorf("failed to ping oracle: %w", err)
 	}
 
 	r.db = db
+	r.q = db
+	return nil
+}
+
+// timedRows releases the statement deadline once the caller closes the rows
+type timedRows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+func (t *timedRows) Close() error {
+	defer t.cancel()
+	return t.Rows.Close()
+}
+
+// query runs a catalog query under the statement timeout. Oracle has no
+// session-level statement timeout, so the deadline is enforced on the
+// client; go-ora interrupts the server call when the context expires.
+func (r *OracleReader) query(ctx context.Context, query string, args ...interface{}) (*timedRows, error) {
+	cancel := context.CancelFunc(func() {})
+	if r.options.StatementTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.options.StatementTimeout)
+	}
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timedRows{Rows: rows, cancel: cancel}, nil
+}
+
+// BeginSnapshot pins every subsequent read to one read-only transaction,
+// which Oracle serves with transaction-level read consistency. The fetchers
+// share its single connection and take turns, so a snapshot read is serial
+// rather than parallel.
+func (r *OracleReader) BeginSnapshot(ctx context.Context) error {
+	if r.snapshot != nil {
+		return fmt.Errorf("snapshot already in progress")
+	}
+
+	// go-ora rejects read-only TxOptions, so the transaction is made
+	// read-only by its first statement instead
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "SET TRANSACTION READ ONLY"); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+
+	r.snapshot = database.NewSerialTx(tx)
 	return nil
 }
 
+// EndSnapshot releases the snapshot transaction
+func (r *OracleReader) EndSnapshot() error {
+	if r.snapshot == nil {
+		return nil
+	}
+	err := r.snapshot.Rollback()
+	r.snapshot = nil
+	return err
+}
+
+// fetcher returns the reader one GetSchema fetcher should query through,
+// waiting for its turn on the snapshot transaction when one is active
+func (r *OracleReader) fetcher(ctx context.Context) (*OracleReader, func(), error) {
+	if r.snapshot == nil {
+		return r, func() {}, nil
+	}
+
+	tx, release := r.snapshot.Acquire()
+	return &OracleReader{db: r.db, options: r.options, q: tx}, release, nil
+}
+
 func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
 	schema := &models.Schema{
 		Name:         schemaName,
@@ -52,6 +135,7 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 		functions  []models.Function
 		procedures []models.Procedure
 		triggers   []models.Trigger
+		synonyms   []models.Synonym
 		err        error
 	}
 
@@ -59,11 +143,17 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 	res := &result{}
 
 	// Fetch schema objects in parallel
-	wg.Add(6)
+	wg.Add(7)
 
 	go func() {
 		defer wg.Done()
-		tables, err := r.getTables(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		tables, err := reader.getTables(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get tables: %w", err)
 			return
@@ -73,7 +163,13 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 
 	go func() {
 		defer wg.Done()
-		views, err := r.getViews(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		views, err := reader.getViews(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get views: %w", err)
 			return
@@ -83,7 +179,13 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 
 	go func() {
 		defer wg.Done()
-		sequences, err := r.getSequences(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		sequences, err := reader.getSequences(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get sequences: %w", err)
 			return
@@ -93,7 +195,13 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 
 	go func() {
 		defer wg.Done()
-		functions, err := r.getFunctions(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		functions, err := reader.getFunctions(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get functions: %w", err)
 			return
@@ -103,7 +211,13 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 
 	go func() {
 		defer wg.Done()
-		procedures, err := r.getProcedures(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		procedures, err := reader.getProcedures(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get procedures: %w", err)
 			return
@@ -113,7 +227,13 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 
 	go func() {
 		defer wg.Done()
-		triggers, err := r.getTriggers(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		triggers, err := reader.getTriggers(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get triggers: %w", err)
 			return
@@ -121,6 +241,22 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 		res.triggers = triggers
 	}()
 
+	go func() {
+		defer wg.Done()
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		synonyms, err := reader.getSynonyms(ctx, schemaName)
+		if err != nil {
+			res.err = fmt.Errorf("failed to get synonyms: %w", err)
+			return
+		}
+		res.synonyms = synonyms
+	}()
+
 	wg.Wait()
 
 	if res.err != nil {
@@ -133,6 +269,7 @@ func (r *OracleReader) GetSchema(ctx context.Context, schemaName string) (*model
 	schema.Functions = res.functions
 	schema.Procedures = res.procedures
 	schema.Triggers = res.triggers
+	schema.Synonyms = res.synonyms
 
 	return schema, nil
 }
@@ -147,7 +284,7 @@ func (r *OracleReader) ListSchemas(ctx context.Context) ([]string, error) {
 			'OLAPSYS', 'ORACLE_OCM', 'XS$NULL', 'BI', 'PM', 'MDDATA', 'IX', 'SH', 'DIP')
 		ORDER BY username`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list schemas: %w", err)
 	}
@@ -173,7 +310,7 @@ func (r *OracleReader) getTables(ctx context.Context, schemaName string) ([]mode
 		WHERE t.owner = :1 AND t.temporary = 'N'
 		ORDER BY t.table_name`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
@@ -193,33 +330,50 @@ func (r *OracleReader) getTables(ctx context.Context, schemaName string) ([]mode
 			table.Comment = comment.String
 		}
 
-		columns, err := r.getColumns(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get columns for table %s: %w", table.Name, err)
-		}
-		table.Columns = columns
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		constraints, err := r.getConstraints(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get constraints for table %s: %w", table.Name, err)
-		}
-		table.Constraints = constraints
+	if len(tables) == 0 {
+		return tables, nil
+	}
 
-		indexes, err := r.getIndexes(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get indexes for table %s: %w", table.Name, err)
-		}
-		table.Indexes = indexes
+	// Fetch each catalog category once for the whole schema and assemble
+	// the tables in memory, rather than issuing a round trip per table
+	columns, err := r.getColumns(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
 
-		tables = append(tables, table)
+	constraints, err := r.getConstraints(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get constraints: %w", err)
+	}
+
+	indexes, err := r.getIndexes(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+
+	for i := range tables {
+		name := tables[i].Name
+		tables[i].Columns = columns[name]
+		tables[i].Constraints = constraints[name]
+		tables[i].Indexes = indexes[name]
 	}
 
 	return tables, nil
 }
 
-func (r *OracleReader) getColumns(ctx context.Context, schemaName, tableName string) ([]models.Column, error) {
+// getColumns returns the columns of every table in the schema, keyed by
+// table name
+func (r *OracleReader) getColumns(ctx context.Context, schemaName string) (map[string][]models.Column, error) {
 	query := `
 		SELECT 
+			c.table_name,
 			c.column_name,
 			c.data_type || 
 			CASE 
@@ -235,22 +389,23 @@ func (r *OracleReader) getColumns(ctx context.Context, schemaName, tableName str
 			cc.comments
 		FROM all_tab_columns c
 		LEFT JOIN all_col_comments cc ON c.owner = cc.owner AND c.table_name = cc.table_name AND c.column_name = cc.column_name
-		WHERE c.owner = :1 AND c.table_name = :2
-		ORDER BY c.column_id`
+		WHERE c.owner = :1
+		ORDER BY c.table_name, c.column_id`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName), strings.ToUpper(tableName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var columns []models.Column
+	columns := make(map[string][]models.Column)
 	for rows.Next() {
+		var tableName string
 		var col models.Column
 		var nullable string
 		var defaultValue, comment sql.NullString
 
-		if err := rows.Scan(&col.Name, &col.DataType, &nullable, &defaultValue, &col.Position, &comment); err != nil {
+		if err := rows.Scan(&tableName, &col.Name, &col.DataType, &nullable, &defaultValue, &col.Position, &comment); err != nil {
 			return nil, err
 		}
 
@@ -263,202 +418,206 @@ func (r *OracleReader) getColumns(ctx context.Context, schemaName, tableName str
 			col.Comment = comment.String
 		}
 
-		columns = append(columns, col)
+		columns[tableName] = append(columns[tableName], col)
 	}
 
-	return columns, nil
+	return columns, rows.Err()
 }
 
-func (r *OracleReader) getConstraints(ctx context.Context, schemaName, tableName string) ([]models.Constraint, error) {
+// getConstraints returns the constraints of every table in the schema,
+// keyed by table name
+func (r *OracleReader) getConstraints(ctx context.Context, schemaName string) (map[string][]models.Constraint, error) {
 	query := `
 		SELECT 
+			c.table_name,
 			c.constraint_name,
 			c.constraint_type,
 			c.search_condition,
-			c.delete_rule
+			c.delete_rule,
+			c.deferrable,
+			c.deferred,
+			c.status,
+			c.validated,
+			cc.column_name
 		FROM all_constraints c
-		WHERE c.owner = :1 AND c.table_name = :2
+		LEFT JOIN all_cons_columns cc ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+		WHERE c.owner = :1
 		AND c.constraint_type IN ('P', 'U', 'R', 'C')
-		ORDER BY c.constraint_name`
+		ORDER BY c.table_name, c.constraint_name, cc.position`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName), strings.ToUpper(tableName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	// Constraint names are unique per owner in Oracle
 	constraintMap := make(map[string]*models.Constraint)
+	var order []string
+	tableOf := make(map[string]string)
 
 	for rows.Next() {
-		var constraintName, constraintType string
+		var tableName, constraintName, constraintType string
 		var searchCondition sql.NullString
 		var deleteRule sql.NullString
+		var deferrable, deferred, status, validated sql.NullString
+		var columnName sql.NullString
 
-		if err := rows.Scan(&constraintName, &constraintType, &searchCondition, &deleteRule); err != nil {
+		if err := rows.Scan(&tableName, &constraintName, &constraintType, &searchCondition, &deleteRule,
+			&deferrable, &deferred, &status, &validated, &columnName); err != nil {
 			return nil, err
 		}
 
-		constraint := &models.Constraint{
-			Name:    constraintName,
-			Columns: []string{},
-		}
-
-		switch constraintType {
-		case "P":
-			constraint.Type = models.PrimaryKey
-		case "U":
-			constraint.Type = models.Unique
-		case "R":
-			constraint.Type = models.ForeignKey
-			if deleteRule.Valid {
-				constraint.OnDelete = strings.ToUpper(deleteRule.String)
+		constraint, exists := constraintMap[constraintName]
+		if !exists {
+			constraint = &models.Constraint{
+				Name:                constraintName,
+				Columns:             []string{},
+				IsDeferrable:        deferrable.String == "DEFERRABLE",
+				IsInitiallyDeferred: deferred.String == "DEFERRED",
+				IsDisabled:          status.String == "DISABLED",
+				IsNotValidated:      validated.String == "NOT VALIDATED",
 			}
-		case "C":
-			constraint.Type = models.Check
-			if searchCondition.Valid {
-				constraint.CheckExpression = searchCondition.String
+
+			switch constraintType {
+			case "P":
+				constraint.Type = models.PrimaryKey
+			case "U":
+				constraint.Type = models.Unique
+			case "R":
+				constraint.Type = models.ForeignKey
+				if deleteRule.Valid {
+					constraint.OnDelete = strings.ToUpper(deleteRule.String)
+				}
+			case "C":
+				constraint.Type = models.Check
+				if searchCondition.Valid {
+					constraint.CheckExpression = searchCondition.String
+				}
 			}
+
+			constraintMap[constraintName] = constraint
+			order = append(order, constraintName)
+			tableOf[constraintName] = tableName
 		}
 
-		constraintMap[constraintName] = constraint
+		if columnName.Valid {
+			constraint.Columns = append(constraint.Columns, columnName.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
+	rows.Close()
 
-	// Get columns for each constraint
-	for name, constraint := range constraintMap {
-		colQuery := `
-			SELECT column_name
-			FROM all_cons_columns
-			WHERE owner = :1 AND constraint_name = :2
-			ORDER BY position`
+	// Get foreign key references
+	refQuery := `
+		SELECT 
+			c.constraint_name,
+			r.owner,
+			r.table_name,
+			rc.column_name
+		FROM all_constraints c
+		JOIN all_constraints r ON c.r_constraint_name = r.constraint_name AND c.r_owner = r.owner
+		JOIN all_cons_columns rc ON r.constraint_name = rc.constraint_name AND r.owner = rc.owner
+		WHERE c.owner = :1 AND c.constraint_type = 'R'
+		ORDER BY c.constraint_name, rc.position`
 
-		colRows, err := r.db.QueryContext(ctx, colQuery, strings.ToUpper(schemaName), name)
-		if err != nil {
+	refRows, err := r.query(ctx, refQuery, strings.ToUpper(schemaName))
+	if err != nil {
+		return nil, err
+	}
+	defer refRows.Close()
+
+	for refRows.Next() {
+		var constraintName, refOwner, refTable, refColumn string
+		if err := refRows.Scan(&constraintName, &refOwner, &refTable, &refColumn); err != nil {
 			return nil, err
 		}
 
-		for colRows.Next() {
-			var columnName string
-			if err := colRows.Scan(&columnName); err != nil {
-				colRows.Close()
-				return nil, err
-			}
-			constraint.Columns = append(constraint.Columns, columnName)
+		constraint, exists := constraintMap[constraintName]
+		if !exists {
+			continue
 		}
-		colRows.Close()
-	}
-
-	// Get foreign key references
-	for name, constraint := range constraintMap {
-		if constraint.Type == models.ForeignKey {
-			refQuery := `
-				SELECT 
-					r.table_name,
-					rc.column_name
-				FROM all_constraints c
-				JOIN all_constraints r ON c.r_constraint_name = r.constraint_name AND c.r_owner = r.owner
-				JOIN all_cons_columns rc ON r.constraint_name = rc.constraint_name AND r.owner = rc.owner
-				WHERE c.owner = :1 AND c.constraint_name = :2
-				ORDER BY rc.position`
-
-			refRows, err := r.db.QueryContext(ctx, refQuery, strings.ToUpper(schemaName), name)
-			if err != nil {
-				return nil, err
-			}
-
-			var refTable string
-			for refRows.Next() {
-				var refColumn string
-				if err := refRows.Scan(&refTable, &refColumn); err != nil {
-					refRows.Close()
-					return nil, err
-				}
-				if constraint.ReferencedTable == "" {
-					constraint.ReferencedTable = refTable
-				}
-				constraint.ReferencedColumn = append(constraint.ReferencedColumn, refColumn)
+		if constraint.ReferencedTable == "" {
+			constraint.ReferencedTable = refTable
+			if !strings.EqualFold(refOwner, schemaName) {
+				constraint.ReferencedSchema = refOwner
 			}
-			refRows.Close()
 		}
+		constraint.ReferencedColumn = append(constraint.ReferencedColumn, refColumn)
+	}
+	if err := refRows.Err(); err != nil {
+		return nil, err
 	}
 
-	var constraints []models.Constraint
-	for _, constraint := range constraintMap {
-		constraints = append(constraints, *constraint)
+	constraints := make(map[string][]models.Constraint)
+	for _, name := range order {
+		tableName := tableOf[name]
+		constraints[tableName] = append(constraints[tableName], *constraintMap[name])
 	}
 
 	return constraints, nil
 }
 
-func (r *OracleReader) getIndexes(ctx context.Context, schemaName, tableName string) ([]models.Index, error) {
+// getIndexes returns the non-primary indexes of every table in the schema,
+// keyed by table name
+func (r *OracleReader) getIndexes(ctx context.Context, schemaName string) (map[string][]models.Index, error) {
 	query := `
-		SELECT DISTINCT
+		SELECT 
+			i.table_name,
 			i.index_name,
 			i.uniqueness,
-			i.index_type
+			i.index_type,
+			ic.column_name
 		FROM all_indexes i
-		WHERE i.owner = :1 AND i.table_name = :2
+		LEFT JOIN all_ind_columns ic ON ic.index_owner = i.owner AND ic.index_name = i.index_name
+		WHERE i.owner = :1
 		AND NOT EXISTS (
 			SELECT 1 FROM all_constraints c 
 			WHERE c.owner = i.owner 
 			AND c.constraint_name = i.index_name 
 			AND c.constraint_type = 'P'
 		)
-		ORDER BY i.index_name`
+		ORDER BY i.table_name, i.index_name, ic.column_position`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName), strings.ToUpper(tableName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	indexMap := make(map[string]*models.Index)
+	indexes := make(map[string][]models.Index)
 
 	for rows.Next() {
-		var indexName, uniqueness, indexType string
+		var tableName, indexName, uniqueness, indexType string
+		var columnName sql.NullString
 
-		if err := rows.Scan(&indexName, &uniqueness, &indexType); err != nil {
+		if err := rows.Scan(&tableName, &indexName, &uniqueness, &indexType, &columnName); err != nil {
 			return nil, err
 		}
 
-		indexMap[indexName] = &models.Index{
-			Name:      indexName,
-			TableName: tableName,
-			IsUnique:  uniqueness == "UNIQUE",
-			Type:      indexType,
-			Columns:   []string{},
+		// Rows arrive grouped by table and index, so a new index starts
+		// whenever the name changes
+		tableIndexes := indexes[tableName]
+		if n := len(tableIndexes); n == 0 || tableIndexes[n-1].Name != indexName {
+			tableIndexes = append(tableIndexes, models.Index{
+				Name:      indexName,
+				TableName: tableName,
+				IsUnique:  uniqueness == "UNIQUE",
+				Type:      indexType,
+				Columns:   []string{},
+			})
 		}
-	}
-
-	// Get columns for each index
-	for name, index := range indexMap {
-		colQuery := `
-			SELECT column_name
-			FROM all_ind_columns
-			WHERE index_owner = :1 AND index_name = :2
-			ORDER BY column_position`
 
-		colRows, err := r.db.QueryContext(ctx, colQuery, strings.ToUpper(schemaName), name)
-		if err != nil {
-			return nil, err
+		if columnName.Valid {
+			last := &tableIndexes[len(tableIndexes)-1]
+			last.Columns = append(last.Columns, columnName.String)
 		}
-
-		for colRows.Next() {
-			var columnName string
-			if err := colRows.Scan(&columnName); err != nil {
-				colRows.Close()
-				return nil, err
-			}
-			index.Columns = append(index.Columns, columnName)
-		}
-		colRows.Close()
-	}
-
-	var indexes []models.Index
-	for _, index := range indexMap {
-		indexes = append(indexes, *index)
+		indexes[tableName] = tableIndexes
 	}
 
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
 func (r *OracleReader) getViews(ctx context.Context, schemaName string) ([]models.View, error) {
@@ -468,7 +627,7 @@ func (r *OracleReader) getViews(ctx context.Context, schemaName string) ([]model
 		WHERE owner = :1
 		ORDER BY view_name`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
@@ -481,57 +640,71 @@ func (r *OracleReader) getViews(ctx context.Context, schemaName string) ([]model
 			return nil, err
 		}
 		view.Schema = schemaName
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		columns, err := r.getViewColumns(ctx, schemaName, view.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get columns for view %s: %w", view.Name, err)
-		}
-		view.Columns = columns
+	if len(views) == 0 {
+		return views, nil
+	}
 
-		views = append(views, view)
+	columns, err := r.getViewColumns(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view columns: %w", err)
+	}
+	for i := range views {
+		views[i].Columns = columns[views[i].Name]
 	}
 
 	return views, nil
 }
 
-func (r *OracleReader) getViewColumns(ctx context.Context, schemaName, viewName string) ([]models.Column, error) {
+// getViewColumns returns the columns of every view in the schema, keyed by
+// view name
+func (r *OracleReader) getViewColumns(ctx context.Context, schemaName string) (map[string][]models.Column, error) {
 	query := `
 		SELECT 
-			column_name,
-			data_type || 
+			c.table_name,
+			c.column_name,
+			c.data_type || 
 			CASE 
-				WHEN data_type IN ('VARCHAR2', 'CHAR', 'NVARCHAR2', 'NCHAR') THEN '(' || data_length || ')'
-				WHEN data_type = 'NUMBER' AND data_precision IS NOT NULL THEN 
-					'(' || data_precision || 
-					CASE WHEN data_scale > 0 THEN ',' || data_scale ELSE '' END || ')'
+				WHEN c.data_type IN ('VARCHAR2', 'CHAR', 'NVARCHAR2', 'NCHAR') THEN '(' || c.data_length || ')'
+				WHEN c.data_type = 'NUMBER' AND c.data_precision IS NOT NULL THEN 
+					'(' || c.data_precision || 
+					CASE WHEN c.data_scale > 0 THEN ',' || c.data_scale ELSE '' END || ')'
 				ELSE ''
 			END AS data_type,
-			nullable,
-			column_id
-		FROM all_tab_columns
-		WHERE owner = :1 AND table_name = :2
-		ORDER BY column_id`
+			c.nullable,
+			c.column_id
+		FROM all_tab_columns c
+		JOIN all_views v ON v.owner = c.owner AND v.view_name = c.table_name
+		WHERE c.owner = :1
+		ORDER BY c.table_name, c.column_id`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName), strings.ToUpper(viewName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var columns []models.Column
+	columns := make(map[string][]models.Column)
 	for rows.Next() {
+		var viewName string
 		var col models.Column
 		var nullable string
 
-		if err := rows.Scan(&col.Name, &col.DataType, &nullable, &col.Position); err != nil {
+		if err := rows.Scan(&viewName, &col.Name, &col.DataType, &nullable, &col.Position); err != nil {
 			return nil, err
 		}
 
 		col.IsNullable = nullable == "Y"
-		columns = append(columns, col)
+		columns[viewName] = append(columns[viewName], col)
 	}
 
-	return columns, nil
+	return columns, rows.Err()
 }
 
 func (r *OracleReader) getSequences(ctx context.Context, schemaName string) ([]models.Sequence, error) {
@@ -547,7 +720,7 @@ func (r *OracleReader) getSequences(ctx context.Context, schemaName string) ([]m
 		WHERE sequence_owner = :1
 		ORDER BY sequence_name`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
@@ -600,7 +773,7 @@ func (r *OracleReader) getFunctions(ctx context.Context, schemaName string) ([]m
 		WHERE owner = :1 AND object_type = 'FUNCTION'
 		ORDER BY object_name`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
@@ -632,7 +805,7 @@ func (r *OracleReader) getProcedures(ctx context.Context, schemaName string) ([]
 		WHERE owner = :1 AND object_type = 'PROCEDURE'
 		ORDER BY object_name`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
@@ -667,7 +840,7 @@ func (r *OracleReader) getTriggers(ctx context.Context, schemaName string) ([]mo
 		WHERE owner = :1
 		ORDER BY trigger_name`
 
-	rows, err := r.db.QueryContext(ctx, query, strings.ToUpper(schemaName))
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
 	if err != nil {
 		return nil, err
 	}
@@ -704,8 +877,49 @@ func (r *OracleReader) getTriggers(ctx context.Context, schemaName string) ([]mo
 	return triggers, nil
 }
 
+func (r *OracleReader) getSynonyms(ctx context.Context, schemaName string) ([]models.Synonym, error) {
+	query := `
+		SELECT 
+			synonym_name,
+			table_owner,
+			table_name,
+			db_link
+		FROM all_synonyms
+		WHERE owner = :1
+		ORDER BY synonym_name`
+
+	rows, err := r.query(ctx, query, strings.ToUpper(schemaName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var synonyms []models.Synonym
+	for rows.Next() {
+		var synonym models.Synonym
+		var targetOwner, dbLink sql.NullString
+
+		if err := rows.Scan(&synonym.Name, &targetOwner, &synonym.TargetObject, &dbLink); err != nil {
+			return nil, err
+		}
+
+		synonym.Schema = schemaName
+		if targetOwner.Valid {
+			synonym.TargetOwner = targetOwner.String
+		}
+		if dbLink.Valid {
+			synonym.DBLink = dbLink.String
+		}
+
+		synonyms = append(synonyms, synonym)
+	}
+
+	return synonyms, nil
+}
+
 func (r *OracleReader) Close() error {
 	if r.db != nil {
+		r.EndSnapshot()
 		return r.db.Close()
 	}
 	return nil
@@ -720,7 +934,7 @@ func (r *OracleReader) GetTableRowCount(ctx context.Context, schemaName, tableNa
 		strings.ToUpper(schemaName),
 		strings.ToUpper(tableName))
 
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.q.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get row count: %w", err)
 	}
@@ -744,7 +958,7 @@ func (r *OracleReader) GetColumnSamples(ctx context.Context, schemaName, tableNa
 		strings.ToUpper(columnName),
 		limit)
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get column samples: %w", err)
 	}
@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/nechja/schemalyzer/internal/database"
+	"github.com/nechja/schemalyzer/internal/database/fakesql"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeSchema serves a schema with the given number of tables, each with
+// a primary key and a foreign key to the first table
+func newFakeSchema(tables int) *fakesql.Driver {
+	fake := fakesql.New()
+
+	var tableRows, columnRows, constraintRows, refRows [][]driver.Value
+	for i := 0; i < tables; i++ {
+		name := fmt.Sprintf("TABLE_%04d", i)
+		tableRows = append(tableRows, []driver.Value{name, nil})
+		columnRows = append(columnRows, []driver.Value{name, "ID", "NUMBER(10)", "N", nil, int64(1), nil})
+		constraintRows = append(constraintRows,
+			[]driver.Value{name, name + "_FK", "R", nil, "CASCADE", "NOT DEFERRABLE", "IMMEDIATE", "ENABLED", "VALIDATED", "ID"},
+			[]driver.Value{name, name + "_PK", "P", nil, nil, "NOT DEFERRABLE", "IMMEDIATE", "ENABLED", "VALIDATED", "ID"})
+		refRows = append(refRows, []driver.Value{name + "_FK", "APP", "TABLE_0000", "ID"})
+	}
+
+	fake.Handle("FROM all_tables t", fakesql.Result{
+		Columns: []string{"table_name", "comments"},
+		Rows:    tableRows,
+	})
+	fake.Handle("FROM all_tab_columns c LEFT JOIN all_col_comments", fakesql.Result{
+		Columns: []string{"table_name", "column_name", "data_type", "nullable", "data_default", "column_id", "comments"},
+		Rows:    columnRows,
+	})
+	fake.Handle("LEFT JOIN all_cons_columns cc", fakesql.Result{
+		Columns: []string{"table_name", "constraint_name", "constraint_type", "search_condition", "delete_rule", "deferrable", "deferred", "status", "validated", "column_name"},
+		Rows:    constraintRows,
+	})
+	fake.Handle("JOIN all_constraints r", fakesql.Result{
+		Columns: []string{"constraint_name", "owner", "table_name", "column_name"},
+		Rows:    refRows,
+	})
+	return fake
+}
+
+func TestGetSchemaAssemblesBatchedCatalog(t *testing.T) {
+	fake := newFakeSchema(3)
+	reader := newTestReader(fake)
+
+	schema, err := reader.GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+	assert.Len(t, schema.Tables, 3)
+
+	for _, table := range schema.Tables {
+		assert.Len(t, table.Columns, 1)
+		if assert.Len(t, table.Constraints, 2) {
+			fk := table.Constraints[0]
+			assert.Equal(t, table.Name+"_FK", fk.Name)
+			assert.Equal(t, "TABLE_0000", fk.ReferencedTable)
+			assert.Empty(t, fk.ReferencedSchema)
+			assert.Equal(t, []string{"ID"}, fk.ReferencedColumn)
+			assert.Equal(t, []string{"ID"}, table.Constraints[1].Columns)
+		}
+	}
+}
+
+func TestGetSchemaRoundTripsIndependentOfTableCount(t *testing.T) {
+	small := newFakeSchema(2)
+	_, err := newTestReader(small).GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+
+	large := newFakeSchema(200)
+	_, err = newTestReader(large).GetSchema(context.Background(), "app")
+	assert.NoError(t, err)
+
+	assert.Equal(t, small.RoundTrips(), large.RoundTrips())
+}
+
+func BenchmarkGetSchema(b *testing.B) {
+	for _, tables := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("tables=%d", tables), func(b *testing.B) {
+			fake := newFakeSchema(tables)
+			reader := newTestReader(fake)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := reader.GetSchema(context.Background(), "app"); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(fake.RoundTrips())/float64(b.N), "roundtrips/op")
+		})
+	}
+}
+
+func newTestReader(fake *fakesql.Driver) *OracleReader {
+	db := fake.DB()
+	return &OracleReader{db: db, options: database.DefaultReaderOptions(), q: db}
+}
+
+func TestSnapshotStartsReadOnlyTransaction(t *testing.T) {
+	fake := newFakeSchema(3)
+	reader := newTestReader(fake)
+
+	assert.NoError(t, reader.BeginSnapshot(context.Background()))
+	schema, err := reader.GetSchema(context.Background(), "app")
+	assert.NoError(t, reader.EndSnapshot())
+	assert.NoError(t, err)
+	assert.Len(t, schema.Tables, 3)
+
+	assert.Equal(t, "SET TRANSACTION READ ONLY", fake.Statements()[0])
+}
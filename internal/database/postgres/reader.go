@@ -3,40 +3,127 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"github.com/lib/pq"
+	"github.com/nechja/schemalyzer/internal/database"
 	"github.com/nechja/schemalyzer/pkg/models"
 	"strings"
 	"sync"
 )
 
 type PostgresReader struct {
-	db *sql.DB
+	db      *sql.DB
+	options database.ReaderOptions
+	// q runs catalog queries: the pool itself, or a snapshot transaction
+	q        database.Queryer
+	snapshot *pgSnapshot
 }
 
 func NewPostgresReader() *PostgresReader {
-	return &PostgresReader{}
+	return NewPostgresReaderWithOptions(database.DefaultReaderOptions())
+}
+
+// NewPostgresReaderWithOptions creates a reader with custom pool and timeout settings
+func NewPostgresReaderWithOptions(options database.ReaderOptions) *PostgresReader {
+	return &PostgresReader{options: options}
 }
 
 func (r *PostgresReader) Connect(ctx context.Context, connectionString string) error {
-	db, err := sql.Open("postgres", connectionString)
+	connector, err := pq.NewConnector(connectionString)
 	if err != nil {
 		return fmt.Errorf("failed to connect to postgres: %w", err)
 	}
 
-	// Configure connection pool for large databases
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(0) // No timeout
+	return r.open(ctx, connector)
+}
+
+func (r *PostgresReader) open(ctx context.Context, connector driver.Connector) error {
+	// statement_timeout is set on every pooled session so the server itself
+	// cancels runaway catalog queries
+	var session []string
+	if r.options.StatementTimeout > 0 {
+		session = append(session, fmt.Sprintf("SET statement_timeout = %d", r.options.StatementTimeout.Milliseconds()))
+	}
+
+	db := sql.OpenDB(database.SessionConnector(connector, session...))
+	database.ConfigurePool(db, r.options)
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return fmt.Errorf("failed to ping postgres: %w", err)
 	}
 
 	r.db = db
+	r.q = db
+	return nil
+}
+
+// pgSnapshot is a transaction whose snapshot has been exported so that
+// parallel fetchers on other connections can import it
+type pgSnapshot struct {
+	leader *sql.Tx
+	id     string
+}
+
+// BeginSnapshot pins every subsequent read to one point in time. A
+// read-only repeatable-read transaction exports its snapshot and each
+// parallel fetcher imports it on its own connection, so reads stay
+// concurrent yet see the same catalog.
+func (r *PostgresReader) BeginSnapshot(ctx context.Context) error {
+	if r.snapshot != nil {
+		return fmt.Errorf("snapshot already in progress")
+	}
+	if r.options.MaxOpenConns == 1 {
+		return fmt.Errorf("a consistent postgres snapshot needs at least 2 connections")
+	}
+
+	leader, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+
+	var id string
+	if err := leader.QueryRowContext(ctx, "SELECT pg_export_snapshot()").Scan(&id); err != nil {
+		leader.Rollback()
+		return fmt.Errorf("failed to export snapshot: %w", err)
+	}
+
+	r.snapshot = &pgSnapshot{leader: leader, id: id}
 	return nil
 }
 
+// EndSnapshot releases the exported snapshot
+func (r *PostgresReader) EndSnapshot() error {
+	if r.snapshot == nil {
+		return nil
+	}
+	err := r.snapshot.leader.Rollback()
+	r.snapshot = nil
+	return err
+}
+
+// fetcher returns the reader one GetSchema fetcher should query through.
+// Inside a snapshot that is a new transaction importing the exported
+// snapshot, rolled back by the returned release function.
+func (r *PostgresReader) fetcher(ctx context.Context) (*PostgresReader, func(), error) {
+	if r.snapshot == nil {
+		return r, func() {}, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "SET TRANSACTION SNAPSHOT "+pq.QuoteLiteral(r.snapshot.id)); err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("failed to import snapshot: %w", err)
+	}
+
+	reader := &PostgresReader{db: r.db, options: r.options, q: tx}
+	return reader, func() { tx.Rollback() }, nil
+}
+
 func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
 	schema := &models.Schema{
 		Name:         schemaName,
@@ -46,11 +133,13 @@ func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*mod
 	// Use parallel fetching for better performance on large databases
 	type result struct {
 		tables     []models.Table
+		columns    map[string][]models.Column
 		views      []models.View
 		sequences  []models.Sequence
 		functions  []models.Function
 		procedures []models.Procedure
 		triggers   []models.Trigger
+		extensions []models.Extension
 		err        error
 	}
 
@@ -62,17 +151,36 @@ func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*mod
 
 	go func() {
 		defer wg.Done()
-		tables, err := r.getTables(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		// Columns of tables and views come from one query, shared below
+		columns, err := reader.getColumns(ctx, schemaName)
+		if err != nil {
+			res.err = fmt.Errorf("failed to get columns: %w", err)
+			return
+		}
+		tables, err := reader.getTables(ctx, schemaName, columns)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get tables: %w", err)
 			return
 		}
 		res.tables = tables
+		res.columns = columns
 	}()
 
 	go func() {
 		defer wg.Done()
-		views, err := r.getViews(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		views, err := reader.getViews(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get views: %w", err)
 			return
@@ -82,7 +190,13 @@ func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*mod
 
 	go func() {
 		defer wg.Done()
-		sequences, err := r.getSequences(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		sequences, err := reader.getSequences(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get sequences: %w", err)
 			return
@@ -92,17 +206,25 @@ func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*mod
 
 	go func() {
 		defer wg.Done()
-		functions, err := r.getFunctions(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		// Functions and procedures share one fetch of their parameters
+		parameters, err := reader.getRoutineParameters(ctx, schemaName)
+		if err != nil {
+			res.err = fmt.Errorf("failed to get routine parameters: %w", err)
+			return
+		}
+		functions, err := reader.getFunctions(ctx, schemaName, parameters)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get functions: %w", err)
 			return
 		}
 		res.functions = functions
-	}()
-
-	go func() {
-		defer wg.Done()
-		procedures, err := r.getProcedures(ctx, schemaName)
+		procedures, err := reader.getProcedures(ctx, schemaName, parameters)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get procedures: %w", err)
 			return
@@ -112,7 +234,13 @@ func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*mod
 
 	go func() {
 		defer wg.Done()
-		triggers, err := r.getTriggers(ctx, schemaName)
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		triggers, err := reader.getTriggers(ctx, schemaName)
 		if err != nil {
 			res.err = fmt.Errorf("failed to get triggers: %w", err)
 			return
@@ -120,18 +248,39 @@ func (r *PostgresReader) GetSchema(ctx context.Context, schemaName string) (*mod
 		res.triggers = triggers
 	}()
 
+	go func() {
+		defer wg.Done()
+		reader, release, err := r.fetcher(ctx)
+		if err != nil {
+			res.err = err
+			return
+		}
+		defer release()
+		extensions, err := reader.getExtensions(ctx, schemaName)
+		if err != nil {
+			res.err = fmt.Errorf("failed to get extensions: %w", err)
+			return
+		}
+		res.extensions = extensions
+	}()
+
 	wg.Wait()
 
 	if res.err != nil {
 		return nil, res.err
 	}
 
+	for i := range res.views {
+		res.views[i].Columns = res.columns[res.views[i].Name]
+	}
+
 	schema.Tables = res.tables
 	schema.Views = res.views
 	schema.Sequences = res.sequences
 	schema.Functions = res.functions
 	schema.Procedures = res.procedures
 	schema.Triggers = res.triggers
+	schema.Extensions = res.extensions
 
 	return schema, nil
 }
@@ -143,7 +292,7 @@ func (r *PostgresReader) ListSchemas(ctx context.Context) ([]string, error) {
 		WHERE schema_name NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
 		ORDER BY schema_name`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list schemas: %w", err)
 	}
@@ -161,16 +310,18 @@ func (r *PostgresReader) ListSchemas(ctx context.Context) ([]string, error) {
 	return schemas, nil
 }
 
-func (r *PostgresReader) getTables(ctx context.Context, schemaName string) ([]models.Table, error) {
+// getTables reads the schema's tables, attaching columns from the map
+// returned by getColumns
+func (r *PostgresReader) getTables(ctx context.Context, schemaName string, columns map[string][]models.Column) ([]models.Table, error) {
 	query := `
-		SELECT table_name, obj_description(pgc.oid)
+		SELECT table_name, obj_description(pgc.oid), pgc.relrowsecurity, pgc.relforcerowsecurity
 		FROM information_schema.tables t
 		JOIN pg_class pgc ON pgc.relname = t.table_name
 		JOIN pg_namespace pgn ON pgn.oid = pgc.relnamespace AND pgn.nspname = t.table_schema
 		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
 		ORDER BY table_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +332,7 @@ func (r *PostgresReader) getTables(ctx context.Context, schemaName string) ([]mo
 		var table models.Table
 		var comment sql.NullString
 
-		if err := rows.Scan(&table.Name, &comment); err != nil {
+		if err := rows.Scan(&table.Name, &comment, &table.RowSecurityEnabled, &table.RowSecurityForced); err != nil {
 			return nil, err
 		}
 
@@ -190,33 +341,51 @@ func (r *PostgresReader) getTables(ctx context.Context, schemaName string) ([]mo
 			table.Comment = comment.String
 		}
 
-		columns, err := r.getColumns(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get columns for table %s: %w", table.Name, err)
-		}
-		table.Columns = columns
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		constraints, err := r.getConstraints(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get constraints for table %s: %w", table.Name, err)
-		}
-		table.Constraints = constraints
+	if len(tables) == 0 {
+		return tables, nil
+	}
 
-		indexes, err := r.getIndexes(ctx, schemaName, table.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get indexes for table %s: %w", table.Name, err)
-		}
-		table.Indexes = indexes
+	// Fetch each catalog category once for the whole schema and assemble
+	// the tables in memory, rather than issuing a round trip per table
+	constraints, err := r.getConstraints(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get constraints: %w", err)
+	}
 
-		tables = append(tables, table)
+	indexes, err := r.getIndexes(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+
+	policies, err := r.getPolicies(ctx, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+
+	for i := range tables {
+		name := tables[i].Name
+		tables[i].Columns = columns[name]
+		tables[i].Constraints = constraints[name]
+		tables[i].Indexes = indexes[name]
+		tables[i].Policies = policies[name]
 	}
 
 	return tables, nil
 }
 
-func (r *PostgresReader) getColumns(ctx context.Context, schemaName, tableName string) ([]models.Column, error) {
+// getColumns returns the columns of every table and view in the schema,
+// keyed by relation name
+func (r *PostgresReader) getColumns(ctx context.Context, schemaName string) (map[string][]models.Column, error) {
 	query := `
 		SELECT 
+			c.table_name,
 			c.column_name,
 			c.data_type,
 			c.is_nullable,
@@ -226,22 +395,23 @@ func (r *PostgresReader) getColumns(ctx context.Context, schemaName, tableName s
 		FROM information_schema.columns c
 		JOIN pg_class pgc ON pgc.relname = c.table_name
 		JOIN pg_namespace pgn ON pgn.oid = pgc.relnamespace AND pgn.nspname = c.table_schema
-		WHERE c.table_schema = $1 AND c.table_name = $2
-		ORDER BY c.ordinal_position`
+		WHERE c.table_schema = $1
+		ORDER BY c.table_name, c.ordinal_position`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName, tableName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var columns []models.Column
+	columns := make(map[string][]models.Column)
 	for rows.Next() {
+		var tableName string
 		var col models.Column
 		var isNullable string
 		var defaultValue, comment sql.NullString
 
-		if err := rows.Scan(&col.Name, &col.DataType, &isNullable, &defaultValue, &col.Position, &comment); err != nil {
+		if err := rows.Scan(&tableName, &col.Name, &col.DataType, &isNullable, &defaultValue, &col.Position, &comment); err != nil {
 			return nil, err
 		}
 
@@ -253,60 +423,85 @@ func (r *PostgresReader) getColumns(ctx context.Context, schemaName, tableName s
 			col.Comment = comment.String
 		}
 
-		columns = append(columns, col)
+		columns[tableName] = append(columns[tableName], col)
 	}
 
-	return columns, nil
+	return columns, rows.Err()
 }
 
-func (r *PostgresReader) getConstraints(ctx context.Context, schemaName, tableName string) ([]models.Constraint, error) {
+// getConstraints returns the constraints of every table in the schema,
+// keyed by table name
+func (r *PostgresReader) getConstraints(ctx context.Context, schemaName string) (map[string][]models.Constraint, error) {
 	query := `
 		SELECT 
+			tc.table_name,
 			tc.constraint_name,
 			tc.constraint_type,
 			kcu.column_name,
+			ccu.table_schema AS foreign_table_schema,
 			ccu.table_name AS foreign_table_name,
 			ccu.column_name AS foreign_column_name,
 			cc.check_clause,
 			rc.update_rule,
-			rc.delete_rule
+			rc.delete_rule,
+			tc.is_deferrable,
+			tc.initially_deferred,
+			COALESCE(pgc.convalidated, true) AS is_validated
 		FROM information_schema.table_constraints tc
+		JOIN pg_namespace pgn ON pgn.nspname = tc.table_schema
+		JOIN pg_class pgcl ON pgcl.relname = tc.table_name AND pgcl.relnamespace = pgn.oid
+		LEFT JOIN pg_constraint pgc
+			ON pgc.conname = tc.constraint_name
+			AND pgc.conrelid = pgcl.oid
 		LEFT JOIN information_schema.key_column_usage kcu 
 			ON tc.constraint_name = kcu.constraint_name
 			AND tc.table_schema = kcu.table_schema
+			AND tc.table_name = kcu.table_name
 		LEFT JOIN information_schema.constraint_column_usage ccu
 			ON ccu.constraint_name = tc.constraint_name
-			AND ccu.table_schema = tc.table_schema
+			AND ccu.constraint_schema = tc.constraint_schema
 		LEFT JOIN information_schema.check_constraints cc
 			ON cc.constraint_name = tc.constraint_name
 			AND cc.constraint_schema = tc.table_schema
 		LEFT JOIN information_schema.referential_constraints rc
 			ON rc.constraint_name = tc.constraint_name
 			AND rc.constraint_schema = tc.table_schema
-		WHERE tc.table_schema = $1 AND tc.table_name = $2
-		ORDER BY tc.constraint_name, kcu.ordinal_position`
+		WHERE tc.table_schema = $1
+		ORDER BY tc.table_name, tc.constraint_name, kcu.ordinal_position`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName, tableName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	constraintMap := make(map[string]*models.Constraint)
+	type constraintKey struct {
+		table, name string
+	}
+	constraintMap := make(map[constraintKey]*models.Constraint)
+	var order []constraintKey
 
 	for rows.Next() {
-		var constraintName, constraintType string
-		var columnName, foreignTable, foreignColumn, checkClause sql.NullString
+		var tableName, constraintName, constraintType string
+		var columnName, foreignSchema, foreignTable, foreignColumn, checkClause sql.NullString
 		var updateRule, deleteRule sql.NullString
+		var isDeferrable, initiallyDeferred string
+		var isValidated bool
 
-		if err := rows.Scan(&constraintName, &constraintType, &columnName, &foreignTable, &foreignColumn, &checkClause, &updateRule, &deleteRule); err != nil {
+		if err := rows.Scan(&tableName, &constraintName, &constraintType, &columnName, &foreignSchema, &foreignTable, &foreignColumn, &checkClause, &updateRule, &deleteRule,
+			&isDeferrable, &initiallyDeferred, &isValidated); err != nil {
 			return nil, err
 		}
 
-		if _, exists := constraintMap[constraintName]; !exists {
+		key := constraintKey{table: tableName, name: constraintName}
+		if _, exists := constraintMap[key]; !exists {
+			// PostgreSQL constraints cannot be disabled, only left NOT VALID
 			constraint := &models.Constraint{
-				Name:    constraintName,
-				Columns: []string{},
+				Name:                constraintName,
+				Columns:             []string{},
+				IsDeferrable:        isDeferrable == "YES",
+				IsInitiallyDeferred: initiallyDeferred == "YES",
+				IsNotValidated:      !isValidated,
 			}
 
 			switch constraintType {
@@ -317,6 +512,9 @@ func (r *PostgresReader) getConstraints(ctx context.Context, schemaName, tableNa
 				if foreignTable.Valid {
 					constraint.ReferencedTable = foreignTable.String
 				}
+				if foreignSchema.Valid && foreignSchema.String != schemaName {
+					constraint.ReferencedSchema = foreignSchema.String
+				}
 				if updateRule.Valid {
 					constraint.OnUpdate = strings.ToUpper(updateRule.String)
 				}
@@ -332,29 +530,135 @@ func (r *PostgresReader) getConstraints(ctx context.Context, schemaName, tableNa
 				}
 			}
 
-			constraintMap[constraintName] = constraint
+			constraintMap[key] = constraint
+			order = append(order, key)
 		}
 
 		if columnName.Valid {
-			constraintMap[constraintName].Columns = append(constraintMap[constraintName].Columns, columnName.String)
+			constraintMap[key].Columns = append(constraintMap[key].Columns, columnName.String)
 		}
 
-		if constraintMap[constraintName].Type == models.ForeignKey && foreignColumn.Valid {
-			constraintMap[constraintName].ReferencedColumn = append(constraintMap[constraintName].ReferencedColumn, foreignColumn.String)
+		if constraintMap[key].Type == models.ForeignKey && foreignColumn.Valid {
+			constraintMap[key].ReferencedColumn = append(constraintMap[key].ReferencedColumn, foreignColumn.String)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	var constraints []models.Constraint
-	for _, constraint := range constraintMap {
-		constraints = append(constraints, *constraint)
+	constraints := make(map[string][]models.Constraint)
+	for _, key := range order {
+		constraints[key.table] = append(constraints[key.table], *constraintMap[key])
+	}
+
+	// Exclusion constraints are not exposed through information_schema
+	exclusions, err := r.getExclusionConstraints(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	for tableName, tableExclusions := range exclusions {
+		constraints[tableName] = append(constraints[tableName], tableExclusions...)
 	}
 
 	return constraints, nil
 }
 
-func (r *PostgresReader) getIndexes(ctx context.Context, schemaName, tableName string) ([]models.Index, error) {
+func (r *PostgresReader) getExclusionConstraints(ctx context.Context, schemaName string) (map[string][]models.Constraint, error) {
+	query := `
+		SELECT 
+			t.relname,
+			con.conname,
+			pg_get_constraintdef(con.oid) AS definition,
+			array_agg(a.attname ORDER BY array_position(con.conkey, a.attnum)) FILTER (WHERE a.attname IS NOT NULL) AS column_names,
+			con.condeferrable,
+			con.condeferred
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(con.conkey)
+		WHERE n.nspname = $1 AND con.contype = 'x'
+		GROUP BY t.relname, con.oid, con.conname, con.condeferrable, con.condeferred
+		ORDER BY t.relname, con.conname`
+
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	constraints := make(map[string][]models.Constraint)
+	for rows.Next() {
+		var tableName string
+		var constraint models.Constraint
+		var definition string
+		var columnNames []string
+
+		if err := rows.Scan(&tableName, &constraint.Name, &definition, pq.Array(&columnNames),
+			&constraint.IsDeferrable, &constraint.IsInitiallyDeferred); err != nil {
+			return nil, err
+		}
+
+		constraint.Type = models.Exclude
+		constraint.Columns = columnNames
+		// pg_get_constraintdef returns "EXCLUDE USING gist (...)"; keep the operator part
+		constraint.ExclusionDefinition = strings.TrimSpace(strings.TrimPrefix(definition, "EXCLUDE"))
+		constraints[tableName] = append(constraints[tableName], constraint)
+	}
+
+	return constraints, rows.Err()
+}
+
+func (r *PostgresReader) getPolicies(ctx context.Context, schemaName string) (map[string][]models.Policy, error) {
 	query := `
 		SELECT 
+			tablename,
+			policyname,
+			permissive,
+			roles,
+			cmd,
+			qual,
+			with_check
+		FROM pg_policies
+		WHERE schemaname = $1
+		ORDER BY tablename, policyname`
+
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make(map[string][]models.Policy)
+	for rows.Next() {
+		var tableName string
+		var policy models.Policy
+		var permissive string
+		var using, withCheck sql.NullString
+
+		if err := rows.Scan(&tableName, &policy.Name, &permissive, pq.Array(&policy.Roles), &policy.Command, &using, &withCheck); err != nil {
+			return nil, err
+		}
+
+		policy.IsRestrictive = permissive == "RESTRICTIVE"
+		if using.Valid {
+			policy.Using = using.String
+		}
+		if withCheck.Valid {
+			policy.WithCheck = withCheck.String
+		}
+
+		policies[tableName] = append(policies[tableName], policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// getIndexes returns the non-primary indexes of every table in the schema,
+// keyed by table name
+func (r *PostgresReader) getIndexes(ctx context.Context, schemaName string) (map[string][]models.Index, error) {
+	query := `
+		SELECT 
+			t.relname AS table_name,
 			i.relname AS index_name,
 			idx.indisunique,
 			am.amname AS index_type,
@@ -365,32 +669,34 @@ func (r *PostgresReader) getIndexes(ctx context.Context, schemaName, tableName s
 		JOIN pg_namespace n ON n.oid = t.relnamespace
 		JOIN pg_am am ON am.oid = i.relam
 		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(idx.indkey)
-		WHERE n.nspname = $1 AND t.relname = $2 AND NOT idx.indisprimary
-		GROUP BY i.relname, idx.indisunique, am.amname`
+		WHERE n.nspname = $1 AND NOT idx.indisprimary
+		GROUP BY t.relname, i.relname, idx.indisunique, am.amname
+		ORDER BY t.relname, i.relname`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName, tableName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var indexes []models.Index
+	indexes := make(map[string][]models.Index)
 	for rows.Next() {
 		var index models.Index
 		var columnNames []string
 
-		if err := rows.Scan(&index.Name, &index.IsUnique, &index.Type, pq.Array(&columnNames)); err != nil {
+		if err := rows.Scan(&index.TableName, &index.Name, &index.IsUnique, &index.Type, pq.Array(&columnNames)); err != nil {
 			return nil, err
 		}
 
-		index.TableName = tableName
 		index.Columns = columnNames
-		indexes = append(indexes, index)
+		indexes[index.TableName] = append(indexes[index.TableName], index)
 	}
 
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
+// getViews reads the schema's views. GetSchema attaches their columns from
+// the query it shares with the tables.
 func (r *PostgresReader) getViews(ctx context.Context, schemaName string) ([]models.View, error) {
 	query := `
 		SELECT table_name, view_definition
@@ -398,7 +704,7 @@ func (r *PostgresReader) getViews(ctx context.Context, schemaName string) ([]mod
 		WHERE table_schema = $1
 		ORDER BY table_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -411,17 +717,10 @@ func (r *PostgresReader) getViews(ctx context.Context, schemaName string) ([]mod
 			return nil, err
 		}
 		view.Schema = schemaName
-
-		columns, err := r.getColumns(ctx, schemaName, view.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get columns for view %s: %w", view.Name, err)
-		}
-		view.Columns = columns
-
 		views = append(views, view)
 	}
 
-	return views, nil
+	return views, rows.Err()
 }
 
 func (r *PostgresReader) getSequences(ctx context.Context, schemaName string) ([]models.Sequence, error) {
@@ -437,7 +736,7 @@ func (r *PostgresReader) getSequences(ctx context.Context, schemaName string) ([
 		WHERE sequence_schema = $1
 		ORDER BY sequence_name`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -461,9 +760,12 @@ func (r *PostgresReader) getSequences(ctx context.Context, schemaName string) ([
 	return sequences, nil
 }
 
-func (r *PostgresReader) getFunctions(ctx context.Context, schemaName string) ([]models.Function, error) {
+// getFunctions reads the schema's functions, attaching their arguments from
+// parameters as returned by getRoutineParameters
+func (r *PostgresReader) getFunctions(ctx context.Context, schemaName string, parameters map[int64][]models.Parameter) ([]models.Function, error) {
 	query := `
 		SELECT 
+			p.oid,
 			p.proname AS function_name,
 			pg_get_function_result(p.oid) AS return_type,
 			pg_get_functiondef(p.oid) AS function_body
@@ -472,7 +774,7 @@ func (r *PostgresReader) getFunctions(ctx context.Context, schemaName string) ([
 		WHERE n.nspname = $1 AND p.prokind = 'f'
 		ORDER BY p.proname`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -481,19 +783,24 @@ func (r *PostgresReader) getFunctions(ctx context.Context, schemaName string) ([
 	var functions []models.Function
 	for rows.Next() {
 		var fn models.Function
-		if err := rows.Scan(&fn.Name, &fn.ReturnType, &fn.Body); err != nil {
+		var oid int64
+		if err := rows.Scan(&oid, &fn.Name, &fn.ReturnType, &fn.Body); err != nil {
 			return nil, err
 		}
 		fn.Schema = schemaName
+		fn.Parameters = parameters[oid]
 		functions = append(functions, fn)
 	}
 
 	return functions, nil
 }
 
-func (r *PostgresReader) getProcedures(ctx context.Context, schemaName string) ([]models.Procedure, error) {
+// getProcedures reads the schema's procedures, attaching their arguments from
+// parameters as returned by getRoutineParameters
+func (r *PostgresReader) getProcedures(ctx context.Context, schemaName string, parameters map[int64][]models.Parameter) ([]models.Procedure, error) {
 	query := `
 		SELECT 
+			p.oid,
 			p.proname AS procedure_name,
 			pg_get_functiondef(p.oid) AS procedure_body
 		FROM pg_proc p
@@ -501,7 +808,7 @@ func (r *PostgresReader) getProcedures(ctx context.Context, schemaName string) (
 		WHERE n.nspname = $1 AND p.prokind = 'p'
 		ORDER BY p.proname`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -510,16 +817,65 @@ func (r *PostgresReader) getProcedures(ctx context.Context, schemaName string) (
 	var procedures []models.Procedure
 	for rows.Next() {
 		var proc models.Procedure
-		if err := rows.Scan(&proc.Name, &proc.Body); err != nil {
+		var oid int64
+		if err := rows.Scan(&oid, &proc.Name, &proc.Body); err != nil {
 			return nil, err
 		}
 		proc.Schema = schemaName
+		proc.Parameters = parameters[oid]
 		procedures = append(procedures, proc)
 	}
 
 	return procedures, nil
 }
 
+// getRoutineParameters returns the arguments of every function and procedure
+// in the schema keyed by routine oid, so overloaded routines can be told apart
+func (r *PostgresReader) getRoutineParameters(ctx context.Context, schemaName string) (map[int64][]models.Parameter, error) {
+	query := `
+		SELECT 
+			p.oid,
+			COALESCE(p.proargnames[a.ordinality], '') AS arg_name,
+			format_type(a.argtype, NULL) AS arg_type,
+			COALESCE(p.proargmodes[a.ordinality], 'i') AS arg_mode
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		CROSS JOIN LATERAL unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[]))
+			WITH ORDINALITY AS a(argtype, ordinality)
+		WHERE n.nspname = $1 AND p.prokind IN ('f', 'p')
+		ORDER BY p.oid, a.ordinality`
+
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parameters := make(map[int64][]models.Parameter)
+	for rows.Next() {
+		var oid int64
+		var param models.Parameter
+		var mode string
+
+		if err := rows.Scan(&oid, &param.Name, &param.DataType, &mode); err != nil {
+			return nil, err
+		}
+
+		switch mode {
+		case "o", "t":
+			param.Direction = models.Out
+		case "b":
+			param.Direction = models.InOut
+		default:
+			param.Direction = models.In
+		}
+
+		parameters[oid] = append(parameters[oid], param)
+	}
+
+	return parameters, rows.Err()
+}
+
 func (r *PostgresReader) getTriggers(ctx context.Context, schemaName string) ([]models.Trigger, error) {
 	query := `
 		SELECT 
@@ -541,7 +897,7 @@ func (r *PostgresReader) getTriggers(ctx context.Context, schemaName string) ([]
 		WHERE n.nspname = $1 AND NOT t.tgisinternal
 		ORDER BY t.tgname`
 
-	rows, err := r.db.QueryContext(ctx, query, schemaName)
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
 	if err != nil {
 		return nil, err
 	}
@@ -580,8 +936,46 @@ func (r *PostgresReader) getTriggers(ctx context.Context, schemaName string) ([]
 	return triggers, nil
 }
 
+// getExtensions returns the extensions installed in the schema. Extensions
+// in other schemas, such as plpgsql in pg_catalog, are left out so they do
+// not show up in, and change the fingerprint of, every schema. The schema
+// itself is left blank, as for foreign keys within it, so the same
+// extension compares equal across differently named schemas.
+func (r *PostgresReader) getExtensions(ctx context.Context, schemaName string) ([]models.Extension, error) {
+	query := `
+		SELECT 
+			e.extname,
+			e.extversion,
+			n.nspname
+		FROM pg_extension e
+		JOIN pg_namespace n ON n.oid = e.extnamespace
+		WHERE n.nspname = $1
+		ORDER BY e.extname`
+
+	rows, err := r.q.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var extensions []models.Extension
+	for rows.Next() {
+		var ext models.Extension
+		if err := rows.Scan(&ext.Name, &ext.Version, &ext.Schema); err != nil {
+			return nil, err
+		}
+		if ext.Schema == schemaName {
+			ext.Schema = ""
+		}
+		extensions = append(extensions, ext)
+	}
+
+	return extensions, nil
+}
+
 func (r *PostgresReader) Close() error {
 	if r.db != nil {
+		r.EndSnapshot()
 		return r.db.Close()
 	}
 	return nil
@@ -596,7 +990,7 @@ func (r *PostgresReader) GetTableRowCount(ctx context.Context, schemaName, table
 		pq.QuoteIdentifier(schemaName),
 		pq.QuoteIdentifier(tableName))
 
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.q.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get row count: %w", err)
 	}
@@ -619,7 +1013,7 @@ func (r *PostgresReader) GetColumnSamples(ctx context.Context, schemaName, table
 		pq.QuoteIdentifier(columnName),
 		limit)
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get column samples: %w", err)
 	}
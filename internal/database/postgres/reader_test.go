@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/compare"
+	"github.com/nechja/schemalyzer/internal/database"
+	"github.com/nechja/schemalyzer/internal/database/fakesql"
+	"github.com/nechja/schemalyzer/internal/fingerprint"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeSchema serves a schema with the given number of tables, each with
+// one column and one index
+func newFakeSchema(tables int) *fakesql.Driver {
+	fake := fakesql.New()
+
+	var tableRows, columnRows, indexRows [][]driver.Value
+	for i := 0; i < tables; i++ {
+		name := fmt.Sprintf("table_%04d", i)
+		tableRows = append(tableRows, []driver.Value{name, nil, false, false})
+		columnRows = append(columnRows, []driver.Value{name, "id", "integer", "NO", nil, int64(1), nil})
+		indexRows = append(indexRows, []driver.Value{name, name + "_idx", false, "btree", "{id}"})
+	}
+
+	fake.Handle("FROM information_schema.tables t", fakesql.Result{
+		Columns: []string{"table_name", "obj_description", "relrowsecurity", "relforcerowsecurity"},
+		Rows:    tableRows,
+	})
+	fake.Handle("FROM information_schema.columns c", fakesql.Result{
+		Columns: []string{"table_name", "column_name", "data_type", "is_nullable", "column_default", "ordinal_position", "col_description"},
+		Rows:    columnRows,
+	})
+	fake.Handle("FROM pg_index idx", fakesql.Result{
+		Columns: []string{"table_name", "index_name", "indisunique", "index_type", "column_names"},
+		Rows:    indexRows,
+	})
+	return fake
+}
+
+func TestGetSchemaAssemblesBatchedCatalog(t *testing.T) {
+	fake := newFakeSchema(3)
+	reader := newTestReader(fake)
+
+	schema, err := reader.GetSchema(context.Background(), "public")
+	assert.NoError(t, err)
+	assert.Len(t, schema.Tables, 3)
+
+	for _, table := range schema.Tables {
+		assert.Equal(t, []models.Column{{Name: "id", DataType: "integer", Position: 1}}, table.Columns)
+		if assert.Len(t, table.Indexes, 1) {
+			assert.Equal(t, table.Name+"_idx", table.Indexes[0].Name)
+			assert.Equal(t, table.Name, table.Indexes[0].TableName)
+			assert.Equal(t, []string{"id"}, table.Indexes[0].Columns)
+		}
+	}
+}
+
+func TestGetSchemaRoundTripsIndependentOfTableCount(t *testing.T) {
+	small := newFakeSchema(2)
+	_, err := newTestReader(small).GetSchema(context.Background(), "public")
+	assert.NoError(t, err)
+
+	large := newFakeSchema(200)
+	_, err = newTestReader(large).GetSchema(context.Background(), "public")
+	assert.NoError(t, err)
+
+	assert.Equal(t, small.RoundTrips(), large.RoundTrips())
+}
+
+func TestGetSchemaReadsRoutineParametersOnce(t *testing.T) {
+	fake := newFakeSchema(1)
+	_, err := newTestReader(fake).GetSchema(context.Background(), "public")
+	assert.NoError(t, err)
+
+	reads := 0
+	for _, statement := range fake.Statements() {
+		if strings.Contains(statement, "proargnames") {
+			reads++
+		}
+	}
+	assert.Equal(t, 1, reads)
+}
+
+func TestGetSchemaReadsColumnsOnceForTablesAndViews(t *testing.T) {
+	fake := fakesql.New()
+	fake.Handle("FROM information_schema.tables t", fakesql.Result{
+		Columns: []string{"table_name", "obj_description", "relrowsecurity", "relforcerowsecurity"},
+		Rows:    [][]driver.Value{{"table_0000", nil, false, false}},
+	})
+	fake.Handle("FROM information_schema.views", fakesql.Result{
+		Columns: []string{"table_name", "view_definition"},
+		Rows:    [][]driver.Value{{"active_tables", "SELECT id FROM table_0000"}},
+	})
+	fake.Handle("FROM information_schema.columns c", fakesql.Result{
+		Columns: []string{"table_name", "column_name", "data_type", "is_nullable", "column_default", "ordinal_position", "col_description"},
+		Rows: [][]driver.Value{
+			{"table_0000", "id", "integer", "NO", nil, int64(1), nil},
+			{"active_tables", "id", "integer", "YES", nil, int64(1), nil},
+		},
+	})
+	schema, err := newTestReader(fake).GetSchema(context.Background(), "public")
+	assert.NoError(t, err)
+	if assert.Len(t, schema.Views, 1) {
+		assert.Equal(t, []models.Column{{Name: "id", DataType: "integer", IsNullable: true, Position: 1}}, schema.Views[0].Columns)
+	}
+
+	reads := 0
+	for _, statement := range fake.Statements() {
+		if strings.Contains(statement, "information_schema.columns") {
+			reads++
+		}
+	}
+	assert.Equal(t, 1, reads)
+}
+
+func BenchmarkGetSchema(b *testing.B) {
+	for _, tables := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("tables=%d", tables), func(b *testing.B) {
+			fake := newFakeSchema(tables)
+			reader := newTestReader(fake)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := reader.GetSchema(context.Background(), "public"); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(fake.RoundTrips())/float64(b.N), "roundtrips/op")
+		})
+	}
+}
+
+func TestExtensionsInOwnSchemaCompareAcrossSchemaNames(t *testing.T) {
+	read := func(schemaName string) *models.Schema {
+		fake := newFakeSchema(1)
+		fake.Handle("FROM pg_extension e", fakesql.Result{
+			Columns: []string{"extname", "extversion", "nspname"},
+			Rows:    [][]driver.Value{{"pgcrypto", "1.3", schemaName}},
+		})
+		schema, err := newTestReader(fake).GetSchema(context.Background(), schemaName)
+		assert.NoError(t, err)
+		return schema
+	}
+	staging, prod := read("staging"), read("prod")
+
+	assert.Equal(t, []models.Extension{{Name: "pgcrypto", Version: "1.3"}}, staging.Extensions)
+	assert.Empty(t, compare.NewComparer().Compare(staging, prod).Differences)
+
+	hasher := fingerprint.NewHasher()
+	stagingHash, err := hasher.GenerateFingerprint(staging)
+	assert.NoError(t, err)
+	prodHash, err := hasher.GenerateFingerprint(prod)
+	assert.NoError(t, err)
+	assert.Equal(t, stagingHash, prodHash)
+}
+
+func TestConnectAppliesStatementTimeout(t *testing.T) {
+	fake := fakesql.New()
+	reader := NewPostgresReaderWithOptions(database.ReaderOptions{MaxOpenConns: 1, StatementTimeout: 1500 * time.Millisecond})
+
+	assert.NoError(t, reader.open(context.Background(), fake.Connector()))
+	defer reader.Close()
+
+	assert.Equal(t, []string{"SET statement_timeout = 1500"}, fake.Statements())
+}
+
+func newTestReader(fake *fakesql.Driver) *PostgresReader {
+	db := fake.DB()
+	return &PostgresReader{db: db, options: database.DefaultReaderOptions(), q: db}
+}
+
+func TestSnapshotFetchersImportExportedSnapshot(t *testing.T) {
+	fake := newFakeSchema(3)
+	fake.Handle("SELECT pg_export_snapshot()", fakesql.Result{
+		Columns: []string{"pg_export_snapshot"},
+		Rows:    [][]driver.Value{{"00000003-0000001B-1"}},
+	})
+	reader := newTestReader(fake)
+
+	assert.NoError(t, reader.BeginSnapshot(context.Background()))
+	schema, err := reader.GetSchema(context.Background(), "public")
+	assert.NoError(t, reader.EndSnapshot())
+	assert.NoError(t, err)
+	assert.Len(t, schema.Tables, 3)
+
+	imports := 0
+	for _, statement := range fake.Statements() {
+		if statement == "SET TRANSACTION SNAPSHOT '00000003-0000001B-1'" {
+			imports++
+		}
+	}
+	assert.Equal(t, 6, imports, "every parallel fetcher imports the snapshot")
+}
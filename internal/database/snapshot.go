@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// DefaultSchemaConcurrency is the number of schemas GetDatabase reads at once
+const DefaultSchemaConcurrency = 4
+
+// ListSchemasMatching returns the schemas whose names match a shell glob
+// such as "tenant_*". Matching ignores case since Oracle reports upper-case names.
+func ListSchemasMatching(ctx context.Context, reader SchemaReader, pattern string) ([]string, error) {
+	schemas, err := reader.ListSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pattern == "" || pattern == "*" {
+		return schemas, nil
+	}
+
+	var matched []string
+	for _, name := range schemas {
+		ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema pattern %q: %w", pattern, err)
+		}
+		if ok {
+			matched = append(matched, name)
+		}
+	}
+
+	return matched, nil
+}
+
+// GetDatabase reads every schema matching pattern concurrently and assembles
+// them into a database snapshot. Readers attach extensions to the schema
+// they are installed in; the snapshot holds them all, as extensions apply to
+// the whole database.
+func GetDatabase(ctx context.Context, reader SchemaReader, pattern string, concurrency int) (*models.Database, error) {
+	names, err := ListSchemasMatching(ctx, reader, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no schemas match %q", pattern)
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	schemas := make([]models.Schema, len(names))
+	errs := make([]error, len(names))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			schema, err := reader.GetSchema(ctx, name)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to read schema %s: %w", name, err)
+				return
+			}
+			schemas[i] = *schema
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	db := &models.Database{
+		DatabaseType: schemas[0].DatabaseType,
+		Schemas:      schemas,
+	}
+	// Readers leave an extension's own schema blank; database-wide, the
+	// schema it is installed in tells extensions apart
+	for i := range db.Schemas {
+		for _, ext := range db.Schemas[i].Extensions {
+			if ext.Schema == "" {
+				ext.Schema = db.Schemas[i].Name
+			}
+			db.Extensions = append(db.Extensions, ext)
+		}
+		db.Schemas[i].Extensions = nil
+	}
+	sort.Slice(db.Extensions, func(i, j int) bool {
+		return db.Extensions[i].Name < db.Extensions[j].Name
+	})
+
+	return db, nil
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReader struct {
+	schemas map[string]*models.Schema
+}
+
+func (f *fakeReader) Connect(ctx context.Context, connectionString string) error { return nil }
+
+func (f *fakeReader) ListSchemas(ctx context.Context) ([]string, error) {
+	return []string{"public", "tenant_001", "tenant_002", "TENANT_003"}, nil
+}
+
+func (f *fakeReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	schema, ok := f.schemas[schemaName]
+	if !ok {
+		return nil, fmt.Errorf("schema %s not found", schemaName)
+	}
+	return schema, nil
+}
+
+func (f *fakeReader) Close() error { return nil }
+
+func TestListSchemasMatching(t *testing.T) {
+	names, err := ListSchemasMatching(context.Background(), &fakeReader{}, "tenant_*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tenant_001", "tenant_002", "TENANT_003"}, names)
+
+	names, err = ListSchemasMatching(context.Background(), &fakeReader{}, "*")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(names))
+}
+
+func TestGetDatabase(t *testing.T) {
+	// Readers leave the schema an extension is installed in blank
+	pgcrypto := models.Extension{Name: "pgcrypto", Version: "1.3"}
+	citext := models.Extension{Name: "citext", Version: "1.6"}
+	reader := &fakeReader{schemas: map[string]*models.Schema{
+		"tenant_001": {Name: "tenant_001", DatabaseType: models.PostgreSQL, Extensions: []models.Extension{citext}},
+		"tenant_002": {Name: "tenant_002", DatabaseType: models.PostgreSQL, Extensions: []models.Extension{pgcrypto}},
+		"TENANT_003": {Name: "TENANT_003", DatabaseType: models.PostgreSQL},
+	}}
+
+	db, err := GetDatabase(context.Background(), reader, "tenant_*", 2)
+	assert.NoError(t, err)
+	if assert.NotNil(t, db) {
+		assert.Equal(t, models.PostgreSQL, db.DatabaseType)
+		assert.Equal(t, 3, len(db.Schemas))
+		assert.Equal(t, "tenant_001", db.Schemas[0].Name)
+		assert.Equal(t, []models.Extension{
+			{Name: "citext", Version: "1.6", Schema: "tenant_001"},
+			{Name: "pgcrypto", Version: "1.3", Schema: "tenant_002"},
+		}, db.Extensions, "extensions of every schema, in name order, with their schema")
+		for _, schema := range db.Schemas {
+			assert.Empty(t, schema.Extensions)
+		}
+	}
+
+	_, err = GetDatabase(context.Background(), reader, "*", 2)
+	assert.Error(t, err, "reading an unknown schema should fail")
+
+	_, err = GetDatabase(context.Background(), reader, "nothing_*", 2)
+	assert.Error(t, err)
+}
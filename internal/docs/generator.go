@@ -236,6 +236,15 @@ func (g *MarkdownDocGenerator) Generate(schema *models.Schema) (string, error) {
 	if len(schema.Triggers) > 0 {
 		sb.WriteString("- [Triggers](#triggers)\n")
 	}
+	if len(schema.Synonyms) > 0 {
+		sb.WriteString("- [Synonyms](#synonyms)\n")
+	}
+	if len(schema.Extensions) > 0 {
+		sb.WriteString("- [Extensions](#extensions)\n")
+	}
+	if len(schema.Events) > 0 {
+		sb.WriteString("- [Events](#events)\n")
+	}
 	sb.WriteString("\n")
 	
 	// Tables section
@@ -290,6 +299,44 @@ func (g *MarkdownDocGenerator) Generate(schema *models.Schema) (string, error) {
 		}
 	}
 	
+	// Synonyms section
+	if len(schema.Synonyms) > 0 {
+		sb.WriteString("## Synonyms\n\n")
+		sb.WriteString("| Synonym | Target | DB Link |\n")
+		sb.WriteString("|---------|--------|---------|\n")
+		for _, synonym := range schema.Synonyms {
+			target := synonym.TargetObject
+			if synonym.TargetOwner != "" {
+				target = synonym.TargetOwner + "." + target
+			}
+			dbLink := "-"
+			if synonym.DBLink != "" {
+				dbLink = synonym.DBLink
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", synonym.Name, target, dbLink))
+		}
+		sb.WriteString("\n")
+	}
+	
+	// Extensions section
+	if len(schema.Extensions) > 0 {
+		sb.WriteString("## Extensions\n\n")
+		sb.WriteString("| Extension | Version | Schema |\n")
+		sb.WriteString("|-----------|---------|--------|\n")
+		for _, ext := range schema.Extensions {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", ext.Name, ext.Version, ext.Schema))
+		}
+		sb.WriteString("\n")
+	}
+	
+	// Events section
+	if len(schema.Events) > 0 {
+		sb.WriteString("## Events\n\n")
+		for _, event := range schema.Events {
+			g.generateMarkdownEvent(&sb, event)
+		}
+	}
+	
 	return sb.String(), nil
 }
 
@@ -349,19 +396,47 @@ func (g *MarkdownDocGenerator) generateMarkdownTable(sb *strings.Builder, table
 	if len(table.Constraints) > 0 {
 		sb.WriteString("\n**Constraints:**\n\n")
 		for _, constraint := range table.Constraints {
+			state := constraintState(constraint)
 			switch constraint.Type {
 			case models.PrimaryKey:
-				sb.WriteString(fmt.Sprintf("- **Primary Key** (%s): %s\n", constraint.Name, strings.Join(constraint.Columns, ", ")))
+				sb.WriteString(fmt.Sprintf("- **Primary Key** (%s): %s%s\n", constraint.Name, strings.Join(constraint.Columns, ", "), state))
 			case models.ForeignKey:
-				sb.WriteString(fmt.Sprintf("- **Foreign Key** (%s): %s → %s.%s\n", 
+				refTable := constraint.ReferencedTable
+				if constraint.ReferencedSchema != "" {
+					refTable = constraint.ReferencedSchema + "." + refTable
+				}
+				sb.WriteString(fmt.Sprintf("- **Foreign Key** (%s): %s → %s.%s%s\n", 
 					constraint.Name, 
 					strings.Join(constraint.Columns, ", "),
-					constraint.ReferencedTable,
-					strings.Join(constraint.ReferencedColumn, ", ")))
+					refTable,
+					strings.Join(constraint.ReferencedColumn, ", "),
+					state))
 			case models.Unique:
-				sb.WriteString(fmt.Sprintf("- **Unique** (%s): %s\n", constraint.Name, strings.Join(constraint.Columns, ", ")))
+				sb.WriteString(fmt.Sprintf("- **Unique** (%s): %s%s\n", constraint.Name, strings.Join(constraint.Columns, ", "), state))
 			case models.Check:
-				sb.WriteString(fmt.Sprintf("- **Check** (%s): %s\n", constraint.Name, constraint.CheckExpression))
+				sb.WriteString(fmt.Sprintf("- **Check** (%s): %s%s\n", constraint.Name, constraint.CheckExpression, state))
+			case models.Exclude:
+				sb.WriteString(fmt.Sprintf("- **Exclude** (%s): %s%s\n", constraint.Name, constraint.ExclusionDefinition, state))
+			}
+		}
+	}
+	
+	// Row-level security section
+	if table.RowSecurityEnabled || table.RowSecurityForced || len(table.Policies) > 0 {
+		sb.WriteString("\n**Row-Level Security:**\n\n")
+		sb.WriteString(fmt.Sprintf("- **Enabled**: %v\n", table.RowSecurityEnabled))
+		sb.WriteString(fmt.Sprintf("- **Forced**: %v\n", table.RowSecurityForced))
+		for _, policy := range table.Policies {
+			kind := "PERMISSIVE"
+			if policy.IsRestrictive {
+				kind = "RESTRICTIVE"
+			}
+			sb.WriteString(fmt.Sprintf("- **Policy** (%s): %s %s TO %s\n", policy.Name, kind, policy.Command, strings.Join(policy.Roles, ", ")))
+			if policy.Using != "" {
+				sb.WriteString(fmt.Sprintf("  - USING `%s`\n", policy.Using))
+			}
+			if policy.WithCheck != "" {
+				sb.WriteString(fmt.Sprintf("  - WITH CHECK `%s`\n", policy.WithCheck))
 			}
 		}
 	}
@@ -381,6 +456,28 @@ func (g *MarkdownDocGenerator) generateMarkdownTable(sb *strings.Builder, table
 	sb.WriteString("\n")
 }
 
+// constraintState renders non-default constraint state as a suffix, e.g. " _(DISABLED, NOT VALIDATED)_"
+func constraintState(constraint models.Constraint) string {
+	var flags []string
+	if constraint.IsDisabled {
+		flags = append(flags, "DISABLED")
+	}
+	if constraint.IsNotValidated {
+		flags = append(flags, "NOT VALIDATED")
+	}
+	if constraint.IsDeferrable {
+		if constraint.IsInitiallyDeferred {
+			flags = append(flags, "DEFERRABLE INITIALLY DEFERRED")
+		} else {
+			flags = append(flags, "DEFERRABLE")
+		}
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return " _(" + strings.Join(flags, ", ") + ")_"
+}
+
 func (g *MarkdownDocGenerator) generateMarkdownView(sb *strings.Builder, view models.View) {
 	sb.WriteString(fmt.Sprintf("### %s\n\n", view.Name))
 	
@@ -421,4 +518,15 @@ func (g *MarkdownDocGenerator) generateMarkdownTrigger(sb *strings.Builder, trig
 	sb.WriteString("```sql\n")
 	sb.WriteString(trigger.Body)
 	sb.WriteString("\n```\n\n")
+}
+
+func (g *MarkdownDocGenerator) generateMarkdownEvent(sb *strings.Builder, event models.Event) {
+	sb.WriteString(fmt.Sprintf("### %s\n\n", event.Name))
+	sb.WriteString(fmt.Sprintf("- **Schedule**: %s\n", event.Schedule))
+	sb.WriteString(fmt.Sprintf("- **Status**: %s\n", event.Status))
+	sb.WriteString(fmt.Sprintf("- **On Completion**: %s\n", event.OnCompletion))
+	sb.WriteString("\n**Body:**\n\n")
+	sb.WriteString("```sql\n")
+	sb.WriteString(event.Body)
+	sb.WriteString("\n```\n\n")
 }
\ No newline at end of file
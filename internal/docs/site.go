@@ -0,0 +1,668 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// SiteGenerator renders a schema as a static HTML site: an index with
+// search, and a page per table, view, function and procedure. Unlike the
+// single-file generators it scales to schemas with thousands of tables.
+type SiteGenerator struct{}
+
+func NewSiteGenerator() *SiteGenerator {
+	return &SiteGenerator{}
+}
+
+// maxERDNeighbors bounds the tables drawn on each side of a table's
+// relationship diagram
+const maxERDNeighbors = 25
+
+type siteEntry struct {
+	Name    string
+	URL     string
+	Detail  string
+	Comment string
+	Search  string
+}
+
+type siteIndex struct {
+	Stats      *models.SchemaStats
+	Tables     []siteEntry
+	Views      []siteEntry
+	Functions  []siteEntry
+	Procedures []siteEntry
+	Sequences  []models.Sequence
+	Triggers   []siteTrigger
+	Synonyms   []models.Synonym
+	Extensions []models.Extension
+	Events     []models.Event
+}
+
+type siteTrigger struct {
+	models.Trigger
+	TableURL string
+}
+
+type siteColumn struct {
+	models.Column
+	Keys    string
+	Default string
+	Samples string
+}
+
+type siteConstraint struct {
+	Kind   string
+	Name   string
+	Detail string
+	State  string
+}
+
+// siteForeignKey is a foreign key as seen from one of its two tables
+type siteForeignKey struct {
+	Name       string
+	Columns    string
+	Table      string
+	URL        string
+	RefColumns string
+	OnUpdate   string
+	OnDelete   string
+}
+
+type siteTable struct {
+	models.Table
+	Columns     []siteColumn
+	HasSamples  bool
+	Constraints []siteConstraint
+	Indexes     []models.Index
+	Outgoing    []siteForeignKey
+	Incoming    []siteForeignKey
+	Triggers    []models.Trigger
+	ERD         template.HTML
+}
+
+type siteRoutine struct {
+	Kind      string
+	Name      string
+	Overloads []siteOverload
+}
+
+type siteOverload struct {
+	Signature  string
+	Parameters []models.Parameter
+	ReturnType string
+	Body       string
+}
+
+// sitePage is the data of one page. Root leads from the page back to the
+// site root, so the site works from any directory or file:// URL.
+type sitePage struct {
+	Root         string
+	Title        string
+	Schema       string
+	DatabaseType models.DatabaseType
+	Generated    string
+	Index        *siteIndex
+	Table        *siteTable
+	View         *models.View
+	Routine      *siteRoutine
+}
+
+// Generate returns the files of the site keyed by their path relative to
+// the site root
+func (g *SiteGenerator) Generate(schema *models.Schema) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	generated := time.Now().Format("2006-01-02 15:04")
+	page := func(root, title string) sitePage {
+		return sitePage{Root: root, Title: title, Schema: schema.Name, DatabaseType: schema.DatabaseType, Generated: generated}
+	}
+	render := func(file string, data sitePage) error {
+		var buf bytes.Buffer
+		if err := siteTemplate.ExecuteTemplate(&buf, "page", data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", file, err)
+		}
+		files[file] = buf.Bytes()
+		return nil
+	}
+
+	tableURLs := make(map[string]string, len(schema.Tables))
+	for _, table := range schema.Tables {
+		tableURLs[table.Name] = pageURL("tables", table.Name)
+	}
+	incoming := incomingForeignKeys(schema, tableURLs)
+
+	index := &siteIndex{
+		Stats:      schema.Stats,
+		Sequences:  schema.Sequences,
+		Synonyms:   schema.Synonyms,
+		Extensions: schema.Extensions,
+		Events:     schema.Events,
+	}
+
+	for _, table := range schema.Tables {
+		t := newSiteTable(schema, table, tableURLs, incoming[table.Name])
+		p := page("../", "Table "+table.Name)
+		p.Table = t
+		if err := render(tableURLs[table.Name], p); err != nil {
+			return nil, err
+		}
+
+		detail := plural(len(table.Columns), "column")
+		if table.RowCount != nil {
+			detail += " · " + plural(int(*table.RowCount), "row")
+		}
+		search := []string{table.Name, table.Comment}
+		for _, column := range table.Columns {
+			search = append(search, column.Name, column.Comment)
+		}
+		index.Tables = append(index.Tables, siteEntry{
+			Name: table.Name, URL: tableURLs[table.Name], Detail: detail, Comment: table.Comment,
+			Search: strings.ToLower(strings.Join(search, " ")),
+		})
+	}
+
+	for i := range schema.Views {
+		view := schema.Views[i]
+		url := pageURL("views", view.Name)
+		p := page("../", "View "+view.Name)
+		p.View = &view
+		if err := render(url, p); err != nil {
+			return nil, err
+		}
+
+		search := []string{view.Name}
+		for _, column := range view.Columns {
+			search = append(search, column.Name)
+		}
+		index.Views = append(index.Views, siteEntry{
+			Name: view.Name, URL: url, Detail: plural(len(view.Columns), "column"),
+			Search: strings.ToLower(strings.Join(search, " ")),
+		})
+	}
+
+	functions := make(map[string]*siteRoutine)
+	for _, fn := range schema.Functions {
+		addOverload(functions, "Function", fn.Name, siteOverload{models.RoutineSignature(fn.Parameters), fn.Parameters, fn.ReturnType, fn.Body})
+	}
+	procedures := make(map[string]*siteRoutine)
+	for _, proc := range schema.Procedures {
+		addOverload(procedures, "Procedure", proc.Name, siteOverload{models.RoutineSignature(proc.Parameters), proc.Parameters, "", proc.Body})
+	}
+	for _, routines := range []struct {
+		dir     string
+		byName  map[string]*siteRoutine
+		entries *[]siteEntry
+	}{{"functions", functions, &index.Functions}, {"procedures", procedures, &index.Procedures}} {
+		for _, name := range sortedKeys(routines.byName) {
+			routine := routines.byName[name]
+			url := pageURL(routines.dir, name)
+			p := page("../", routine.Kind+" "+name)
+			p.Routine = routine
+			if err := render(url, p); err != nil {
+				return nil, err
+			}
+
+			var signatures []string
+			for _, overload := range routine.Overloads {
+				signatures = append(signatures, "("+overload.Signature+")")
+			}
+			*routines.entries = append(*routines.entries, siteEntry{
+				Name: name, URL: url, Detail: strings.Join(signatures, " "),
+				Search: strings.ToLower(name),
+			})
+		}
+	}
+
+	for _, trigger := range schema.Triggers {
+		index.Triggers = append(index.Triggers, siteTrigger{Trigger: trigger, TableURL: tableURLs[trigger.TableName]})
+	}
+
+	p := page("", schema.Name)
+	p.Index = index
+	if err := render("index.html", p); err != nil {
+		return nil, err
+	}
+	files["style.css"] = []byte(siteCSS)
+	return files, nil
+}
+
+func addOverload(routines map[string]*siteRoutine, kind, name string, overload siteOverload) {
+	routine, ok := routines[name]
+	if !ok {
+		routine = &siteRoutine{Kind: kind, Name: name}
+		routines[name] = routine
+	}
+	routine.Overloads = append(routine.Overloads, overload)
+}
+
+// incomingForeignKeys finds, for every table, the foreign keys of other
+// tables that reference it
+func incomingForeignKeys(schema *models.Schema, tableURLs map[string]string) map[string][]siteForeignKey {
+	incoming := make(map[string][]siteForeignKey)
+	for _, table := range schema.Tables {
+		for _, constraint := range table.Constraints {
+			// Foreign keys into other schemas reference tables not in the site
+			if constraint.Type != models.ForeignKey || constraint.ReferencedSchema != "" {
+				continue
+			}
+			incoming[constraint.ReferencedTable] = append(incoming[constraint.ReferencedTable], siteForeignKey{
+				Name:       constraint.Name,
+				Columns:    strings.Join(constraint.Columns, ", "),
+				Table:      table.Name,
+				URL:        tableURLs[table.Name],
+				RefColumns: strings.Join(constraint.ReferencedColumn, ", "),
+				OnUpdate:   constraint.OnUpdate,
+				OnDelete:   constraint.OnDelete,
+			})
+		}
+	}
+	return incoming
+}
+
+func newSiteTable(schema *models.Schema, table models.Table, tableURLs map[string]string, incoming []siteForeignKey) *siteTable {
+	t := &siteTable{Table: table, Incoming: incoming, Indexes: table.Indexes}
+
+	foreignKeyColumns := make(map[string]bool)
+	for _, constraint := range table.Constraints {
+		switch constraint.Type {
+		case models.ForeignKey:
+			refTable, url := constraint.ReferencedTable, ""
+			if constraint.ReferencedSchema != "" {
+				refTable = constraint.ReferencedSchema + "." + refTable
+			} else {
+				url = tableURLs[constraint.ReferencedTable]
+			}
+			t.Outgoing = append(t.Outgoing, siteForeignKey{
+				Name:       constraint.Name,
+				Columns:    strings.Join(constraint.Columns, ", "),
+				Table:      refTable,
+				URL:        url,
+				RefColumns: strings.Join(constraint.ReferencedColumn, ", "),
+				OnUpdate:   constraint.OnUpdate,
+				OnDelete:   constraint.OnDelete,
+			})
+			for _, column := range constraint.Columns {
+				foreignKeyColumns[column] = true
+			}
+		case models.PrimaryKey:
+			t.Constraints = append(t.Constraints, siteConstraint{"Primary key", constraint.Name, strings.Join(constraint.Columns, ", "), siteConstraintState(constraint)})
+		case models.Unique:
+			t.Constraints = append(t.Constraints, siteConstraint{"Unique", constraint.Name, strings.Join(constraint.Columns, ", "), siteConstraintState(constraint)})
+		case models.Check:
+			t.Constraints = append(t.Constraints, siteConstraint{"Check", constraint.Name, constraint.CheckExpression, siteConstraintState(constraint)})
+		case models.Exclude:
+			t.Constraints = append(t.Constraints, siteConstraint{"Exclude", constraint.Name, constraint.ExclusionDefinition, siteConstraintState(constraint)})
+		}
+	}
+
+	for _, column := range table.Columns {
+		var keys []string
+		if column.IsPrimaryKey {
+			keys = append(keys, "PK")
+		}
+		if column.IsUnique {
+			keys = append(keys, "UNIQUE")
+		}
+		if foreignKeyColumns[column.Name] {
+			keys = append(keys, "FK")
+		}
+		c := siteColumn{Column: column, Keys: strings.Join(keys, ", "), Samples: strings.Join(column.Samples, ", ")}
+		if column.DefaultValue != nil {
+			c.Default = *column.DefaultValue
+		}
+		t.HasSamples = t.HasSamples || len(column.Samples) > 0
+		t.Columns = append(t.Columns, c)
+	}
+
+	// Some readers list indexes at schema level rather than on the table
+	seen := make(map[string]bool)
+	for _, index := range table.Indexes {
+		seen[index.Name] = true
+	}
+	for _, index := range schema.Indexes {
+		if index.TableName == table.Name && !seen[index.Name] {
+			t.Indexes = append(t.Indexes, index)
+		}
+	}
+
+	for _, trigger := range schema.Triggers {
+		if trigger.TableName == table.Name {
+			t.Triggers = append(t.Triggers, trigger)
+		}
+	}
+
+	t.ERD = erdSVG(table, foreignKeyColumns, t.Outgoing, incoming)
+	return t
+}
+
+// siteConstraintState lists non-default constraint state, e.g. "DISABLED, NOT VALIDATED"
+func siteConstraintState(constraint models.Constraint) string {
+	return strings.TrimSuffix(strings.TrimPrefix(constraintState(constraint), " _("), ")_")
+}
+
+// erdSVG draws a table's relationship neighborhood: the tables that
+// reference it on the left, the table with its key columns in the middle,
+// and the tables it references on the right
+func erdSVG(table models.Table, foreignKeyColumns map[string]bool, outgoing, incoming []siteForeignKey) template.HTML {
+	const (
+		boxWidth   = 220
+		gap        = 90
+		headHeight = 26
+		rowHeight  = 18
+		neighborH  = 26
+		spacing    = 12
+		margin     = 10
+		maxColumns = 10
+	)
+
+	// The middle box lists every column of small tables, otherwise the keys
+	var columns []models.Column
+	hidden := 0
+	for _, column := range table.Columns {
+		if len(table.Columns) <= maxColumns || column.IsPrimaryKey || foreignKeyColumns[column.Name] {
+			columns = append(columns, column)
+		} else {
+			hidden++
+		}
+	}
+
+	left := erdNeighbors(incoming, table.Name)
+	right := erdNeighbors(outgoing, table.Name)
+
+	rows := len(columns)
+	if hidden > 0 {
+		rows++
+	}
+	centerHeight := headHeight + rows*rowHeight + 8
+	sideHeight := func(n int) int { return n*(neighborH+spacing) - spacing }
+	height := max(centerHeight, sideHeight(len(left)), sideHeight(len(right))) + 2*margin
+	width := 3*boxWidth + 2*gap + 2*margin
+
+	leftX, centerX, rightX := margin, margin+boxWidth+gap, margin+2*(boxWidth+gap)
+	centerY := (height - centerHeight) / 2
+	sideY := func(n, i int) int { return (height-sideHeight(n))/2 + i*(neighborH+spacing) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="erd" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Relationships of %s">`,
+		width, height, width, height, template.HTMLEscapeString(table.Name))
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>`)
+
+	edge := func(x1, y1, x2, y2 int, title string) {
+		mid := (x1 + x2) / 2
+		fmt.Fprintf(&b, `<path class="edge" d="M%d,%d C%d,%d %d,%d %d,%d" marker-end="url(#arrow)"><title>%s</title></path>`,
+			x1, y1, mid, y1, mid, y2, x2, y2, template.HTMLEscapeString(title))
+	}
+	neighbor := func(x, y int, n erdNeighbor) {
+		label := n.Table
+		if n.More > 0 {
+			label = fmt.Sprintf("… and %d more", n.More)
+		}
+		box := fmt.Sprintf(`<rect class="neighbor" x="%d" y="%d" width="%d" height="%d" rx="4"/><text x="%d" y="%d">%s</text>`,
+			x, y, boxWidth, neighborH, x+10, y+17, template.HTMLEscapeString(truncate(label, 30)))
+		if n.URL != "" {
+			fmt.Fprintf(&b, `<a href="../%s"><title>%s</title>%s</a>`, template.HTMLEscapeString(n.URL), template.HTMLEscapeString(n.Table), box)
+		} else {
+			b.WriteString(box)
+		}
+	}
+
+	for i, n := range left {
+		y := sideY(len(left), i)
+		neighbor(leftX, y, n)
+		for _, title := range n.Titles {
+			edge(leftX+boxWidth, y+neighborH/2, centerX, centerY+headHeight/2, title)
+		}
+	}
+	for i, n := range right {
+		y := sideY(len(right), i)
+		neighbor(rightX, y, n)
+		for _, title := range n.Titles {
+			edge(centerX+boxWidth, centerY+headHeight/2, rightX, y+neighborH/2, title)
+		}
+	}
+
+	fmt.Fprintf(&b, `<rect class="table" x="%d" y="%d" width="%d" height="%d" rx="4"/>`, centerX, centerY, boxWidth, centerHeight)
+	fmt.Fprintf(&b, `<rect class="head" x="%d" y="%d" width="%d" height="%d" rx="4"/>`, centerX, centerY, boxWidth, headHeight)
+	fmt.Fprintf(&b, `<text class="title" x="%d" y="%d">%s</text>`, centerX+10, centerY+18, template.HTMLEscapeString(truncate(table.Name, 28)))
+	for i, column := range columns {
+		var keys []string
+		if column.IsPrimaryKey {
+			keys = append(keys, "PK")
+		}
+		if foreignKeyColumns[column.Name] {
+			keys = append(keys, "FK")
+		}
+		y := centerY + headHeight + (i+1)*rowHeight
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text><text class="type" x="%d" y="%d" text-anchor="end">%s</text>`,
+			centerX+10, y, template.HTMLEscapeString(truncate(column.Name, 18)),
+			centerX+boxWidth-10, y, template.HTMLEscapeString(truncate(strings.Join(append(keys, simplifyDataType(column.DataType)), " "), 14)))
+	}
+	if hidden > 0 {
+		fmt.Fprintf(&b, `<text class="type" x="%d" y="%d">… %d more columns</text>`, centerX+10, centerY+headHeight+(len(columns)+1)*rowHeight, hidden)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// erdNeighbor is one table next to the diagram's table, with a tooltip
+// per foreign key between them
+type erdNeighbor struct {
+	Table  string
+	URL    string
+	Titles []string
+	More   int
+}
+
+// erdNeighbors merges foreign keys by table, leaving out self-references,
+// which the foreign key lists show
+func erdNeighbors(keys []siteForeignKey, self string) []erdNeighbor {
+	var neighbors []erdNeighbor
+	index := make(map[string]int)
+	for _, key := range keys {
+		if key.Table == self {
+			continue
+		}
+		i, ok := index[key.Table]
+		if !ok {
+			i = len(neighbors)
+			index[key.Table] = i
+			neighbors = append(neighbors, erdNeighbor{Table: key.Table, URL: key.URL})
+		}
+		neighbors[i].Titles = append(neighbors[i].Titles, fmt.Sprintf("%s (%s) → (%s)", key.Name, key.Columns, key.RefColumns))
+	}
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].Table < neighbors[j].Table })
+
+	if len(neighbors) > maxERDNeighbors {
+		more := len(neighbors) - maxERDNeighbors + 1
+		neighbors = append(neighbors[:maxERDNeighbors-1], erdNeighbor{More: more})
+	}
+	return neighbors
+}
+
+// pageURL returns the path of an object's page. Names are kept readable
+// but bytes that are unsafe in file names or URLs are escaped.
+func pageURL(dir, name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-':
+			b.WriteByte(c)
+		case c == '.' && i > 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "~%02x", c)
+		}
+	}
+	return path.Join(dir, b.String()+".html")
+}
+
+func sortedKeys(m map[string]*siteRoutine) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func truncate(text string, size int) string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return text
+	}
+	return string(runes[:size-1]) + "…"
+}
+
+var siteTemplate = template.Must(template.New("site").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`{{define "page"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.Schema}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header><a href="{{.Root}}index.html">{{.Schema}}</a> <span class="muted">{{.DatabaseType}}</span>{{if not .Index}} / {{.Title}}{{end}}</header>
+<main>
+{{if .Index}}{{template "index" .Index}}{{end}}{{if .Table}}{{template "table" .Table}}{{end}}{{if .View}}{{template "view" .View}}{{end}}{{if .Routine}}{{template "routine" .Routine}}{{end}}
+</main>
+<footer>Generated by schemalyzer on {{.Generated}}</footer>
+</body>
+</html>
+{{end}}
+
+{{define "entries"}}<ul class="entries">{{range .}}<li data-search="{{.Search}}"><a href="{{.URL}}">{{.Name}}</a> <span class="muted">{{.Detail}}</span>{{if .Comment}}<div class="comment">{{.Comment}}</div>{{end}}</li>{{end}}</ul>{{end}}
+
+{{define "index"}}<input id="search" type="search" placeholder="Search tables, columns, views and routines" autofocus>
+<p id="matches" class="muted"></p>
+{{with .Stats}}<p class="stats">{{.TableCount}} tables · {{.ViewCount}} views · {{.TotalColumns}} columns · {{.IndexCount}} indexes</p>{{end}}
+{{if .Tables}}<section class="searchable"><h2>Tables <span class="muted">{{len .Tables}}</span></h2>{{template "entries" .Tables}}</section>{{end}}
+{{if .Views}}<section class="searchable"><h2>Views <span class="muted">{{len .Views}}</span></h2>{{template "entries" .Views}}</section>{{end}}
+{{if .Functions}}<section class="searchable"><h2>Functions <span class="muted">{{len .Functions}}</span></h2>{{template "entries" .Functions}}</section>{{end}}
+{{if .Procedures}}<section class="searchable"><h2>Procedures <span class="muted">{{len .Procedures}}</span></h2>{{template "entries" .Procedures}}</section>{{end}}
+{{if .Sequences}}<section><h2>Sequences</h2><table><tr><th>Sequence</th><th>Start</th><th>Increment</th><th>Min</th><th>Max</th><th>Cyclic</th></tr>
+{{range .Sequences}}<tr><td>{{.Name}}</td><td>{{.StartValue}}</td><td>{{.Increment}}</td><td>{{.MinValue}}</td><td>{{.MaxValue}}</td><td>{{if .IsCyclic}}yes{{else}}no{{end}}</td></tr>
+{{end}}</table></section>{{end}}
+{{if .Triggers}}<section><h2>Triggers</h2><table><tr><th>Trigger</th><th>Table</th><th>Timing</th><th>Event</th></tr>
+{{range .Triggers}}<tr><td>{{.Name}}</td><td>{{if .TableURL}}<a href="{{.TableURL}}">{{.TableName}}</a>{{else}}{{.TableName}}{{end}}</td><td>{{.Timing}}</td><td>{{.Event}}</td></tr>
+{{end}}</table></section>{{end}}
+{{if .Synonyms}}<section><h2>Synonyms</h2><table><tr><th>Synonym</th><th>Target</th><th>DB link</th></tr>
+{{range .Synonyms}}<tr><td>{{.Name}}</td><td>{{if .TargetOwner}}{{.TargetOwner}}.{{end}}{{.TargetObject}}</td><td>{{.DBLink}}</td></tr>
+{{end}}</table></section>{{end}}
+{{if .Extensions}}<section><h2>Extensions</h2><table><tr><th>Extension</th><th>Version</th><th>Schema</th></tr>
+{{range .Extensions}}<tr><td>{{.Name}}</td><td>{{.Version}}</td><td>{{.Schema}}</td></tr>
+{{end}}</table></section>{{end}}
+{{if .Events}}<section><h2>Events</h2><table><tr><th>Event</th><th>Schedule</th><th>Status</th></tr>
+{{range .Events}}<tr><td>{{.Name}}</td><td>{{.Schedule}}</td><td>{{.Status}}</td></tr>
+{{end}}</table></section>{{end}}
+<script>
+(function () {
+  var input = document.getElementById("search");
+  var matches = document.getElementById("matches");
+  var items = document.querySelectorAll(".searchable li");
+  input.addEventListener("input", function () {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var shown = 0;
+    items.forEach(function (li) {
+      var text = li.getAttribute("data-search");
+      var match = words.every(function (w) { return text.indexOf(w) >= 0; });
+      li.hidden = !match;
+      if (match) { shown++; }
+    });
+    document.querySelectorAll("section.searchable").forEach(function (section) {
+      section.hidden = !section.querySelector("li:not([hidden])");
+    });
+    matches.textContent = words.length ? shown + " of " + items.length + " objects match" : "";
+  });
+})();
+</script>{{end}}
+
+{{define "foreignKeys"}}<table><tr><th>Constraint</th><th>Columns</th><th>Table</th><th>Referenced columns</th><th>On update</th><th>On delete</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Columns}}</td><td>{{if .URL}}<a href="../{{.URL}}">{{.Table}}</a>{{else}}{{.Table}}{{end}}</td><td>{{.RefColumns}}</td><td>{{.OnUpdate}}</td><td>{{.OnDelete}}</td></tr>
+{{end}}</table>{{end}}
+
+{{define "table"}}<h1>{{.Name}}</h1>
+{{if .Comment}}<p class="comment">{{.Comment}}</p>{{end}}
+{{if .RowCount}}<p class="stats">{{.RowCount}} rows</p>{{end}}
+{{if or .Outgoing .Incoming}}<div class="erd-wrap">{{.ERD}}</div>{{end}}
+<h2>Columns</h2>
+<table><tr><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Keys</th><th>Description</th>{{if .HasSamples}}<th>Samples</th>{{end}}</tr>
+{{range .Columns}}<tr><td><code>{{.Name}}</code></td><td><code>{{.DataType}}</code></td><td>{{if .IsNullable}}yes{{else}}no{{end}}</td><td><code>{{.Default}}</code></td><td>{{.Keys}}</td><td>{{.Comment}}</td>{{if $.HasSamples}}<td class="muted">{{.Samples}}</td>{{end}}</tr>
+{{end}}</table>
+{{if .Constraints}}<h2>Constraints</h2><table><tr><th>Constraint</th><th>Kind</th><th>Definition</th><th>State</th></tr>
+{{range .Constraints}}<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td><code>{{.Detail}}</code></td><td>{{.State}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Outgoing}}<h2>References</h2>{{template "foreignKeys" .Outgoing}}{{end}}
+{{if .Incoming}}<h2>Referenced by</h2>{{template "foreignKeys" .Incoming}}{{end}}
+{{if .Indexes}}<h2>Indexes</h2><table><tr><th>Index</th><th>Columns</th><th>Unique</th><th>Type</th></tr>
+{{range .Indexes}}<tr><td>{{.Name}}</td><td>{{join .Columns ", "}}</td><td>{{if .IsUnique}}yes{{else}}no{{end}}</td><td>{{.Type}}</td></tr>
+{{end}}</table>{{end}}
+{{if or .RowSecurityEnabled .Policies}}<h2>Row-level security</h2><p>Enabled: {{.RowSecurityEnabled}} · Forced: {{.RowSecurityForced}}</p>
+{{if .Policies}}<table><tr><th>Policy</th><th>Command</th><th>Roles</th><th>Using</th><th>With check</th></tr>
+{{range .Policies}}<tr><td>{{.Name}}{{if .IsRestrictive}} (restrictive){{end}}</td><td>{{.Command}}</td><td>{{join .Roles ", "}}</td><td><code>{{.Using}}</code></td><td><code>{{.WithCheck}}</code></td></tr>
+{{end}}</table>{{end}}{{end}}
+{{if .Triggers}}<h2>Triggers</h2>{{range .Triggers}}<h3>{{.Name}} <span class="muted">{{.Timing}} {{.Event}}</span></h3><pre>{{.Body}}</pre>{{end}}{{end}}{{end}}
+
+{{define "view"}}<h1>{{.Name}}</h1>
+{{if .Columns}}<h2>Columns</h2><table><tr><th>Column</th><th>Type</th><th>Nullable</th></tr>
+{{range .Columns}}<tr><td><code>{{.Name}}</code></td><td><code>{{.DataType}}</code></td><td>{{if .IsNullable}}yes{{else}}no{{end}}</td></tr>
+{{end}}</table>{{end}}
+<h2>Definition</h2><pre>{{.Definition}}</pre>{{end}}
+
+{{define "routine"}}<h1>{{.Name}}</h1>
+{{range .Overloads}}<section><h2>{{$.Kind}} {{$.Name}}({{.Signature}}){{if .ReturnType}} → {{.ReturnType}}{{end}}</h2>
+{{if .Parameters}}<table><tr><th>Parameter</th><th>Type</th><th>Direction</th></tr>
+{{range .Parameters}}<tr><td>{{.Name}}</td><td><code>{{.DataType}}</code></td><td>{{.Direction}}</td></tr>
+{{end}}</table>{{end}}
+<pre>{{.Body}}</pre></section>{{end}}{{end}}
+`))
+
+const siteCSS = `body { font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #1f2328; }
+header { background: #24292f; color: #fff; padding: 10px 24px; font-size: 16px; }
+header a { color: #fff; font-weight: 600; text-decoration: none; }
+header .muted { color: #aab; }
+main { padding: 8px 24px 32px; max-width: 1200px; }
+footer { padding: 12px 24px; color: #656d76; font-size: 12px; border-top: 1px solid #d0d7de; }
+a { color: #0969da; }
+h1 { font-size: 24px; margin: 16px 0 8px; }
+h2 { font-size: 18px; margin: 24px 0 8px; border-bottom: 1px solid #d0d7de; padding-bottom: 4px; }
+h3 { font-size: 15px; margin: 16px 0 4px; }
+table { border-collapse: collapse; margin: 8px 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 12px; }
+pre { background: #f6f8fa; padding: 12px; overflow-x: auto; border-radius: 6px; }
+.muted { color: #656d76; font-weight: normal; }
+.comment { color: #57606a; font-style: italic; }
+.stats { color: #57606a; }
+#search { width: 100%; max-width: 600px; font-size: 16px; padding: 8px 10px; margin: 16px 0 4px; border: 1px solid #d0d7de; border-radius: 6px; }
+ul.entries { list-style: none; padding: 0; columns: 3 280px; }
+ul.entries li { break-inside: avoid; padding: 2px 0; }
+.erd-wrap { overflow-x: auto; margin: 8px 0; }
+svg.erd { font-size: 12px; }
+svg.erd rect.table { fill: #fff; stroke: #0969da; }
+svg.erd rect.head { fill: #0969da; }
+svg.erd text.title { fill: #fff; font-weight: 600; }
+svg.erd text.type { fill: #656d76; }
+svg.erd rect.neighbor { fill: #f6f8fa; stroke: #8c959f; }
+svg.erd a:hover rect.neighbor { fill: #ddf4ff; stroke: #0969da; }
+svg.erd path.edge { fill: none; stroke: #8c959f; }
+svg.erd path.edge:hover { stroke: #0969da; stroke-width: 2; }
+`
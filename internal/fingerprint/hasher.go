@@ -1,80 +1,80 @@
 package fingerprint
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
-	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/nechja/schemalyzer/pkg/models"
 )
 
 type Hasher struct {
-	includeComments bool
-	verbose         bool
+	options Options
+	verbose bool
 }
 
 func NewHasher() *Hasher {
+	return NewHasherWithOptions(DefaultOptions())
+}
+
+// NewHasherWithOptions returns a hasher covering what options select
+func NewHasherWithOptions(options Options) *Hasher {
 	return &Hasher{
-		includeComments: false,
-		verbose:         false,
+		options: options,
+		verbose: false,
 	}
 }
 
+// Options returns the options the hasher was created with
+func (h *Hasher) Options() Options {
+	return h.options
+}
+
 func (h *Hasher) WithVerbose(verbose bool) *Hasher {
 	h.verbose = verbose
 	return h
 }
 
+// GenerateFingerprint hashes a schema. The digest is the root of the
+// schema's manifest, so a fingerprint and a manifest of the same schema
+// always agree.
 func (h *Hasher) GenerateFingerprint(schema *models.Schema) (string, error) {
-	normalized := h.normalizeSchema(schema)
+	_, root, err := h.schemaTree(schema)
+	return root, err
+}
 
-	data, err := json.Marshal(normalized)
+// Fingerprint hashes a schema into a self-describing fingerprint that
+// records the hasher's options alongside the digest
+func (h *Hasher) Fingerprint(schema *models.Schema) (Fingerprint, error) {
+	digest, err := h.GenerateFingerprint(schema)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal schema: %w", err)
+		return Fingerprint{}, err
 	}
-
-	hash := sha256.Sum256(data)
-	return hex.EncodeToString(hash[:]), nil
+	return h.fingerprint(digest), nil
 }
 
-func (h *Hasher) normalizeSchema(schema *models.Schema) map[string]interface{} {
-	result := make(map[string]interface{})
-
-	result["tables"] = h.normalizeTables(schema.Tables)
-	result["views"] = h.normalizeViews(schema.Views)
-	result["indexes"] = h.normalizeIndexes(schema.Indexes)
-	result["sequences"] = h.normalizeSequences(schema.Sequences)
-	result["procedures"] = h.normalizeProcedures(schema.Procedures)
-	result["functions"] = h.normalizeFunctions(schema.Functions)
-	result["triggers"] = h.normalizeTriggers(schema.Triggers)
-
-	return result
+// DatabaseFingerprint is Fingerprint for a multi-schema snapshot
+func (h *Hasher) DatabaseFingerprint(db *models.Database) (Fingerprint, error) {
+	digest, err := h.GenerateDatabaseFingerprint(db)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	return h.fingerprint(digest), nil
 }
 
-func (h *Hasher) normalizeTables(tables []models.Table) []map[string]interface{} {
-	sort.Slice(tables, func(i, j int) bool {
-		return tables[i].Name < tables[j].Name
-	})
-
-	var result []map[string]interface{}
-	for _, table := range tables {
-		normalized := map[string]interface{}{
-			"name":        table.Name,
-			"columns":     h.normalizeColumns(table.Columns),
-			"constraints": h.normalizeConstraints(table.Constraints),
-			"indexes":     h.normalizeTableIndexes(table.Indexes),
-		}
-
-		if h.includeComments && table.Comment != "" {
-			normalized["comment"] = table.Comment
-		}
-
-		result = append(result, normalized)
+func (h *Hasher) fingerprint(digest string) Fingerprint {
+	return Fingerprint{
+		Version:   FormatVersion,
+		Algorithm: Algorithm,
+		Options:   h.options,
+		Digest:    digest,
 	}
+}
 
-	return result
+// GenerateDatabaseFingerprint hashes a multi-schema snapshot. The digest
+// is the root of the database manifest.
+func (h *Hasher) GenerateDatabaseFingerprint(db *models.Database) (string, error) {
+	_, root, err := h.databaseTree(db)
+	return root, err
 }
 
 func (h *Hasher) normalizeColumns(columns []models.Column) []map[string]interface{} {
@@ -88,11 +88,14 @@ func (h *Hasher) normalizeColumns(columns []models.Column) []map[string]interfac
 			"name":        col.Name,
 			"type":        col.DataType,
 			"nullable":    col.IsNullable,
-			"position":    col.Position,
 			"primary_key": col.IsPrimaryKey,
 			"unique":      col.IsUnique,
 		}
 
+		if !h.options.IgnoreColumnOrder {
+			normalized["position"] = col.Position
+		}
+
 		if col.DefaultValue != nil {
 			normalized["default"] = *col.DefaultValue
 		}
@@ -101,7 +104,7 @@ func (h *Hasher) normalizeColumns(columns []models.Column) []map[string]interfac
 			normalized["auto_increment"] = true
 		}
 
-		if h.includeComments && col.Comment != "" {
+		if h.options.IncludeComments && col.Comment != "" {
 			normalized["comment"] = col.Comment
 		}
 
@@ -130,6 +133,10 @@ func (h *Hasher) normalizeConstraints(constraints []models.Constraint) []map[str
 			normalized["columns"] = cols
 		}
 
+		if c.ReferencedSchema != "" {
+			normalized["ref_schema"] = c.ReferencedSchema
+		}
+
 		if c.ReferencedTable != "" {
 			normalized["ref_table"] = c.ReferencedTable
 		}
@@ -152,6 +159,48 @@ func (h *Hasher) normalizeConstraints(constraints []models.Constraint) []map[str
 			normalized["check_expr"] = c.CheckExpression
 		}
 
+		if c.ExclusionDefinition != "" {
+			normalized["exclusion"] = c.ExclusionDefinition
+		}
+
+		if c.IsDeferrable {
+			normalized["deferrable"] = true
+		}
+		if c.IsInitiallyDeferred {
+			normalized["initially_deferred"] = true
+		}
+		if c.IsDisabled {
+			normalized["disabled"] = true
+		}
+		if c.IsNotValidated {
+			normalized["not_validated"] = true
+		}
+
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+func (h *Hasher) normalizePolicies(policies []models.Policy) []map[string]interface{} {
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	var result []map[string]interface{}
+	for _, p := range policies {
+		roles := make([]string, len(p.Roles))
+		copy(roles, p.Roles)
+		sort.Strings(roles)
+
+		normalized := map[string]interface{}{
+			"name":        p.Name,
+			"command":     p.Command,
+			"roles":       roles,
+			"restrictive": p.IsRestrictive,
+			"using":       p.Using,
+			"with_check":  p.WithCheck,
+		}
 		result = append(result, normalized)
 	}
 
@@ -223,9 +272,9 @@ func (h *Hasher) normalizeViews(views []models.View) []map[string]interface{} {
 	var result []map[string]interface{}
 	for _, view := range views {
 		normalized := map[string]interface{}{
-			"name":       view.Name,
-			"definition": view.Definition,
+			"name": view.Name,
 		}
+		h.setBody(normalized, "definition", view.Definition)
 		result = append(result, normalized)
 	}
 
@@ -253,9 +302,25 @@ func (h *Hasher) normalizeSequences(sequences []models.Sequence) []map[string]in
 	return result
 }
 
+// identityLess orders objects by name, breaking ties between same-named
+// objects (overloads, triggers on different tables) by table and signature
+// so the hash does not depend on catalog row order. Comparing names first
+// keeps the order, and so the hash, of schemas without such ties unchanged.
+func identityLess(a, b models.ObjectIdentity) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	if a.Table != b.Table {
+		return a.Table < b.Table
+	}
+	return a.Signature < b.Signature
+}
+
 func (h *Hasher) normalizeProcedures(procedures []models.Procedure) []map[string]interface{} {
 	sort.Slice(procedures, func(i, j int) bool {
-		return procedures[i].Name < procedures[j].Name
+		return identityLess(
+			models.ObjectIdentity{Name: procedures[i].Name, Signature: models.RoutineSignature(procedures[i].Parameters)},
+			models.ObjectIdentity{Name: procedures[j].Name, Signature: models.RoutineSignature(procedures[j].Parameters)})
 	})
 
 	var result []map[string]interface{}
@@ -263,8 +328,8 @@ func (h *Hasher) normalizeProcedures(procedures []models.Procedure) []map[string
 		normalized := map[string]interface{}{
 			"name":       proc.Name,
 			"parameters": h.normalizeParameters(proc.Parameters),
-			"body":       proc.Body,
 		}
+		h.setBody(normalized, "body", proc.Body)
 		result = append(result, normalized)
 	}
 
@@ -273,7 +338,9 @@ func (h *Hasher) normalizeProcedures(procedures []models.Procedure) []map[string
 
 func (h *Hasher) normalizeFunctions(functions []models.Function) []map[string]interface{} {
 	sort.Slice(functions, func(i, j int) bool {
-		return functions[i].Name < functions[j].Name
+		return identityLess(
+			models.ObjectIdentity{Name: functions[i].Name, Signature: models.RoutineSignature(functions[i].Parameters)},
+			models.ObjectIdentity{Name: functions[j].Name, Signature: models.RoutineSignature(functions[j].Parameters)})
 	})
 
 	var result []map[string]interface{}
@@ -282,8 +349,8 @@ func (h *Hasher) normalizeFunctions(functions []models.Function) []map[string]in
 			"name":        fn.Name,
 			"parameters":  h.normalizeParameters(fn.Parameters),
 			"return_type": fn.ReturnType,
-			"body":        fn.Body,
 		}
+		h.setBody(normalized, "body", fn.Body)
 		result = append(result, normalized)
 	}
 
@@ -292,7 +359,9 @@ func (h *Hasher) normalizeFunctions(functions []models.Function) []map[string]in
 
 func (h *Hasher) normalizeTriggers(triggers []models.Trigger) []map[string]interface{} {
 	sort.Slice(triggers, func(i, j int) bool {
-		return triggers[i].Name < triggers[j].Name
+		return identityLess(
+			models.ObjectIdentity{Table: triggers[i].TableName, Name: triggers[i].Name},
+			models.ObjectIdentity{Table: triggers[j].TableName, Name: triggers[j].Name})
 	})
 
 	var result []map[string]interface{}
@@ -302,8 +371,69 @@ func (h *Hasher) normalizeTriggers(triggers []models.Trigger) []map[string]inter
 			"table":  trig.TableName,
 			"event":  trig.Event,
 			"timing": trig.Timing,
-			"body":   trig.Body,
 		}
+		h.setBody(normalized, "body", trig.Body)
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+func (h *Hasher) normalizeSynonyms(synonyms []models.Synonym) []map[string]interface{} {
+	sort.Slice(synonyms, func(i, j int) bool {
+		return synonyms[i].Name < synonyms[j].Name
+	})
+
+	var result []map[string]interface{}
+	for _, syn := range synonyms {
+		normalized := map[string]interface{}{
+			"name":          syn.Name,
+			"target_owner":  syn.TargetOwner,
+			"target_object": syn.TargetObject,
+		}
+
+		if syn.DBLink != "" {
+			normalized["db_link"] = syn.DBLink
+		}
+
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+func (h *Hasher) normalizeExtensions(extensions []models.Extension) []map[string]interface{} {
+	sort.Slice(extensions, func(i, j int) bool {
+		return extensions[i].Name < extensions[j].Name
+	})
+
+	var result []map[string]interface{}
+	for _, ext := range extensions {
+		normalized := map[string]interface{}{
+			"name":    ext.Name,
+			"version": ext.Version,
+			"schema":  ext.Schema,
+		}
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+func (h *Hasher) normalizeEvents(events []models.Event) []map[string]interface{} {
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+
+	var result []map[string]interface{}
+	for _, ev := range events {
+		normalized := map[string]interface{}{
+			"name":          ev.Name,
+			"schedule":      ev.Schedule,
+			"status":        ev.Status,
+			"on_completion": ev.OnCompletion,
+		}
+		h.setBody(normalized, "body", ev.Body)
 		result = append(result, normalized)
 	}
 
@@ -311,7 +441,9 @@ func (h *Hasher) normalizeTriggers(triggers []models.Trigger) []map[string]inter
 }
 
 func (h *Hasher) normalizeParameters(params []models.Parameter) []map[string]interface{} {
-	// Sort parameters by name for consistent hashing
+	// Sort a copy of the parameters by name for consistent hashing; the
+	// declared order is what identifies a routine's overload
+	params = append([]models.Parameter(nil), params...)
 	sort.Slice(params, func(i, j int) bool {
 		return params[i].Name < params[j].Name
 	})
@@ -327,3 +459,15 @@ func (h *Hasher) normalizeParameters(params []models.Parameter) []map[string]int
 	}
 	return result
 }
+
+// setBody adds a routine body or view definition under key as the body
+// mode dictates
+func (h *Hasher) setBody(normalized map[string]interface{}, key, body string) {
+	switch h.options.bodies() {
+	case BodiesExcluded:
+	case BodiesNormalized:
+		normalized[key] = strings.Join(strings.Fields(body), " ")
+	default:
+		normalized[key] = body
+	}
+}
@@ -345,4 +345,106 @@ func TestFingerprintParameterOrderIndependence(t *testing.T) {
 	if hash1 != hash2 {
 		t.Error("Functions with different parameter order should produce same fingerprint")
 	}
-}
\ No newline at end of file
+}
+func TestFingerprintExtensionVersion(t *testing.T) {
+	hasher := NewHasher()
+
+	schema1 := &models.Schema{
+		Name: "test",
+		Extensions: []models.Extension{
+			{Name: "postgis", Version: "3.3.2", Schema: "public"},
+		},
+	}
+
+	schema2 := &models.Schema{
+		Name: "test",
+		Extensions: []models.Extension{
+			{Name: "postgis", Version: "3.4.0", Schema: "public"},
+		},
+	}
+
+	hash1, err := hasher.GenerateFingerprint(schema1)
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for schema1: %v", err)
+	}
+
+	hash2, err := hasher.GenerateFingerprint(schema2)
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for schema2: %v", err)
+	}
+
+	if hash1 == hash2 {
+		t.Error("Different extension versions should produce different fingerprints")
+	}
+
+}
+
+func TestFingerprintSameNamedObjectOrderIndependence(t *testing.T) {
+	hasher := NewHasher()
+
+	overloads := []models.Function{
+		{Name: "area", Parameters: []models.Parameter{{Name: "r", DataType: "numeric", Direction: models.In}}, ReturnType: "numeric"},
+		{Name: "area", Parameters: []models.Parameter{{Name: "w", DataType: "integer", Direction: models.In}}, ReturnType: "integer"},
+	}
+	triggers := []models.Trigger{
+		{Name: "touch", TableName: "users", Event: "UPDATE", Timing: "BEFORE"},
+		{Name: "touch", TableName: "orders", Event: "UPDATE", Timing: "AFTER"},
+	}
+
+	schema1 := &models.Schema{Name: "test", Functions: overloads, Triggers: triggers}
+	schema2 := &models.Schema{
+		Name:      "test",
+		Functions: []models.Function{overloads[1], overloads[0]},
+		Triggers:  []models.Trigger{triggers[1], triggers[0]},
+	}
+
+	hash1, err := hasher.GenerateFingerprint(schema1)
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for schema1: %v", err)
+	}
+
+	hash2, err := hasher.GenerateFingerprint(schema2)
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for schema2: %v", err)
+	}
+
+	if hash1 != hash2 {
+		t.Error("Overloads and same-named triggers in a different order should produce the same fingerprint")
+	}
+}
+
+func TestDatabaseFingerprintSchemaOrderIndependence(t *testing.T) {
+	hasher := NewHasher()
+
+	billing := models.Schema{Name: "billing", Tables: []models.Table{
+		{Name: "invoices", Columns: []models.Column{{Name: "id", DataType: "integer"}}},
+	}}
+	crm := models.Schema{Name: "crm", Tables: []models.Table{
+		{Name: "customers", Columns: []models.Column{{Name: "id", DataType: "integer"}}},
+	}}
+
+	hash1, err := hasher.GenerateDatabaseFingerprint(&models.Database{Schemas: []models.Schema{billing, crm}})
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for db1: %v", err)
+	}
+
+	hash2, err := hasher.GenerateDatabaseFingerprint(&models.Database{Schemas: []models.Schema{crm, billing}})
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for db2: %v", err)
+	}
+
+	if hash1 != hash2 {
+		t.Error("Databases with different schema ordering should produce same fingerprint")
+	}
+
+	renamed := crm
+	renamed.Name = "crm_v2"
+	hash3, err := hasher.GenerateDatabaseFingerprint(&models.Database{Schemas: []models.Schema{billing, renamed}})
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint for db3: %v", err)
+	}
+
+	if hash1 == hash3 {
+		t.Error("Databases with different schema names should produce different fingerprints")
+	}
+}
@@ -0,0 +1,396 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// ManifestVersion is bumped whenever the way entries are hashed changes, so
+// manifests built by different versions are never compared
+const ManifestVersion = 1
+
+// ManifestScope says whether a manifest covers one schema or a whole database
+type ManifestScope string
+
+const (
+	ScopeSchema   ManifestScope = "schema"
+	ScopeDatabase ManifestScope = "database"
+)
+
+// Manifest records a hash per schema object, arranged as a Merkle tree:
+// tables (and, for databases, schemas) hash their own attributes together
+// with the hashes of their children, and Root hashes the top-level entries.
+// Two manifests with equal roots describe identical schemas; otherwise
+// walking both trees finds exactly which objects changed.
+type Manifest struct {
+	Version   int           `json:"version"`
+	Algorithm string        `json:"algorithm"`
+	Scope     ManifestScope `json:"scope"`
+	Name      string        `json:"name,omitempty"`
+	// Fingerprint is Root in the form printed by the fingerprint command,
+	// so a manifest can be checked against a stored fingerprint. Its
+	// options apply to every entry.
+	Fingerprint string          `json:"fingerprint"`
+	Root        string          `json:"root"`
+	Objects     []ManifestEntry `json:"objects"`
+}
+
+// ManifestEntry is one object in a manifest. Self hashes the object's own
+// attributes and is only set on entries with children.
+type ManifestEntry struct {
+	Type      string          `json:"type"`
+	Table     string          `json:"table,omitempty"`
+	Name      string          `json:"name"`
+	Signature string          `json:"signature,omitempty"`
+	Hash      string          `json:"hash"`
+	Self      string          `json:"self,omitempty"`
+	Children  []ManifestEntry `json:"children,omitempty"`
+}
+
+func (e ManifestEntry) key() [4]string {
+	return [4]string{e.Type, e.Table, e.Name, e.Signature}
+}
+
+// GenerateManifest builds the per-object manifest of a schema
+func (h *Hasher) GenerateManifest(schema *models.Schema) (*Manifest, error) {
+	objects, root, err := h.schemaTree(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manifest{
+		Version:     ManifestVersion,
+		Algorithm:   "SHA256",
+		Scope:       ScopeSchema,
+		Name:        schema.Name,
+		Fingerprint: h.fingerprint(root).String(),
+		Root:        root,
+		Objects:     objects,
+	}, nil
+}
+
+// GenerateDatabaseManifest builds the manifest of a multi-schema snapshot,
+// with one Schema entry per schema holding that schema's objects
+func (h *Hasher) GenerateDatabaseManifest(db *models.Database) (*Manifest, error) {
+	objects, root, err := h.databaseTree(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manifest{
+		Version:     ManifestVersion,
+		Algorithm:   "SHA256",
+		Scope:       ScopeDatabase,
+		Fingerprint: h.fingerprint(root).String(),
+		Root:        root,
+		Objects:     objects,
+	}, nil
+}
+
+// schemaTree hashes a schema's objects and rolls them up into its root
+func (h *Hasher) schemaTree(schema *models.Schema) ([]ManifestEntry, string, error) {
+	b := &manifestBuilder{h: h}
+	objects := b.schemaEntries(schema)
+	root := b.rollup(objects)
+	if b.err != nil {
+		return nil, "", b.err
+	}
+	return objects, root, nil
+}
+
+// databaseTree is schemaTree for a multi-schema snapshot
+func (h *Hasher) databaseTree(db *models.Database) ([]ManifestEntry, string, error) {
+	b := &manifestBuilder{h: h}
+
+	var objects []ManifestEntry
+	for i := range db.Schemas {
+		schema := &db.Schemas[i]
+		objects = append(objects, b.node(ManifestEntry{Type: "Schema", Name: schema.Name},
+			map[string]interface{}{"name": schema.Name}, b.schemaEntries(schema)))
+	}
+	for _, ext := range db.Extensions {
+		objects = append(objects, b.leaf(ManifestEntry{Type: "Extension", Name: ext.Name},
+			h.normalizeExtensions([]models.Extension{ext})[0]))
+	}
+	sortEntries(objects)
+
+	root := b.rollup(objects)
+	if b.err != nil {
+		return nil, "", b.err
+	}
+	return objects, root, nil
+}
+
+// DiffManifests lists the objects that differ between two manifests, in the
+// same terms as a full comparison: Removed objects exist only in source,
+// Added only in target. Source and Target of each difference hold the
+// differing hashes.
+func DiffManifests(source, target *Manifest) ([]models.Difference, error) {
+	if source.Version != target.Version {
+		return nil, fmt.Errorf("manifest versions differ: %d and %d", source.Version, target.Version)
+	}
+	if source.Scope != target.Scope {
+		return nil, fmt.Errorf("cannot compare a %s manifest with a %s manifest", source.Scope, target.Scope)
+	}
+
+	sourceFingerprint, err := ParseFingerprint(source.Fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("source manifest: %w", err)
+	}
+	targetFingerprint, err := ParseFingerprint(target.Fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("target manifest: %w", err)
+	}
+	if sourceFingerprint.Digest != source.Root {
+		return nil, fmt.Errorf("source manifest: fingerprint does not match the root")
+	}
+	if targetFingerprint.Digest != target.Root {
+		return nil, fmt.Errorf("target manifest: fingerprint does not match the root")
+	}
+	if _, err := Compare(sourceFingerprint, targetFingerprint); err != nil {
+		return nil, err
+	}
+	if source.Root == target.Root {
+		return nil, nil
+	}
+	return diffEntries(models.ObjectIdentity{}, source.Objects, target.Objects), nil
+}
+
+func diffEntries(parent models.ObjectIdentity, source, target []ManifestEntry) []models.Difference {
+	targetByKey := make(map[[4]string]ManifestEntry, len(target))
+	for _, e := range target {
+		targetByKey[e.key()] = e
+	}
+	sourceKeys := make(map[[4]string]bool, len(source))
+
+	var differences []models.Difference
+	for _, s := range source {
+		sourceKeys[s.key()] = true
+		id := entryIdentity(parent, s)
+
+		t, exists := targetByKey[s.key()]
+		if !exists {
+			differences = append(differences, models.Difference{
+				Type:        models.Removed,
+				ObjectType:  s.Type,
+				ObjectName:  id.String(),
+				Identity:    id,
+				Source:      s.Hash,
+				Description: fmt.Sprintf("%s exists in source but not in target", s.Type),
+			})
+			continue
+		}
+		if s.Hash == t.Hash {
+			continue
+		}
+
+		if (len(s.Children) == 0 && len(t.Children) == 0) || s.Self != t.Self {
+			differences = append(differences, models.Difference{
+				Type:        models.Modified,
+				ObjectType:  s.Type,
+				ObjectName:  id.String(),
+				Identity:    id,
+				Source:      s.Hash,
+				Target:      t.Hash,
+				Description: fmt.Sprintf("%s definition differs", s.Type),
+			})
+		}
+		differences = append(differences, diffEntries(childScope(parent, s), s.Children, t.Children)...)
+	}
+
+	for _, t := range target {
+		if sourceKeys[t.key()] {
+			continue
+		}
+		id := entryIdentity(parent, t)
+		differences = append(differences, models.Difference{
+			Type:        models.Added,
+			ObjectType:  t.Type,
+			ObjectName:  id.String(),
+			Identity:    id,
+			Target:      t.Hash,
+			Description: fmt.Sprintf("%s exists in target but not in source", t.Type),
+		})
+	}
+
+	return differences
+}
+
+// entryIdentity names an entry within its parent's identity
+func entryIdentity(parent models.ObjectIdentity, e ManifestEntry) models.ObjectIdentity {
+	id := models.ObjectIdentity{
+		Schema:    parent.Schema,
+		Table:     e.Table,
+		Name:      e.Name,
+		Signature: e.Signature,
+	}
+	if id.Table == "" {
+		id.Table = parent.Table
+	}
+	return id
+}
+
+// childScope is the identity the children of e are named under: a Schema
+// entry supplies their schema and a Table entry their table
+func childScope(parent models.ObjectIdentity, e ManifestEntry) models.ObjectIdentity {
+	switch e.Type {
+	case "Schema":
+		return models.ObjectIdentity{Schema: e.Name}
+	case "Table":
+		return models.ObjectIdentity{Schema: parent.Schema, Table: e.Name}
+	default:
+		return parent
+	}
+}
+
+// manifestBuilder hashes entries, keeping the first marshalling error so
+// the tree can be built without checking every call
+type manifestBuilder struct {
+	h   *Hasher
+	err error
+}
+
+func (b *manifestBuilder) schemaEntries(schema *models.Schema) []ManifestEntry {
+	h := b.h
+	var entries []ManifestEntry
+
+	for _, table := range schema.Tables {
+		self := map[string]interface{}{"name": table.Name}
+		if h.options.IncludeComments && table.Comment != "" {
+			self["comment"] = table.Comment
+		}
+		if h.options.IncludeStatistics && table.RowCount != nil {
+			self["row_count"] = *table.RowCount
+		}
+		if table.RowSecurityEnabled {
+			self["row_security"] = true
+		}
+		if table.RowSecurityForced {
+			self["force_row_security"] = true
+		}
+
+		var children []ManifestEntry
+		for _, col := range table.Columns {
+			children = append(children, b.leaf(ManifestEntry{Type: "Column", Name: col.Name},
+				h.normalizeColumns([]models.Column{col})[0]))
+		}
+		for _, c := range table.Constraints {
+			children = append(children, b.leaf(ManifestEntry{Type: "Constraint", Name: c.Name},
+				h.normalizeConstraints([]models.Constraint{c})[0]))
+		}
+		for _, idx := range table.Indexes {
+			children = append(children, b.leaf(ManifestEntry{Type: "Index", Name: idx.Name},
+				h.normalizeTableIndexes([]models.Index{idx})[0]))
+		}
+		for _, p := range table.Policies {
+			children = append(children, b.leaf(ManifestEntry{Type: "Policy", Name: p.Name},
+				h.normalizePolicies([]models.Policy{p})[0]))
+		}
+		sortEntries(children)
+
+		entries = append(entries, b.node(ManifestEntry{Type: "Table", Name: table.Name}, self, children))
+	}
+
+	for _, view := range schema.Views {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "View", Name: view.Name},
+			h.normalizeViews([]models.View{view})[0]))
+	}
+	for _, idx := range schema.Indexes {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Index", Table: idx.TableName, Name: idx.Name},
+			h.normalizeIndexes([]models.Index{idx})[0]))
+	}
+	for _, seq := range schema.Sequences {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Sequence", Name: seq.Name},
+			h.normalizeSequences([]models.Sequence{seq})[0]))
+	}
+	for _, proc := range schema.Procedures {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Procedure", Name: proc.Name, Signature: models.RoutineSignature(proc.Parameters)},
+			h.normalizeProcedures([]models.Procedure{proc})[0]))
+	}
+	for _, fn := range schema.Functions {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Function", Name: fn.Name, Signature: models.RoutineSignature(fn.Parameters)},
+			h.normalizeFunctions([]models.Function{fn})[0]))
+	}
+	for _, trig := range schema.Triggers {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Trigger", Table: trig.TableName, Name: trig.Name},
+			h.normalizeTriggers([]models.Trigger{trig})[0]))
+	}
+	for _, syn := range schema.Synonyms {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Synonym", Name: syn.Name},
+			h.normalizeSynonyms([]models.Synonym{syn})[0]))
+	}
+	// PostgreSQL readers attach an extension only to the schema it is
+	// installed in, so the plpgsql extension in pg_catalog leaves user
+	// schemas' fingerprints alone
+	for _, ext := range schema.Extensions {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Extension", Name: ext.Name},
+			h.normalizeExtensions([]models.Extension{ext})[0]))
+	}
+	for _, ev := range schema.Events {
+		entries = append(entries, b.leaf(ManifestEntry{Type: "Event", Name: ev.Name},
+			h.normalizeEvents([]models.Event{ev})[0]))
+	}
+
+	sortEntries(entries)
+	return entries
+}
+
+// leaf hashes an object with no children
+func (b *manifestBuilder) leaf(entry ManifestEntry, normalized map[string]interface{}) ManifestEntry {
+	entry.Hash = b.hash(normalized)
+	return entry
+}
+
+// node hashes an object's own attributes together with its children's hashes
+func (b *manifestBuilder) node(entry ManifestEntry, self map[string]interface{}, children []ManifestEntry) ManifestEntry {
+	entry.Self = b.hash(self)
+	entry.Children = children
+	entry.Hash = b.hash(map[string]interface{}{
+		"self":     entry.Self,
+		"children": entryHashes(children),
+	})
+	return entry
+}
+
+// rollup hashes the top-level entries into the manifest root
+func (b *manifestBuilder) rollup(entries []ManifestEntry) string {
+	return b.hash(entryHashes(entries))
+}
+
+func (b *manifestBuilder) hash(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("failed to marshal manifest entry: %w", err)
+		}
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// entryHashes lists each entry's key and hash, which is what a parent hashes
+func entryHashes(entries []ManifestEntry) [][5]string {
+	hashes := make([][5]string, 0, len(entries))
+	for _, e := range entries {
+		hashes = append(hashes, [5]string{e.Type, e.Table, e.Name, e.Signature, e.Hash})
+	}
+	return hashes
+}
+
+func sortEntries(entries []ManifestEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].key(), entries[j].key()
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
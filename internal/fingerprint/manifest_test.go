@@ -0,0 +1,175 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+func manifestSchema() *models.Schema {
+	return &models.Schema{
+		Name: "app",
+		Tables: []models.Table{
+			{
+				Name: "users",
+				Columns: []models.Column{
+					{Name: "id", DataType: "integer", IsPrimaryKey: true},
+					{Name: "email", DataType: "varchar(255)"},
+				},
+				Constraints: []models.Constraint{
+					{Name: "pk_users", Type: models.PrimaryKey, Columns: []string{"id"}},
+				},
+			},
+			{
+				Name:    "orders",
+				Columns: []models.Column{{Name: "id", DataType: "integer"}},
+			},
+		},
+		Functions: []models.Function{
+			{Name: "total", Parameters: []models.Parameter{{Name: "a", DataType: "integer"}}, ReturnType: "integer"},
+			{Name: "total", Parameters: []models.Parameter{{Name: "a", DataType: "numeric"}}, ReturnType: "numeric"},
+		},
+	}
+}
+
+func diffSchemas(t *testing.T, source, target *models.Schema) []models.Difference {
+	t.Helper()
+	hasher := NewHasher()
+
+	sourceManifest, err := hasher.GenerateManifest(source)
+	if err != nil {
+		t.Fatalf("Failed to generate source manifest: %v", err)
+	}
+	targetManifest, err := hasher.GenerateManifest(target)
+	if err != nil {
+		t.Fatalf("Failed to generate target manifest: %v", err)
+	}
+
+	diffs, err := DiffManifests(sourceManifest, targetManifest)
+	if err != nil {
+		t.Fatalf("Failed to diff manifests: %v", err)
+	}
+	return diffs
+}
+
+func TestFingerprintIsManifestRoot(t *testing.T) {
+	hasher := NewHasher()
+
+	manifest, err := hasher.GenerateManifest(manifestSchema())
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
+	}
+
+	fp, err := hasher.Fingerprint(manifestSchema())
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint: %v", err)
+	}
+
+	if fp.Digest != manifest.Root || manifest.Fingerprint != fp.String() {
+		t.Error("The fingerprint of a schema should be its manifest root")
+	}
+	if len(manifest.Objects) != 4 {
+		t.Errorf("Expected 2 tables and 2 function overloads, got %d entries", len(manifest.Objects))
+	}
+
+	db := &models.Database{Schemas: []models.Schema{*manifestSchema()}}
+	dbManifest, err := hasher.GenerateDatabaseManifest(db)
+	if err != nil {
+		t.Fatalf("Failed to generate database manifest: %v", err)
+	}
+	dbFingerprint, err := hasher.DatabaseFingerprint(db)
+	if err != nil {
+		t.Fatalf("Failed to generate database fingerprint: %v", err)
+	}
+
+	if dbFingerprint.Digest != dbManifest.Root || dbManifest.Fingerprint != dbFingerprint.String() {
+		t.Error("The fingerprint of a database should be its manifest root")
+	}
+}
+
+func TestManifestRootOrderIndependence(t *testing.T) {
+	reordered := manifestSchema()
+	reordered.Tables[0], reordered.Tables[1] = reordered.Tables[1], reordered.Tables[0]
+	reordered.Tables[1].Columns[0], reordered.Tables[1].Columns[1] = reordered.Tables[1].Columns[1], reordered.Tables[1].Columns[0]
+
+	hasher := NewHasher()
+	m1, err := hasher.GenerateManifest(manifestSchema())
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
+	}
+	m2, err := hasher.GenerateManifest(reordered)
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
+	}
+
+	if m1.Root != m2.Root {
+		t.Error("Object ordering should not change the manifest root")
+	}
+}
+
+func TestDiffManifestsDrillsDownToChangedObjects(t *testing.T) {
+	target := manifestSchema()
+	target.Tables[0].Columns[1].DataType = "text"
+	target.Tables = append(target.Tables, models.Table{Name: "audit"})
+	target.Functions = target.Functions[:1]
+
+	diffs := diffSchemas(t, manifestSchema(), target)
+
+	expected := map[string]models.DifferenceType{
+		"Column users.email":      models.Modified,
+		"Table audit":             models.Added,
+		"Function total(numeric)": models.Removed,
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("Expected %d differences, got %d: %+v", len(expected), len(diffs), diffs)
+	}
+	for _, diff := range diffs {
+		key := diff.ObjectType + " " + diff.ObjectName
+		if expected[key] != diff.Type {
+			t.Errorf("Unexpected difference %s %s", diff.Type, key)
+		}
+	}
+}
+
+func TestDiffManifestsReportsTableAttributes(t *testing.T) {
+	target := manifestSchema()
+	target.Tables[0].RowSecurityEnabled = true
+
+	diffs := diffSchemas(t, manifestSchema(), target)
+
+	if len(diffs) != 1 || diffs[0].ObjectType != "Table" || diffs[0].ObjectName != "users" {
+		t.Errorf("Expected only table users to be modified, got %+v", diffs)
+	}
+}
+
+func TestDiffDatabaseManifestsQualifiesSchema(t *testing.T) {
+	hasher := NewHasher()
+
+	source, err := hasher.GenerateDatabaseManifest(&models.Database{Schemas: []models.Schema{*manifestSchema()}})
+	if err != nil {
+		t.Fatalf("Failed to generate source manifest: %v", err)
+	}
+
+	changed := manifestSchema()
+	changed.Tables[1].Columns[0].IsNullable = true
+	target, err := hasher.GenerateDatabaseManifest(&models.Database{Schemas: []models.Schema{*changed}})
+	if err != nil {
+		t.Fatalf("Failed to generate target manifest: %v", err)
+	}
+
+	diffs, err := DiffManifests(source, target)
+	if err != nil {
+		t.Fatalf("Failed to diff manifests: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].ObjectName != "app.orders.id" {
+		t.Errorf("Expected app.orders.id to be modified, got %+v", diffs)
+	}
+
+	schemaManifest, err := hasher.GenerateManifest(manifestSchema())
+	if err != nil {
+		t.Fatalf("Failed to generate manifest: %v", err)
+	}
+	if _, err := DiffManifests(source, schemaManifest); err == nil {
+		t.Error("Database and schema manifests should not be comparable")
+	}
+}
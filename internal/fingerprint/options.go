@@ -0,0 +1,213 @@
+package fingerprint
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatVersion is the version written into fingerprints by this package.
+// Version 1 fingerprints are the bare hex digests of earlier releases,
+// which hashed schemas differently and only compare with each other.
+const FormatVersion = 2
+
+// Algorithm is the digest used for fingerprints
+const Algorithm = "sha256"
+
+// BodyMode controls how routine bodies and view definitions are hashed
+type BodyMode string
+
+const (
+	// BodiesRaw hashes bodies exactly as the catalog returns them
+	BodiesRaw BodyMode = "raw"
+	// BodiesNormalized collapses runs of whitespace before hashing, so
+	// reformatting a routine doesn't change the fingerprint
+	BodiesNormalized BodyMode = "normalized"
+	// BodiesExcluded leaves bodies out of the hash entirely
+	BodiesExcluded BodyMode = "none"
+)
+
+// Options selects what a fingerprint covers. The zero value reproduces the
+// default settings of earlier releases, but their version 1 digests hash
+// schemas differently and are not comparable with current fingerprints.
+type Options struct {
+	// IncludeComments hashes table and column comments
+	IncludeComments bool
+	// IgnoreColumnOrder leaves column positions out, so reordered columns
+	// hash the same
+	IgnoreColumnOrder bool
+	// Bodies controls routine bodies and view definitions; empty means raw
+	Bodies BodyMode
+	// IncludeStatistics hashes table row counts when the schema has them
+	IncludeStatistics bool
+}
+
+// DefaultOptions returns the options used by NewHasher
+func DefaultOptions() Options {
+	return Options{Bodies: BodiesRaw}
+}
+
+// Validate reports an unknown body mode
+func (o Options) Validate() error {
+	switch o.Bodies {
+	case "", BodiesRaw, BodiesNormalized, BodiesExcluded:
+		return nil
+	default:
+		return fmt.Errorf("unknown body mode %q (want %s, %s or %s)", o.Bodies, BodiesRaw, BodiesNormalized, BodiesExcluded)
+	}
+}
+
+func (o Options) bodies() BodyMode {
+	if o.Bodies == "" {
+		return BodiesRaw
+	}
+	return o.Bodies
+}
+
+// String encodes the options as they appear in a fingerprint, e.g.
+// "comments=0,order=1,bodies=raw,stats=0". Every option is always written,
+// in a fixed order, so equal options encode identically.
+func (o Options) String() string {
+	return fmt.Sprintf("comments=%s,order=%s,bodies=%s,stats=%s",
+		flag(o.IncludeComments), flag(!o.IgnoreColumnOrder), o.bodies(), flag(o.IncludeStatistics))
+}
+
+func (o Options) equal(other Options) bool {
+	return o.String() == other.String()
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func parseOptions(s string) (Options, error) {
+	var o Options
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return Options{}, fmt.Errorf("malformed option %q", pair)
+		}
+		seen[key] = true
+
+		switch key {
+		case "bodies":
+			o.Bodies = BodyMode(value)
+			if err := o.Validate(); err != nil {
+				return Options{}, err
+			}
+			continue
+		case "comments", "order", "stats":
+		default:
+			return Options{}, fmt.Errorf("unknown option %q", key)
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return Options{}, fmt.Errorf("option %s: %w", key, err)
+		}
+		switch key {
+		case "comments":
+			o.IncludeComments = b
+		case "order":
+			o.IgnoreColumnOrder = !b
+		case "stats":
+			o.IncludeStatistics = b
+		}
+	}
+
+	for _, key := range []string{"comments", "order", "bodies", "stats"} {
+		if !seen[key] {
+			return Options{}, fmt.Errorf("missing option %q", key)
+		}
+	}
+	return o, nil
+}
+
+// Fingerprint is a digest together with the settings that produced it.
+// Its string form is "v2:sha256:<options>:<hex digest>".
+type Fingerprint struct {
+	Version   int
+	Algorithm string
+	Options   Options
+	Digest    string
+}
+
+// String renders the self-describing form. Version 1 fingerprints render
+// as the bare digest they were parsed from.
+func (f Fingerprint) String() string {
+	if f.Version < 2 {
+		return f.Digest
+	}
+	return fmt.Sprintf("v%d:%s:%s:%s", f.Version, f.Algorithm, f.Options, f.Digest)
+}
+
+// ParseFingerprint parses a fingerprint string. A bare 64 character hex
+// digest is accepted as a version 1 fingerprint with the default options;
+// Compare refuses to match it against a version 2 fingerprint.
+func ParseFingerprint(s string) (Fingerprint, error) {
+	s = strings.TrimSpace(s)
+	if isDigest(s) {
+		return Fingerprint{Version: 1, Algorithm: Algorithm, Options: DefaultOptions(), Digest: strings.ToLower(s)}, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 || !strings.HasPrefix(parts[0], "v") {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint %q: want v<version>:<algorithm>:<options>:<digest>", s)
+	}
+
+	version, err := strconv.Atoi(parts[0][1:])
+	if err != nil || version != FormatVersion {
+		return Fingerprint{}, fmt.Errorf("unsupported fingerprint version %q", parts[0])
+	}
+	if parts[1] != Algorithm {
+		return Fingerprint{}, fmt.Errorf("unsupported fingerprint algorithm %q", parts[1])
+	}
+	options, err := parseOptions(parts[2])
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint options: %w", err)
+	}
+	if !isDigest(parts[3]) {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint digest %q", parts[3])
+	}
+
+	return Fingerprint{Version: version, Algorithm: parts[1], Options: options, Digest: strings.ToLower(parts[3])}, nil
+}
+
+func isDigest(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// IncompatibleError is returned when two fingerprints were produced with
+// different options or format versions and so can't be compared
+type IncompatibleError struct {
+	Source        Options
+	Target        Options
+	SourceVersion int
+	TargetVersion int
+}
+
+func (e *IncompatibleError) Error() string {
+	if e.SourceVersion != e.TargetVersion {
+		return fmt.Sprintf("fingerprints were generated by releases that hash schemas differently: source v%d, target v%d; regenerate the stored fingerprint", e.SourceVersion, e.TargetVersion)
+	}
+	return fmt.Sprintf("fingerprints were generated with different settings: source %s, target %s", e.Source, e.Target)
+}
+
+// Compare reports whether two fingerprints describe the same schema. It
+// returns an *IncompatibleError instead of a false mismatch when they were
+// produced with different options or format versions.
+func Compare(source, target Fingerprint) (bool, error) {
+	if source.Version != target.Version || !source.Options.equal(target.Options) {
+		return false, &IncompatibleError{Source: source.Options, Target: target.Options, SourceVersion: source.Version, TargetVersion: target.Version}
+	}
+	return source.Digest == target.Digest, nil
+}
@@ -0,0 +1,173 @@
+package fingerprint
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+func optionsSchema() *models.Schema {
+	rows := int64(10)
+	return &models.Schema{
+		Name: "test",
+		Tables: []models.Table{
+			{
+				Name:     "users",
+				Comment:  "User table",
+				RowCount: &rows,
+				Columns: []models.Column{
+					{Name: "id", DataType: "integer", Position: 1},
+					{Name: "name", DataType: "text", Position: 2, Comment: "Display name"},
+				},
+			},
+		},
+		Views: []models.View{{Name: "active_users", Definition: "SELECT id\n  FROM users"}},
+		Functions: []models.Function{
+			{Name: "touch", ReturnType: "trigger", Body: "BEGIN\n  RETURN NEW;\nEND"},
+		},
+	}
+}
+
+func digest(t *testing.T, options Options, schema *models.Schema) Fingerprint {
+	t.Helper()
+	fp, err := NewHasherWithOptions(options).Fingerprint(schema)
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint: %v", err)
+	}
+	return fp
+}
+
+func TestFingerprintStringRoundTrip(t *testing.T) {
+	options := Options{IncludeComments: true, IgnoreColumnOrder: true, Bodies: BodiesNormalized, IncludeStatistics: true}
+	fp := digest(t, options, optionsSchema())
+
+	s := fp.String()
+	if want := "v2:sha256:comments=1,order=0,bodies=normalized,stats=1:" + fp.Digest; s != want {
+		t.Errorf("Expected %s, got %s", want, s)
+	}
+
+	parsed, err := ParseFingerprint(s)
+	if err != nil {
+		t.Fatalf("Failed to parse fingerprint: %v", err)
+	}
+	if parsed != fp {
+		t.Errorf("Expected %+v after round trip, got %+v", fp, parsed)
+	}
+}
+
+// baselineDigest is what the release before self-describing fingerprints
+// printed for postgresSchema, which had no extensions then
+const baselineDigest = "b8177dc1bc4fa69e2f1961e2e74cbf1d1ea28118d7e0bbf1581ff5d0087be28d"
+
+func postgresSchema() *models.Schema {
+	def := "nextval('accounts_id_seq'::regclass)"
+	return &models.Schema{
+		Name:         "public",
+		DatabaseType: models.PostgreSQL,
+		Tables: []models.Table{{
+			Name:   "accounts",
+			Schema: "public",
+			Columns: []models.Column{
+				{Name: "id", DataType: "integer", IsPrimaryKey: true, DefaultValue: &def, Position: 1},
+				{Name: "email", DataType: "citext", Position: 2},
+			},
+			Constraints: []models.Constraint{{Name: "accounts_pkey", Type: models.PrimaryKey, Columns: []string{"id"}}},
+		}},
+		Sequences: []models.Sequence{{Name: "accounts_id_seq", Schema: "public", StartValue: 1, Increment: 1, MinValue: 1, MaxValue: 2147483647}},
+		Functions: []models.Function{{
+			Name:       "account_total",
+			Schema:     "public",
+			Parameters: []models.Parameter{{Name: "account_id", DataType: "integer", Direction: models.In}},
+			ReturnType: "numeric",
+			Body:       "BEGIN RETURN 0; END",
+		}},
+		Extensions: []models.Extension{{Name: "citext", Schema: "public", Version: "1.6"}},
+	}
+}
+
+func TestCompareRefusesVersion1Digest(t *testing.T) {
+	v1, err := ParseFingerprint(baselineDigest)
+	if err != nil {
+		t.Fatalf("Failed to parse bare digest: %v", err)
+	}
+	if v1.Version != 1 {
+		t.Errorf("Expected a bare digest to parse as version 1, got %d", v1.Version)
+	}
+
+	_, err = Compare(v1, digest(t, Options{}, postgresSchema()))
+	var incompatible *IncompatibleError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("Expected an IncompatibleError rather than a mismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "regenerate the stored fingerprint") {
+		t.Errorf("Expected the error to say how to recover, got %q", err)
+	}
+
+	match, err := Compare(v1, v1)
+	if err != nil || !match {
+		t.Errorf("Version 1 digests should still compare with each other, got %v, %v", match, err)
+	}
+}
+
+func TestFingerprintOptionsChangeCoverage(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		change  func(*models.Schema)
+		same    bool
+	}{
+		{"comments ignored by default", Options{}, func(s *models.Schema) { s.Tables[0].Comment = "Accounts" }, true},
+		{"comments included", Options{IncludeComments: true}, func(s *models.Schema) { s.Tables[0].Columns[1].Comment = "Full name" }, false},
+		{"column order counts by default", Options{}, func(s *models.Schema) { s.Tables[0].Columns[1].Position = 3 }, false},
+		{"column order ignored", Options{IgnoreColumnOrder: true}, func(s *models.Schema) { s.Tables[0].Columns[1].Position = 3 }, true},
+		{"raw bodies see whitespace", Options{}, func(s *models.Schema) { s.Functions[0].Body = "BEGIN RETURN NEW; END" }, false},
+		{"normalized bodies ignore whitespace", Options{Bodies: BodiesNormalized}, func(s *models.Schema) { s.Views[0].Definition = "SELECT id FROM users" }, true},
+		{"normalized bodies see edits", Options{Bodies: BodiesNormalized}, func(s *models.Schema) { s.Functions[0].Body = "BEGIN RETURN OLD; END" }, false},
+		{"excluded bodies", Options{Bodies: BodiesExcluded}, func(s *models.Schema) { s.Functions[0].Body = "" }, true},
+		{"statistics ignored by default", Options{}, func(s *models.Schema) { s.Tables[0].RowCount = nil }, true},
+		{"statistics included", Options{IncludeStatistics: true}, func(s *models.Schema) { *s.Tables[0].RowCount = 11 }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := optionsSchema()
+			tt.change(changed)
+
+			same := digest(t, tt.options, optionsSchema()).Digest == digest(t, tt.options, changed).Digest
+			if same != tt.same {
+				t.Errorf("Expected unchanged fingerprint to be %v", tt.same)
+			}
+		})
+	}
+}
+
+func TestCompareRefusesIncompatibleOptions(t *testing.T) {
+	_, err := Compare(digest(t, Options{}, optionsSchema()), digest(t, Options{IncludeComments: true}, optionsSchema()))
+
+	var incompatible *IncompatibleError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("Expected an IncompatibleError, got %v", err)
+	}
+	if !incompatible.Target.IncludeComments {
+		t.Error("Expected the target options to be reported")
+	}
+}
+
+func TestParseFingerprintRejectsMalformed(t *testing.T) {
+	valid := digest(t, Options{}, optionsSchema()).Digest
+	for _, s := range []string{
+		"",
+		"abc",
+		"v3:sha256:comments=0,order=1,bodies=raw,stats=0:" + valid,
+		"v2:md5:comments=0,order=1,bodies=raw,stats=0:" + valid,
+		"v2:sha256:comments=0,order=1,bodies=raw:" + valid,
+		"v2:sha256:comments=0,order=1,bodies=pretty,stats=0:" + valid,
+		"v2:sha256:comments=0,order=1,bodies=raw,stats=0:xyz",
+	} {
+		if _, err := ParseFingerprint(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package fleet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TenantPlaceholder is replaced by the tenant name in connection templates
+const TenantPlaceholder = "{tenant}"
+
+// Member is one database of a fleet
+type Member struct {
+	Name   string `yaml:"-"`
+	Type   string `yaml:"type"`
+	Conn   string `yaml:"conn"`
+	Schema string `yaml:"schema"`
+}
+
+// LoadMembers reads the connections section of a fleet file, the same
+// shape the serve configuration uses, keeping the entries whose name
+// matches the glob selector. An empty selector keeps every entry. Entries
+// without a type or schema take defaultType and defaultSchema.
+func LoadMembers(file, selector, defaultType, defaultSchema string) ([]Member, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fleet file: %w", err)
+	}
+
+	var config struct {
+		Connections map[string]Member `yaml:"connections"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse fleet file: %w", err)
+	}
+
+	var members []Member
+	for name, member := range config.Connections {
+		if selector != "" {
+			matched, err := path.Match(selector, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		member.Name = name
+		member.Conn = os.ExpandEnv(member.Conn)
+		if member.Type == "" {
+			member.Type = defaultType
+		}
+		if member.Schema == "" {
+			member.Schema = defaultSchema
+		}
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+
+	if err := validateMembers(members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// ExpandTemplate builds one member per tenant by substituting the tenant
+// name for {tenant} in the connection string and schema
+func ExpandTemplate(dbType, connTemplate, schemaTemplate string, tenants []string) ([]Member, error) {
+	if !strings.Contains(connTemplate, TenantPlaceholder) && !strings.Contains(schemaTemplate, TenantPlaceholder) {
+		return nil, fmt.Errorf("connection template must contain %s in the connection string or schema", TenantPlaceholder)
+	}
+
+	members := make([]Member, 0, len(tenants))
+	for _, tenant := range tenants {
+		members = append(members, Member{
+			Name:   tenant,
+			Type:   dbType,
+			Conn:   os.ExpandEnv(strings.ReplaceAll(connTemplate, TenantPlaceholder, tenant)),
+			Schema: strings.ReplaceAll(schemaTemplate, TenantPlaceholder, tenant),
+		})
+	}
+
+	if err := validateMembers(members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// ReadTenants reads tenant names from a file, one per line, skipping blank
+// lines and # comments
+func ReadTenants(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+
+	var tenants []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tenants = append(tenants, line)
+	}
+	return tenants, nil
+}
+
+func validateMembers(members []Member) error {
+	if len(members) == 0 {
+		return errors.New("fleet has no members")
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, member := range members {
+		if seen[member.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate name", member.Name))
+		}
+		seen[member.Name] = true
+		if member.Type == "" || member.Conn == "" || member.Schema == "" {
+			errs = append(errs, fmt.Errorf("%s: type, conn and schema are required", member.Name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid fleet: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+// Package fleet validates many schemas against one reference concurrently
+// and reports the outliers, grouping members whose differences are
+// identical so a few distinct kinds of drift stand out among hundreds of
+// tenants.
+package fleet
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// Result is the outcome of checking one member of a fleet
+type Result struct {
+	Name        string
+	Differences []models.Difference
+	Err         error
+}
+
+// CheckFunc compares one member against the reference
+type CheckFunc func(ctx context.Context, name string) ([]models.Difference, error)
+
+// Run checks every member with at most workers checks in flight and
+// returns the results in the order of names. Members not yet started when
+// ctx is cancelled fail with the context's error.
+func Run(ctx context.Context, names []string, workers int, check CheckFunc) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(names))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i].Name = names[i]
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Differences, results[i].Err = check(ctx, names[i])
+			}
+		}()
+	}
+
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// Group is a set of members with identical differences from the reference
+type Group struct {
+	Members     []string
+	Differences []models.DifferenceSummary
+}
+
+// Report summarizes a fleet run
+type Report struct {
+	// Reference names what every member was compared against
+	Reference string
+	Total     int
+	Matching  []string
+	// Groups holds the deviating members, largest group first
+	Groups []Group
+	Failed []Result
+}
+
+// NewReport groups results by their set of differences
+func NewReport(reference string, results []Result) *Report {
+	report := &Report{Reference: reference, Total: len(results)}
+
+	groups := make(map[string]*Group)
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			report.Failed = append(report.Failed, result)
+		case len(result.Differences) == 0:
+			report.Matching = append(report.Matching, result.Name)
+		default:
+			diffs := models.SummarizeDifferences(result.Differences)
+			key := models.DifferencesKey(diffs)
+			group, ok := groups[key]
+			if !ok {
+				group = &Group{Differences: diffs}
+				groups[key] = group
+			}
+			group.Members = append(group.Members, result.Name)
+		}
+	}
+
+	for _, group := range groups {
+		sort.Strings(group.Members)
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if len(a.Members) != len(b.Members) {
+			return len(a.Members) > len(b.Members)
+		}
+		return a.Members[0] < b.Members[0]
+	})
+	sort.Strings(report.Matching)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Name < report.Failed[j].Name })
+	return report
+}
+
+// Deviating counts the members that differ from the reference
+func (r *Report) Deviating() int {
+	n := 0
+	for _, group := range r.Groups {
+		n += len(group.Members)
+	}
+	return n
+}
+
+// WriteText writes the report grouped by drift
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Validated %d against %s\n", r.Total, r.Reference)
+	fmt.Fprintf(&b, "  ✓ %d match\n", len(r.Matching))
+	fmt.Fprintf(&b, "  ✗ %d deviate in %d groups\n", r.Deviating(), len(r.Groups))
+	if len(r.Failed) > 0 {
+		fmt.Fprintf(&b, "  ! %d failed\n", len(r.Failed))
+	}
+
+	for i, group := range r.Groups {
+		fmt.Fprintf(&b, "\nGroup %d (%d): %s\n", i+1, len(group.Members), strings.Join(group.Members, ", "))
+		for _, diff := range group.Differences {
+			fmt.Fprintf(&b, "  %s %s: %s - %s\n", marker(diff.Type), diff.ObjectType, diff.ObjectName, diff.Description)
+		}
+	}
+
+	if len(r.Failed) > 0 {
+		b.WriteString("\nFailed:\n")
+		for _, result := range r.Failed {
+			fmt.Fprintf(&b, "  %s: %v\n", result.Name, result.Err)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonGroup struct {
+	Members     []string                   `json:"members"`
+	Differences []models.DifferenceSummary `json:"differences"`
+}
+
+type jsonFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := struct {
+		Reference string        `json:"reference"`
+		Total     int           `json:"total"`
+		Matching  []string      `json:"matching"`
+		Deviating int           `json:"deviating"`
+		Groups    []jsonGroup   `json:"groups"`
+		Failed    []jsonFailure `json:"failed"`
+	}{
+		Reference: r.Reference,
+		Total:     r.Total,
+		Matching:  append([]string{}, r.Matching...),
+		Deviating: r.Deviating(),
+		Groups:    []jsonGroup{},
+		Failed:    []jsonFailure{},
+	}
+
+	for _, group := range r.Groups {
+		out.Groups = append(out.Groups, jsonGroup{Members: group.Members, Differences: group.Differences})
+	}
+	for _, result := range r.Failed {
+		out.Failed = append(out.Failed, jsonFailure{Name: result.Name, Error: result.Err.Error()})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// WriteMatrix writes a CSV matrix with one row per member and one column
+// per distinct difference, marked +, - or ~ where the member has it
+func (r *Report) WriteMatrix(w io.Writer) error {
+	var columns []string
+	index := make(map[string]int)
+	for _, group := range r.Groups {
+		for _, diff := range group.Differences {
+			column := diff.ObjectType + " " + diff.ObjectName
+			if _, ok := index[column]; !ok {
+				index[column] = len(columns)
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(append([]string{"name", "status", "group"}, columns...))
+
+	type row struct {
+		name   string
+		record []string
+	}
+	var rows []row
+	for _, name := range r.Matching {
+		rows = append(rows, row{name, append([]string{name, "match", ""}, make([]string, len(columns))...)})
+	}
+	for i, group := range r.Groups {
+		cells := make([]string, len(columns))
+		for _, diff := range group.Differences {
+			cells[index[diff.ObjectType+" "+diff.ObjectName]] += marker(diff.Type)
+		}
+		for _, name := range group.Members {
+			rows = append(rows, row{name, append([]string{name, "deviates", fmt.Sprint(i + 1)}, cells...)})
+		}
+	}
+	for _, result := range r.Failed {
+		rows = append(rows, row{result.Name, append([]string{result.Name, "failed", ""}, make([]string, len(columns))...)})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].name < rows[j].name })
+	for _, row := range rows {
+		_ = writer.Write(row.record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func marker(t models.DifferenceType) string {
+	switch t {
+	case models.Added:
+		return "+"
+	case models.Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
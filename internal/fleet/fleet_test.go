@@ -0,0 +1,149 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+var missingIndex = models.Difference{Type: models.Removed, ObjectType: "Index", ObjectName: "idx_orders_date", Description: "Index removed"}
+var widerID = models.Difference{Type: models.Modified, ObjectType: "Column", ObjectName: "users.id", Description: "Column definition changed"}
+
+func TestRunBoundsWorkersAndKeepsOrder(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	var inFlight, peak atomic.Int32
+
+	results := Run(context.Background(), names, 2, func(ctx context.Context, name string) ([]models.Difference, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if name == "c" {
+			return nil, errors.New("connection refused")
+		}
+		return nil, nil
+	})
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 checks in flight, got %d", peak.Load())
+	}
+	for i, result := range results {
+		if result.Name != names[i] {
+			t.Fatalf("Expected results in input order, got %s at %d", result.Name, i)
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("Expected the failing member's error to be kept")
+	}
+}
+
+func TestReportGroupsIdenticalDrift(t *testing.T) {
+	report := NewReport("golden.yaml", []Result{
+		{Name: "tenant_c", Differences: []models.Difference{widerID, missingIndex}},
+		{Name: "tenant_a"},
+		{Name: "tenant_b", Differences: []models.Difference{missingIndex, widerID}},
+		{Name: "tenant_d", Differences: []models.Difference{missingIndex}},
+		{Name: "tenant_e", Err: errors.New("timeout")},
+	})
+
+	if len(report.Matching) != 1 || len(report.Failed) != 1 || report.Deviating() != 3 {
+		t.Fatalf("Unexpected totals: %+v", report)
+	}
+	if len(report.Groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %+v", report.Groups)
+	}
+	if got := strings.Join(report.Groups[0].Members, ","); got != "tenant_b,tenant_c" {
+		t.Errorf("Expected members with the same differences in any order to group first, got %s", got)
+	}
+
+	var matrix strings.Builder
+	if err := report.WriteMatrix(&matrix); err != nil {
+		t.Fatal(err)
+	}
+	expected := `name,status,group,Column users.id,Index idx_orders_date
+tenant_a,match,,,
+tenant_b,deviates,1,~,-
+tenant_c,deviates,1,~,-
+tenant_d,deviates,2,,-
+tenant_e,failed,,,
+`
+	if matrix.String() != expected {
+		t.Errorf("Unexpected matrix:\n%s\nwant:\n%s", matrix.String(), expected)
+	}
+}
+
+func TestReportSeparatesDifferentChangesToSameObject(t *testing.T) {
+	changedID := func(dataType string) models.Difference {
+		return models.Difference{
+			Type: models.Modified, ObjectType: "Column", ObjectName: "users.id", Description: "Column definition changed",
+			Source: models.Column{Name: "id", DataType: "integer", Position: 1},
+			Target: models.Column{Name: "id", DataType: dataType, Position: 1},
+		}
+	}
+	report := NewReport("golden.yaml", []Result{
+		{Name: "tenant_a", Differences: []models.Difference{changedID("bigint")}},
+		{Name: "tenant_b", Differences: []models.Difference{changedID("text")}},
+		{Name: "tenant_c", Differences: []models.Difference{changedID("bigint")}},
+	})
+
+	if len(report.Groups) != 2 {
+		t.Fatalf("Expected bigint and text changes in separate groups, got %+v", report.Groups)
+	}
+	if got := strings.Join(report.Groups[0].Members, ","); got != "tenant_a,tenant_c" {
+		t.Errorf("Expected tenants with the same change to group, got %s", got)
+	}
+}
+
+func TestMembersFromFileAndTemplate(t *testing.T) {
+	t.Setenv("FLEET_TEST_PASSWORD", "secret")
+	file := filepath.Join(t.TempDir(), "fleet.yaml")
+	config := `
+connections:
+  tenant_a:
+    conn: postgres://app:${FLEET_TEST_PASSWORD}@a/app
+  tenant_b:
+    conn: postgres://app@b/app
+    schema: custom
+  reporting:
+    type: mysql
+    conn: app@tcp(r)/app
+`
+	if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := LoadMembers(file, "tenant_*", "postgresql", "public")
+	if err != nil {
+		t.Fatalf("Failed to load members: %v", err)
+	}
+	if len(members) != 2 || members[0].Name != "tenant_a" || members[1].Schema != "custom" {
+		t.Fatalf("Unexpected members: %+v", members)
+	}
+	if members[0].Type != "postgresql" || members[0].Conn != "postgres://app:secret@a/app" {
+		t.Errorf("Expected defaults and environment expansion, got %+v", members[0])
+	}
+
+	members, err = ExpandTemplate("postgresql", "postgres://app@db/{tenant}", "public", []string{"acme", "globex"})
+	if err != nil {
+		t.Fatalf("Failed to expand template: %v", err)
+	}
+	if members[1].Name != "globex" || members[1].Conn != "postgres://app@db/globex" {
+		t.Errorf("Unexpected template member: %+v", members[1])
+	}
+
+	if _, err := ExpandTemplate("postgresql", "postgres://app@db/app", "public", []string{"acme"}); err == nil {
+		t.Error("Expected a template without {tenant} to be rejected")
+	}
+}
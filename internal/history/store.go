@@ -0,0 +1,358 @@
+// Package history keeps a local, content-addressed repository of schema
+// snapshots so the schema of every environment can be tracked over time.
+//
+// A store is a directory laid out like this:
+//
+//	objects/ab/cdef...   schema snapshots as JSON, named by the SHA256 of their content
+//	revisions.jsonl      one revision per line, oldest first
+//
+// Identical snapshots are stored once, however many revisions point at them.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/compare"
+	"github.com/nechja/schemalyzer/internal/schema"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+const (
+	objectsDir    = "objects"
+	revisionsFile = "revisions.jsonl"
+
+	// minPrefix is the shortest revision ID prefix accepted by Resolve
+	minPrefix = 4
+)
+
+// ErrRevisionNotFound is returned when a revision reference matches nothing
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision records one saved snapshot of an environment's schema
+type Revision struct {
+	// ID is the SHA256 of the revision record itself
+	ID string `json:"id"`
+	// Parent is the previous revision of the same environment
+	Parent      string `json:"parent,omitempty"`
+	Environment string `json:"environment"`
+	Schema      string `json:"schema"`
+	// Object is the content hash of the snapshot
+	Object  string    `json:"object"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message,omitempty"`
+}
+
+// ShortID returns the abbreviated revision ID used in listings
+func (r *Revision) ShortID() string {
+	if len(r.ID) < 12 {
+		return r.ID
+	}
+	return r.ID[:12]
+}
+
+// Change is a difference to an object, attributed to the revision that introduced it
+type Change struct {
+	Revision   *Revision
+	Difference models.Difference
+}
+
+// Store is a snapshot repository rooted at a directory
+type Store struct {
+	dir    string
+	loader *schema.Loader
+}
+
+// Open opens the store in dir, creating it if it doesn't exist
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, objectsDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot store: %w", err)
+	}
+	return &Store{dir: dir, loader: schema.NewLoader()}, nil
+}
+
+// Dir returns the store's root directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save stores a snapshot of an environment and records it as that
+// environment's newest revision
+func (s *Store) Save(env string, snapshot *models.Schema, message string) (*Revision, error) {
+	if env == "" {
+		return nil, errors.New("environment name required")
+	}
+
+	var buf bytes.Buffer
+	if err := s.loader.SaveToJSON(snapshot, &buf); err != nil {
+		return nil, err
+	}
+	object, err := s.writeObject(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &Revision{
+		Environment: env,
+		Schema:      snapshot.Name,
+		Object:      object,
+		Time:        time.Now().UTC(),
+		Message:     message,
+	}
+	if latest := latestOf(revisions, env); latest != nil {
+		rev.Parent = latest.ID
+	}
+
+	record, err := json.Marshal(rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode revision: %w", err)
+	}
+	rev.ID = digest(record)
+
+	return rev, s.appendRevision(rev)
+}
+
+// Log lists revisions newest first, limited to one environment unless env is empty
+func (s *Store) Log(env string) ([]*Revision, error) {
+	revisions, err := s.revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	var log []*Revision
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if env == "" || revisions[i].Environment == env {
+			log = append(log, revisions[i])
+		}
+	}
+	return log, nil
+}
+
+// Resolve finds the revision a reference names. A reference is a revision
+// ID or an unambiguous prefix of at least four characters, an environment
+// name for its newest revision, or "env~N" for the Nth revision before that.
+func (s *Store) Resolve(ref string) (*Revision, error) {
+	revisions, err := s.revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	env, back := ref, 0
+	if name, n, ok := strings.Cut(ref, "~"); ok {
+		back, err = strconv.Atoi(n)
+		if err != nil || back < 0 {
+			return nil, fmt.Errorf("invalid revision %q: want <env>~<count>", ref)
+		}
+		env = name
+	}
+
+	var byEnv []*Revision
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Environment == env {
+			byEnv = append(byEnv, revisions[i])
+		}
+	}
+	if len(byEnv) > 0 {
+		if back >= len(byEnv) {
+			return nil, fmt.Errorf("%w: %s has only %d revisions", ErrRevisionNotFound, env, len(byEnv))
+		}
+		return byEnv[back], nil
+	}
+
+	if len(ref) < minPrefix {
+		return nil, fmt.Errorf("%w: %s", ErrRevisionNotFound, ref)
+	}
+	var match *Revision
+	for _, rev := range revisions {
+		if strings.HasPrefix(rev.ID, strings.ToLower(ref)) {
+			if match != nil {
+				return nil, fmt.Errorf("ambiguous revision %s: matches %s and %s", ref, match.ShortID(), rev.ShortID())
+			}
+			match = rev
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRevisionNotFound, ref)
+	}
+	return match, nil
+}
+
+// Load reads the snapshot a revision points at, checking it against its
+// content hash
+func (s *Store) Load(rev *Revision) (*models.Schema, error) {
+	data, err := os.ReadFile(s.objectPath(rev.Object))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", rev.Object, err)
+	}
+	if digest(data) != rev.Object {
+		return nil, fmt.Errorf("snapshot %s is corrupt: content hash mismatch", rev.Object)
+	}
+	return s.loader.LoadFromJSON(bytes.NewReader(data))
+}
+
+// Blame walks an environment's revisions oldest first and returns every
+// change to an object, attributed to the revision in which it first
+// appeared. object matches a difference's name, e.g. "orders.status".
+// Naming a table also matches the columns, constraints and indexes under
+// it, and an object is created and dropped along with its table.
+func (s *Store) Blame(env, object string) ([]Change, error) {
+	log, err := s.Log(env)
+	if err != nil {
+		return nil, err
+	}
+	if len(log) == 0 {
+		return nil, fmt.Errorf("%w: no revisions for %s", ErrRevisionNotFound, env)
+	}
+
+	comparer := compare.NewComparer()
+	var changes []Change
+	previous := &models.Schema{}
+	previousObject := ""
+	for i := len(log) - 1; i >= 0; i-- {
+		rev := log[i]
+		if rev.Object == previousObject {
+			continue
+		}
+
+		current, err := s.Load(rev)
+		if err != nil {
+			return nil, err
+		}
+		for _, diff := range comparer.Compare(previous, current).Differences {
+			if matchesObject(diff, object) {
+				changes = append(changes, Change{Revision: rev, Difference: diff})
+			}
+		}
+		previous, previousObject = current, rev.Object
+	}
+	return changes, nil
+}
+
+func matchesObject(diff models.Difference, object string) bool {
+	object = strings.ToLower(object)
+	for _, name := range []string{diff.ObjectName, diff.Identity.String()} {
+		name = strings.ToLower(name)
+		switch {
+		case name == object, strings.HasPrefix(name, object+"."):
+			return true
+		case strings.HasPrefix(object, name+".") && diff.Type != models.Modified:
+			return true
+		}
+	}
+	return false
+}
+
+func latestOf(revisions []*Revision, env string) *Revision {
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Environment == env {
+			return revisions[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) objectPath(hash string) string {
+	return filepath.Join(s.dir, objectsDir, hash[:2], hash[2:])
+}
+
+// writeObject stores content under its hash, leaving an existing copy alone
+func (s *Store) writeObject(data []byte) (string, error) {
+	hash := digest(data)
+	path := s.objectPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	// Write then rename so a crash never leaves a truncated object behind.
+	// Each writer gets its own temporary file, so concurrent saves of the
+	// same object never write into each other's.
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash[2:]+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return hash, nil
+}
+
+func (s *Store) revisions() ([]*Revision, error) {
+	file, err := os.Open(filepath.Join(s.dir, revisionsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+	defer file.Close()
+
+	var revisions []*Revision
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		rev := &Revision{}
+		if err := json.Unmarshal(scanner.Bytes(), rev); err != nil {
+			return nil, fmt.Errorf("failed to read revisions: line %d: %w", line, err)
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (s *Store) appendRevision(rev *Revision) error {
+	line, err := json.Marshal(rev)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(s.dir, revisionsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return file.Close()
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,182 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+func ordersSchema(nullable bool) *models.Schema {
+	return &models.Schema{
+		Name: "app",
+		Tables: []models.Table{
+			{
+				Name: "orders",
+				Columns: []models.Column{
+					{Name: "id", DataType: "integer", Position: 1},
+					{Name: "status", DataType: "text", Position: 2, IsNullable: nullable},
+				},
+			},
+		},
+	}
+}
+
+func save(t *testing.T, store *Store, env string, snapshot *models.Schema) *Revision {
+	t.Helper()
+	rev, err := store.Save(env, snapshot, "")
+	if err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+	return rev
+}
+
+func TestSaveDeduplicatesContent(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	first := save(t, store, "prod", ordersSchema(false))
+	second := save(t, store, "staging", ordersSchema(false))
+
+	if first.Object != second.Object {
+		t.Error("Identical snapshots should share one object")
+	}
+	if first.ID == second.ID {
+		t.Error("Each save should record its own revision")
+	}
+
+	loaded, err := store.Load(second)
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if len(loaded.Tables) != 1 || loaded.Tables[0].Name != "orders" {
+		t.Errorf("Unexpected snapshot contents: %+v", loaded)
+	}
+}
+
+func TestConcurrentWritesOfSameObject(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	data := []byte(`{"Name":"app"}`)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.writeObject(data); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent write failed: %v", err)
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(store.Dir(), objectsDir, "*", "*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leftovers) > 0 {
+		t.Errorf("Temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	first := save(t, store, "prod", ordersSchema(false))
+	second := save(t, store, "prod", ordersSchema(true))
+	if second.Parent != first.ID {
+		t.Errorf("Expected parent %s, got %s", first.ID, second.Parent)
+	}
+
+	tests := map[string]*Revision{
+		"prod":          second,
+		"prod~0":        second,
+		"prod~1":        first,
+		first.ShortID(): first,
+		first.ID:        first,
+	}
+	for ref, expected := range tests {
+		rev, err := store.Resolve(ref)
+		if err != nil {
+			t.Errorf("Failed to resolve %s: %v", ref, err)
+			continue
+		}
+		if rev.ID != expected.ID {
+			t.Errorf("Expected %s to resolve to %s, got %s", ref, expected.ShortID(), rev.ShortID())
+		}
+	}
+
+	for _, ref := range []string{"prod~2", "staging", "abc"} {
+		if _, err := store.Resolve(ref); !errors.Is(err, ErrRevisionNotFound) {
+			t.Errorf("Expected %s to be not found, got %v", ref, err)
+		}
+	}
+}
+
+func TestBlameFindsRevisionThatChangedObject(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	created := save(t, store, "prod", ordersSchema(false))
+	save(t, store, "prod", ordersSchema(false))
+	nullable := save(t, store, "prod", ordersSchema(true))
+	save(t, store, "staging", ordersSchema(false))
+
+	changes, err := store.Blame("prod", "orders.status")
+	if err != nil {
+		t.Fatalf("Failed to blame: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Expected the column to be added then modified, got %+v", changes)
+	}
+	if changes[0].Revision.ID != created.ID || changes[0].Difference.Type != models.Added {
+		t.Errorf("Expected the column to appear in %s, got %+v", created.ShortID(), changes[0])
+	}
+	if changes[1].Revision.ID != nullable.ID || changes[1].Difference.Type != models.Modified {
+		t.Errorf("Expected the column to change in %s, got %+v", nullable.ShortID(), changes[1])
+	}
+
+	table, err := store.Blame("prod", "orders")
+	if err != nil {
+		t.Fatalf("Failed to blame: %v", err)
+	}
+	if len(table) != 2 || table[0].Difference.ObjectType != "Table" {
+		t.Errorf("Expected the table to be created then its status column changed, got %+v", table)
+	}
+}
+
+func TestLoadRejectsCorruptObject(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	rev := save(t, store, "prod", ordersSchema(false))
+	path := filepath.Join(dir, objectsDir, rev.Object[:2], rev.Object[2:])
+	if err := os.WriteFile(path, []byte(`{"Name":"tampered"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Load(rev); err == nil {
+		t.Error("Expected a content hash mismatch")
+	}
+}
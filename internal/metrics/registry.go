@@ -0,0 +1,237 @@
+// Package metrics is a small registry of gauges, counters and histograms
+// that renders the Prometheus text exposition format, so long-running modes
+// can be scraped without pulling in a client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is a metric type
+type Kind string
+
+const (
+	Gauge     Kind = "gauge"
+	Counter   Kind = "counter"
+	Histogram Kind = "histogram"
+)
+
+// Labels are the label names and values of one series
+type Labels map[string]string
+
+// key renders labels in Prometheus syntax with names sorted, so equal label
+// sets share a series
+func (l Labels) key() string {
+	if len(l) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escape(l[name]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+// matches reports whether the series has every label in match
+func (l Labels) matches(match Labels) bool {
+	for name, value := range match {
+		if l[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    Kind
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	labels Labels
+	value  float64
+	// histograms only: cumulative bucket counts, sum and count
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Registry holds metric families. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Register declares a metric family. Histograms take their upper bucket
+// bounds in increasing order; +Inf is added automatically.
+func (r *Registry) Register(name, help string, kind Kind, buckets ...float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families[name] = &family{name: name, help: help, kind: kind, buckets: buckets, series: make(map[string]*series)}
+}
+
+// Set sets a gauge
+func (r *Registry) Set(name string, labels Labels, value float64) {
+	r.update(name, Gauge, labels, func(s *series) { s.value = value })
+}
+
+// Add increments a counter
+func (r *Registry) Add(name string, labels Labels, delta float64) {
+	r.update(name, Counter, labels, func(s *series) { s.value += delta })
+}
+
+// Observe records a histogram sample
+func (r *Registry) Observe(name string, labels Labels, value float64) {
+	r.update(name, Histogram, labels, func(s *series) {
+		f := r.families[name]
+		if s.counts == nil {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		for i, bound := range f.buckets {
+			if value <= bound {
+				s.counts[i]++
+			}
+		}
+		s.sum += value
+		s.count++
+	})
+}
+
+// Delete removes every series of a family that has all the given labels,
+// so values from an earlier state don't linger
+func (r *Registry) Delete(name string, match Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok {
+		return
+	}
+	for key, s := range f.series {
+		if s.labels.matches(match) {
+			delete(f.series, key)
+		}
+	}
+}
+
+func (r *Registry) update(name string, kind Kind, labels Labels, apply func(*series)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok || f.kind != kind {
+		panic(fmt.Sprintf("metrics: %s is not a registered %s", name, kind))
+	}
+
+	key := labels.key()
+	s, ok := f.series[key]
+	if !ok {
+		copied := make(Labels, len(labels))
+		for k, v := range labels {
+			copied[k] = v
+		}
+		s = &series{labels: copied}
+		f.series[key] = s
+	}
+	apply(s)
+}
+
+// WriteTo renders every family in the Prometheus text format, families and
+// series sorted for stable output
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != Histogram {
+				fmt.Fprintf(&b, "%s%s %s\n", f.name, braces(key), formatValue(s.value))
+				continue
+			}
+			for i, bound := range f.buckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, braces(joinLabels(key, `le="`+formatValue(bound)+`"`)), s.counts[i])
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, braces(joinLabels(key, `le="+Inf"`)), s.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", f.name, braces(key), formatValue(s.sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", f.name, braces(key), s.count)
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the registry as a Prometheus scrape target
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = r.WriteTo(w)
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case v == math.Trunc(v) && math.Abs(v) < 1e15:
+		// Whole numbers such as timestamps and counts read better without an exponent
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	return b.String()
+}
+
+func TestRegistryRendersExpositionFormat(t *testing.T) {
+	r := NewRegistry()
+	r.Register("drift", "Whether the target drifted", Gauge)
+	r.Register("errors_total", "Failed checks", Counter)
+	r.Register("read_seconds", "Read latency", Histogram, 0.1, 1)
+
+	r.Set("drift", Labels{"target": "prod"}, 1)
+	r.Add("errors_total", Labels{"target": "prod"}, 1)
+	r.Add("errors_total", Labels{"target": "prod"}, 2)
+	r.Observe("read_seconds", Labels{"target": "prod"}, 0.05)
+	r.Observe("read_seconds", Labels{"target": "prod"}, 0.5)
+
+	expected := `# HELP drift Whether the target drifted
+# TYPE drift gauge
+drift{target="prod"} 1
+# HELP errors_total Failed checks
+# TYPE errors_total counter
+errors_total{target="prod"} 3
+# HELP read_seconds Read latency
+# TYPE read_seconds histogram
+read_seconds_bucket{target="prod",le="0.1"} 1
+read_seconds_bucket{target="prod",le="1"} 2
+read_seconds_bucket{target="prod",le="+Inf"} 2
+read_seconds_sum{target="prod"} 0.55
+read_seconds_count{target="prod"} 2
+`
+	if got := render(t, r); got != expected {
+		t.Errorf("Unexpected exposition:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestRegistryDeleteAndEscaping(t *testing.T) {
+	r := NewRegistry()
+	r.Register("differences", "Differences", Gauge)
+	r.Set("differences", Labels{"target": "prod", "type": "Column"}, 2)
+	r.Set("differences", Labels{"target": "prod", "type": "Index"}, 1)
+	r.Set("differences", Labels{"target": "dev", "type": `a "quoted"\name`}, 1)
+
+	r.Delete("differences", Labels{"target": "prod"})
+
+	got := render(t, r)
+	if strings.Contains(got, `target="prod"`) {
+		t.Errorf("Expected prod series to be deleted:\n%s", got)
+	}
+	if !strings.Contains(got, `differences{target="dev",type="a \"quoted\"\\name"} 1`) {
+		t.Errorf("Expected escaped label values:\n%s", got)
+	}
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// Detail is the side-by-side view of one difference: the attributes of the
+// compared objects and line diffs of their view or routine bodies
+type Detail struct {
+	Attributes []DetailAttribute
+	Bodies     []DetailBody
+}
+
+// DetailAttribute is one attribute of the compared objects
+type DetailAttribute struct {
+	Name    string
+	Source  string
+	Target  string
+	Changed bool
+}
+
+// DetailBody is a line diff of a changed multi-line attribute
+type DetailBody struct {
+	Name  string
+	Lines []DiffLine
+}
+
+// DiffLine is one line of a body diff
+type DiffLine struct {
+	Kind string // "same", "add" or "del"
+	Text string
+}
+
+// Describe breaks a difference down into the attributes of its source and
+// target objects, skipping those unset on both sides. Changed bodies and
+// other multi-line values are returned as line diffs instead.
+func Describe(diff models.Difference) Detail {
+	source, sourceNames := attributes(diff.Source)
+	target, targetNames := attributes(diff.Target)
+	names := sourceNames
+	for _, name := range targetNames {
+		if _, ok := source[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	var detail Detail
+	for _, name := range names {
+		s, t := source[name], target[name]
+		if s == "" && t == "" {
+			continue
+		}
+		changed := diff.Type == models.Modified && s != t
+		if changed && (bodyFields[name] || strings.Contains(s, "\n") || strings.Contains(t, "\n")) {
+			detail.Bodies = append(detail.Bodies, DetailBody{Name: name, Lines: diffLines(s, t)})
+			continue
+		}
+		detail.Attributes = append(detail.Attributes, DetailAttribute{Name: name, Source: s, Target: t, Changed: changed})
+	}
+	return detail
+}
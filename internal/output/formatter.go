@@ -5,27 +5,48 @@ import (
 	"fmt"
 	"github.com/nechja/schemalyzer/pkg/models"
 	"gopkg.in/yaml.v3"
+	"sort"
 	"strings"
 )
 
 type OutputFormat string
 
 const (
-	FormatJSON    OutputFormat = "json"
-	FormatYAML    OutputFormat = "yaml"
-	FormatText    OutputFormat = "text"
-	FormatSummary OutputFormat = "summary"
+	FormatJSON     OutputFormat = "json"
+	FormatYAML     OutputFormat = "yaml"
+	FormatText     OutputFormat = "text"
+	FormatSummary  OutputFormat = "summary"
+	FormatHTML     OutputFormat = "html"
+	FormatJUnit    OutputFormat = "junit"
+	FormatSARIF    OutputFormat = "sarif"
+	FormatMarkdown OutputFormat = "markdown"
+	FormatJSONTree OutputFormat = "json-tree"
+	FormatYAMLTree OutputFormat = "yaml-tree"
 )
 
 type Formatter struct {
 	format OutputFormat
+	// maxBytes caps the size of markdown reports
+	maxBytes int
 }
 
 func NewFormatter(format OutputFormat) *Formatter {
 	return &Formatter{format: format}
 }
 
+// WithMaxBytes caps the size of size-limited formats such as markdown;
+// zero keeps the format's default
+func (f *Formatter) WithMaxBytes(maxBytes int) *Formatter {
+	f.maxBytes = maxBytes
+	return f
+}
+
 func (f *Formatter) Format(result *models.ComparisonResult) ([]byte, error) {
+	// Results read back from files or built by callers may be in any order
+	sorted := *result
+	sorted.Differences = sortedDifferences(result.Differences)
+	result = &sorted
+
 	switch f.format {
 	case FormatJSON:
 		return f.formatJSON(result)
@@ -35,6 +56,18 @@ func (f *Formatter) Format(result *models.ComparisonResult) ([]byte, error) {
 		return f.formatText(result)
 	case FormatSummary:
 		return f.formatSummary(result)
+	case FormatHTML:
+		return f.formatHTML(result)
+	case FormatJUnit:
+		return f.formatJUnit(result)
+	case FormatSARIF:
+		return f.formatSARIF(result)
+	case FormatMarkdown:
+		return f.formatMarkdown(result)
+	case FormatJSONTree:
+		return f.formatJSONTree(result)
+	case FormatYAMLTree:
+		return f.formatYAMLTree(result)
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", f.format)
 	}
@@ -111,49 +144,40 @@ func (f *Formatter) formatText(result *models.ComparisonResult) ([]byte, error)
 		}
 	}
 
-	// Write added objects
-	if len(added) > 0 {
-		sb.WriteString("Added Objects\n")
-		sb.WriteString("-------------\n")
-		for _, diff := range added {
-			sb.WriteString(fmt.Sprintf("+ %s: %s\n", diff.ObjectType, diff.ObjectName))
-			sb.WriteString(fmt.Sprintf("  %s\n", diff.Description))
-			if extra := formatConstraintActions(diff); extra != "" {
-				sb.WriteString(extra)
-			}
-		}
-		sb.WriteString("\n")
-	}
+	writeTextSection(&sb, "Added Objects", "+", added)
+	writeTextSection(&sb, "Removed Objects", "-", removed)
+	writeTextSection(&sb, "Modified Objects", "~", modified)
 
-	// Write removed objects
-	if len(removed) > 0 {
-		sb.WriteString("Removed Objects\n")
-		sb.WriteString("---------------\n")
-		for _, diff := range removed {
-			sb.WriteString(fmt.Sprintf("- %s: %s\n", diff.ObjectType, diff.ObjectName))
-			sb.WriteString(fmt.Sprintf("  %s\n", diff.Description))
-			if extra := formatConstraintActions(diff); extra != "" {
-				sb.WriteString(extra)
-			}
-		}
-		sb.WriteString("\n")
+	return []byte(sb.String()), nil
+}
+
+// writeTextSection lists differences under a heading, nesting the members
+// of a table under the table's name unless the table itself is listed
+func writeTextSection(sb *strings.Builder, title, marker string, diffs []models.Difference) {
+	if len(diffs) == 0 {
+		return
 	}
 
-	// Write modified objects
-	if len(modified) > 0 {
-		sb.WriteString("Modified Objects\n")
-		sb.WriteString("----------------\n")
-		for _, diff := range modified {
-			sb.WriteString(fmt.Sprintf("~ %s: %s\n", diff.ObjectType, diff.ObjectName))
-			sb.WriteString(fmt.Sprintf("  %s\n", diff.Description))
-			if extra := formatConstraintActions(diff); extra != "" {
-				sb.WriteString(extra)
+	sb.WriteString(title + "\n")
+	sb.WriteString(strings.Repeat("-", len(title)) + "\n")
+	var table objectOwner
+	for _, diff := range diffs {
+		indent := ""
+		owner := ownerOf(diff)
+		if owner.ObjectType == "Table" && diff.ObjectType != "Table" {
+			if owner != table {
+				sb.WriteString(fmt.Sprintf("Table %s\n", owner.Identity))
 			}
+			indent = "  "
+		}
+		table = owner
+		sb.WriteString(fmt.Sprintf("%s%s %s: %s\n", indent, marker, diff.ObjectType, diff.ObjectName))
+		sb.WriteString(fmt.Sprintf("%s  %s\n", indent, diff.Description))
+		if extra := formatConstraintActions(diff); extra != "" {
+			sb.WriteString(indent + extra)
 		}
-		sb.WriteString("\n")
 	}
-
-	return []byte(sb.String()), nil
+	sb.WriteString("\n")
 }
 
 func (f *Formatter) formatSummary(result *models.ComparisonResult) ([]byte, error) {
@@ -173,21 +197,28 @@ func (f *Formatter) formatSummary(result *models.ComparisonResult) ([]byte, erro
 
 	sb.WriteString("Differences by Type:\n")
 	sb.WriteString("-------------------\n")
-	for diffType, count := range summary {
-		sb.WriteString(fmt.Sprintf("  %s: %d\n", diffType, count))
+	for _, diffType := range []string{"added", "removed", "modified"} {
+		if count, ok := summary[diffType]; ok {
+			sb.WriteString(fmt.Sprintf("  %s: %d\n", diffType, count))
+		}
 	}
 
 	sb.WriteString("\nDifferences by Object:\n")
 	sb.WriteString("---------------------\n")
 
 	objectCounts := make(map[string]int)
+	var objectKeys []string
 	for _, diff := range result.Differences {
 		key := fmt.Sprintf("%s %s", diff.Type, diff.ObjectType)
+		if objectCounts[key] == 0 {
+			objectKeys = append(objectKeys, key)
+		}
 		objectCounts[key]++
 	}
+	sort.Strings(objectKeys)
 
-	for objType, count := range objectCounts {
-		sb.WriteString(fmt.Sprintf("  %s: %d\n", objType, count))
+	for _, objType := range objectKeys {
+		sb.WriteString(fmt.Sprintf("  %s: %d\n", objType, objectCounts[objType]))
 	}
 
 	sb.WriteString(fmt.Sprintf("\nTotal Differences: %d\n", len(result.Differences)))
@@ -195,6 +226,18 @@ func (f *Formatter) formatSummary(result *models.ComparisonResult) ([]byte, erro
 	return []byte(sb.String()), nil
 }
 
+// sortedDifferences returns the differences in hierarchical order without
+// reordering the caller's slice
+func sortedDifferences(diffs []models.Difference) []models.Difference {
+	if diffs == nil {
+		return nil
+	}
+	sorted := make([]models.Difference, len(diffs))
+	copy(sorted, diffs)
+	models.SortDifferences(sorted)
+	return sorted
+}
+
 func (f *Formatter) generateSummary(result *models.ComparisonResult) map[string]int {
 	summary := make(map[string]int)
 
@@ -265,3 +308,25 @@ func constraintFromInterface(val interface{}) *models.Constraint {
 func isForeignKeyConstraint(c *models.Constraint) bool {
 	return c != nil && c.Type == models.ForeignKey
 }
+
+// objectOwner identifies the object a difference is reported under. Columns,
+// constraints, indexes, policies and triggers belong to their table, so a
+// report can show each table once with everything that changed in it.
+type objectOwner struct {
+	ObjectType string
+	Identity   models.ObjectIdentity
+}
+
+func ownerOf(diff models.Difference) objectOwner {
+	id := diff.Identity
+	switch {
+	case id.Table != "":
+		return objectOwner{"Table", models.ObjectIdentity{Schema: id.Schema, Name: id.Table}}
+	case diff.ObjectType == "Table Comment" || diff.ObjectType == "Row Security":
+		return objectOwner{"Table", id}
+	case id.Name == "":
+		// Results loaded from older exports carry no identity
+		return objectOwner{diff.ObjectType, models.ObjectIdentity{Name: diff.ObjectName}}
+	}
+	return objectOwner{diff.ObjectType, id}
+}
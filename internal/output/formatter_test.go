@@ -2,6 +2,9 @@ package output
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -196,6 +199,347 @@ func TestFormatter_FormatSummary_NoDifferences(t *testing.T) {
 	assert.Contains(t, text, "Result: SCHEMAS ARE IDENTICAL")
 }
 
+func TestFormatter_FormatHTML(t *testing.T) {
+	formatter := NewFormatter(FormatHTML)
+
+	result := &models.ComparisonResult{
+		SourceDatabase: "postgresql://prod",
+		TargetDatabase: "postgresql://<staging>",
+		ComparisonTime: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+		Differences: []models.Difference{
+			{
+				Type:        models.Modified,
+				ObjectType:  "Column",
+				ObjectName:  "users.id",
+				Identity:    models.ObjectIdentity{Table: "users", Name: "id"},
+				Source:      &models.Column{Name: "id", DataType: "integer", Position: 1},
+				Target:      &models.Column{Name: "id", DataType: "bigint", Position: 1},
+				Description: "Column definition changed",
+			},
+			{
+				Type:        models.Modified,
+				ObjectType:  "View",
+				ObjectName:  "active_users",
+				Identity:    models.ObjectIdentity{Name: "active_users"},
+				Source:      &models.View{Name: "active_users", Definition: "SELECT id\nFROM users\nWHERE active"},
+				Target:      &models.View{Name: "active_users", Definition: "SELECT id\nFROM users\nWHERE active AND verified"},
+				Description: "View definition changed",
+			},
+		},
+	}
+
+	output, err := formatter.Format(result)
+	assert.NoError(t, err)
+
+	html := string(output)
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Contains(t, html, "postgresql://&lt;staging&gt;", "values must be escaped")
+	assert.Contains(t, html, "<summary>Table users <span class=\"count\">(1)</span></summary>")
+	assert.Contains(t, html, "<summary>Views <span class=\"count\">(1)</span></summary>")
+	assert.Contains(t, html, `<tr class="changed"><th>DataType</th><td>integer</td><td>bigint</td></tr>`)
+	assert.Contains(t, html, `<span class="del">WHERE active</span><span class="add">WHERE active AND verified</span>`)
+	assert.Contains(t, html, `<span class="same">FROM users</span>`)
+	assert.NotContains(t, html, "<th>Samples</th>", "fields empty on both sides are omitted")
+	assert.NotContains(t, html, "http", "the report must not load external resources")
+}
+
+func TestFormatter_FormatJUnit(t *testing.T) {
+	formatter := NewFormatter(FormatJUnit)
+
+	schema := func(idType string) *models.Schema {
+		return &models.Schema{
+			Name: "app",
+			Tables: []models.Table{
+				{Name: "users", Columns: []models.Column{{Name: "id", DataType: idType}}},
+				{Name: "orders"},
+			},
+		}
+	}
+	result := &models.ComparisonResult{
+		SourceSchema: schema("integer"),
+		TargetSchema: schema("bigint"),
+		Differences: []models.Difference{
+			{
+				Type:        models.Modified,
+				ObjectType:  "Column",
+				ObjectName:  "users.id",
+				Identity:    models.ObjectIdentity{Table: "users", Name: "id"},
+				Source:      &models.Column{Name: "id", DataType: "integer"},
+				Target:      &models.Column{Name: "id", DataType: "bigint"},
+				Description: "Column definition changed",
+			},
+		},
+	}
+
+	output, err := formatter.Format(result)
+	assert.NoError(t, err)
+
+	var report struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name     string `xml:"name,attr"`
+				Failures []struct {
+					Type string `xml:"type,attr"`
+					Text string `xml:",chardata"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	assert.NoError(t, xml.Unmarshal(output, &report))
+	assert.Equal(t, 2, report.Tests, "every compared table is a test case")
+	assert.Equal(t, 1, report.Failures)
+	if assert.Len(t, report.Suites, 1) && assert.Len(t, report.Suites[0].Cases, 2) {
+		orders, users := report.Suites[0].Cases[0], report.Suites[0].Cases[1]
+		assert.Equal(t, "orders", orders.Name, "test cases are in name order")
+		assert.Empty(t, orders.Failures)
+		assert.Equal(t, "users", users.Name)
+		if assert.Len(t, users.Failures, 1) {
+			assert.Equal(t, "MODIFIED", users.Failures[0].Type)
+			assert.Contains(t, users.Failures[0].Text, "DataType: integer -> bigint")
+		}
+	}
+}
+
+func TestFormatter_FormatSARIF(t *testing.T) {
+	formatter := NewFormatter(FormatSARIF)
+
+	result := &models.ComparisonResult{
+		SourceDatabase: "schemas/golden.yaml",
+		TargetDatabase: "postgresql://public",
+		Differences: []models.Difference{
+			{Type: models.Removed, ObjectType: "Table", ObjectName: "audit_log", Identity: models.ObjectIdentity{Name: "audit_log"}, Description: "Table removed"},
+			{Type: models.Added, ObjectType: "Index", ObjectName: "users.idx_email", Identity: models.ObjectIdentity{Table: "users", Name: "idx_email"}, Description: "Index added"},
+			{Type: models.Removed, ObjectType: "Table", ObjectName: "sessions", Identity: models.ObjectIdentity{Name: "sessions"}, Description: "Table removed"},
+		},
+	}
+
+	output, err := formatter.Format(result)
+	assert.NoError(t, err)
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	assert.NoError(t, json.Unmarshal(output, &log))
+	assert.Equal(t, "2.1.0", log.Version)
+
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 2, "one rule per kind of difference")
+	assert.Len(t, run.Results, 3)
+	for _, r := range run.Results {
+		assert.Equal(t, r.RuleID, run.Tool.Driver.Rules[r.RuleIndex].ID)
+	}
+	// Results are in hierarchical order: the tables, then the index under users
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "note", run.Results[2].Level)
+	assert.Equal(t, "schemas/golden.yaml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "sessions", run.Results[1].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, "users.idx_email", run.Results[2].Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
+
+func TestFormatter_FormatMarkdown(t *testing.T) {
+	formatter := NewFormatter(FormatMarkdown)
+
+	result := &models.ComparisonResult{
+		SourceDatabase: "postgresql://prod",
+		TargetDatabase: "postgresql://staging",
+		Differences: []models.Difference{
+			{
+				Type:        models.Modified,
+				ObjectType:  "Column",
+				ObjectName:  "users.id",
+				Identity:    models.ObjectIdentity{Table: "users", Name: "id"},
+				Source:      &models.Column{Name: "id", DataType: "integer"},
+				Target:      &models.Column{Name: "id", DataType: "bigint"},
+				Description: "Column definition changed",
+			},
+			{
+				Type:        models.Modified,
+				ObjectType:  "View",
+				ObjectName:  "active_users",
+				Identity:    models.ObjectIdentity{Name: "active_users"},
+				Source:      &models.View{Name: "active_users", Definition: "SELECT id\nWHERE active"},
+				Target:      &models.View{Name: "active_users", Definition: "SELECT id\nWHERE active AND verified"},
+				Description: "View definition changed",
+			},
+		},
+	}
+
+	output, err := formatter.Format(result)
+	assert.NoError(t, err)
+
+	markdown := string(output)
+	assert.Contains(t, markdown, "| Column | 0 | 0 | 1 |")
+	assert.Contains(t, markdown, "### Table users")
+	assert.Contains(t, markdown, "| ~ | `id` | `integer` NOT NULL | `bigint` NOT NULL |")
+	assert.Contains(t, markdown, "<details><summary>Definition diff</summary>")
+	assert.Contains(t, markdown, "  -WHERE active\n  +WHERE active AND verified\n")
+	assert.NotContains(t, markdown, "truncated")
+
+	limit := len(markdown) - 1
+	truncated, err := NewFormatter(FormatMarkdown).WithMaxBytes(limit).Format(result)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(truncated), limit)
+	assert.Contains(t, string(truncated), "### Table users", "sections that fit are kept")
+	assert.NotContains(t, string(truncated), "### Views")
+	assert.Contains(t, string(truncated), "showing 1 of 2 differences, 1 sections omitted")
+}
+
+func TestTemplate_ExecuteComparison(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "report.md.tmpl")
+	html := filepath.Join(dir, "report.html")
+	body := `{{range .Differences | groupBy "section"}}{{.Key}}:{{range .Differences}} {{marker .Change}}{{.Name}}{{range .Changes}} {{.Name}}={{.Before}}->{{.After}}{{end}}{{end}}
+{{end}}removed={{len (.Differences | where "change" "removed")}}`
+	assert.NoError(t, os.WriteFile(text, []byte(body), 0644))
+	assert.NoError(t, os.WriteFile(html, []byte(`<p>{{.Source}}</p>`), 0644))
+
+	result := &models.ComparisonResult{
+		SourceDatabase: "<prod>",
+		// Out of order, as a loaded or hand-built result may be
+		Differences: []models.Difference{
+			{Type: models.Removed, ObjectType: "View", ObjectName: "active_users", Identity: models.ObjectIdentity{Name: "active_users"}},
+			{
+				Type:       models.Modified,
+				ObjectType: "Column",
+				ObjectName: "users.id",
+				Identity:   models.ObjectIdentity{Table: "users", Name: "id"},
+				Source:     &models.Column{Name: "id", DataType: "integer"},
+				Target:     &models.Column{Name: "id", DataType: "bigint"},
+			},
+		},
+	}
+
+	view := NewComparisonView(result)
+	assert.Equal(t, "users.id", view.Differences[0].Name, "templates see differences in hierarchical order")
+
+	tmpl, err := ParseTemplate(text)
+	assert.NoError(t, err)
+	output, err := tmpl.ExecuteComparison(result)
+	assert.NoError(t, err)
+	assert.Equal(t, "Table users: ~users.id DataType=integer->bigint\nViews: -active_users\nremoved=1", string(output))
+
+	tmpl, err = ParseTemplate(html)
+	assert.NoError(t, err)
+	output, err = tmpl.ExecuteComparison(result)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>&lt;prod&gt;</p>", string(output), "html templates escape values")
+
+	bad := filepath.Join(dir, "bad.tmpl")
+	assert.NoError(t, os.WriteFile(bad, []byte(`{{.Differences | groupBy "color"}}`), 0644))
+	tmpl, err = ParseTemplate(bad)
+	assert.NoError(t, err)
+	_, err = tmpl.ExecuteComparison(result)
+	assert.ErrorContains(t, err, `unknown field "color"`)
+}
+
+func TestTemplate_ExecuteSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.txt")
+	body := `{{.Name}}{{range .Tables}} {{.Name}}({{join (primaryKey .) ","}}){{range .Columns}} {{.Name}}:{{formatType .}}{{end}}{{end}}`
+	assert.NoError(t, os.WriteFile(path, []byte(body), 0644))
+
+	defaultValue := "now()"
+	schema := &models.Schema{
+		Name: "app",
+		Tables: []models.Table{{
+			Name: "users",
+			Columns: []models.Column{
+				{Name: "id", DataType: "integer", IsPrimaryKey: true},
+				{Name: "created", DataType: "timestamp", IsNullable: true, DefaultValue: &defaultValue},
+			},
+		}},
+	}
+
+	tmpl, err := ParseTemplate(path)
+	assert.NoError(t, err)
+	output, err := tmpl.ExecuteSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, "app users(id) id:integer NOT NULL created:timestamp DEFAULT now()", string(output))
+}
+
+func hierarchyResult() *models.ComparisonResult {
+	return &models.ComparisonResult{
+		SourceDatabase: "postgresql://prod",
+		TargetDatabase: "postgresql://staging",
+		Differences: []models.Difference{
+			{Type: models.Modified, ObjectType: "View", ObjectName: "active_users", Identity: models.ObjectIdentity{Name: "active_users"}, Description: "View definition changed"},
+			{Type: models.Added, ObjectType: "Index", ObjectName: "users.idx_email", Identity: models.ObjectIdentity{Table: "users", Name: "idx_email"}, Description: "Index exists in target but not in source"},
+			{Type: models.Modified, ObjectType: "Column", ObjectName: "users.id", Identity: models.ObjectIdentity{Table: "users", Name: "id"}, Description: "Column definition changed"},
+			{Type: models.Added, ObjectType: "Table", ObjectName: "audit_log", Identity: models.ObjectIdentity{Name: "audit_log"}, Description: "Table exists in target but not in source"},
+			{Type: models.Modified, ObjectType: "Column", ObjectName: "users.email", Identity: models.ObjectIdentity{Table: "users", Name: "email"}, Description: "Column definition changed"},
+		},
+	}
+}
+
+func TestFormatter_FormatText_Hierarchical(t *testing.T) {
+	output, err := NewFormatter(FormatText).Format(hierarchyResult())
+	assert.NoError(t, err)
+
+	text := string(output)
+	assert.Contains(t, text, "Modified Objects\n----------------\nTable users\n  ~ Column: users.email\n    Column definition changed\n  ~ Column: users.id\n")
+	assert.Less(t, strings.Index(text, "users.id"), strings.Index(text, "View: active_users"))
+}
+
+func TestFormatter_FormatJSONTree(t *testing.T) {
+	result := hierarchyResult()
+	output, err := NewFormatter(FormatJSONTree).Format(result)
+	assert.NoError(t, err)
+	assert.Equal(t, "View", result.Differences[0].ObjectType, "the caller's differences are not reordered")
+
+	var report struct {
+		TotalDifferences int         `json:"total_differences"`
+		Objects          []*TreeNode `json:"objects"`
+	}
+	assert.NoError(t, json.Unmarshal(output, &report))
+	assert.Equal(t, 5, report.TotalDifferences)
+	if !assert.Len(t, report.Objects, 3) {
+		return
+	}
+
+	auditLog, users, view := report.Objects[0], report.Objects[1], report.Objects[2]
+	assert.Equal(t, "audit_log", auditLog.Name)
+	assert.Len(t, auditLog.Differences, 1)
+	assert.Equal(t, "users", users.Name)
+	assert.Empty(t, users.Differences)
+	if assert.Len(t, users.Children, 3) {
+		assert.Equal(t, "email", users.Children[0].Name)
+		assert.Equal(t, "id", users.Children[1].Name)
+		assert.Equal(t, "Index", users.Children[2].ObjectType)
+		assert.Len(t, users.Children[2].Differences, 1)
+	}
+	assert.Equal(t, "View", view.ObjectType)
+
+	yamlOutput, err := NewFormatter(FormatYAMLTree).Format(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(yamlOutput), "children:")
+}
+
 func TestFormatter_UnsupportedFormat(t *testing.T) {
 	formatter := NewFormatter("invalid")
 
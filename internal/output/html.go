@@ -0,0 +1,431 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// maxDiffLines bounds the bodies diffed line by line; longer bodies are
+// shown whole on each side instead
+const maxDiffLines = 2000
+
+// bodyFields are attributes holding SQL text, shown as a line diff
+var bodyFields = map[string]bool{"Definition": true, "Body": true}
+
+type htmlReport struct {
+	Source    string
+	Target    string
+	Time      string
+	Total     int
+	Added     int
+	Removed   int
+	Modified  int
+	ByType    []htmlCount
+	Sections  []*htmlSection
+	Identical bool
+}
+
+type htmlCount struct {
+	ObjectType string
+	Added      int
+	Removed    int
+	Modified   int
+}
+
+// htmlSection collects the differences of one table, or of one kind of
+// schema-level object such as views
+type htmlSection struct {
+	Title       string
+	Differences []htmlDifference
+}
+
+type htmlDifference struct {
+	Change      string
+	Marker      string
+	ObjectType  string
+	ObjectName  string
+	Description string
+	Search      string
+	Attributes  []DetailAttribute
+	Bodies      []DetailBody
+	Extra       string
+}
+
+func (f *Formatter) formatHTML(result *models.ComparisonResult) ([]byte, error) {
+	report := &htmlReport{
+		Source:    result.SourceDatabase,
+		Target:    result.TargetDatabase,
+		Time:      result.ComparisonTime.Format("2006-01-02 15:04:05"),
+		Total:     len(result.Differences),
+		Identical: len(result.Differences) == 0,
+	}
+
+	counts := make(map[string]*htmlCount)
+	sections := make(map[string]*htmlSection)
+	for _, diff := range result.Differences {
+		count, ok := counts[diff.ObjectType]
+		if !ok {
+			count = &htmlCount{ObjectType: diff.ObjectType}
+			counts[diff.ObjectType] = count
+		}
+		switch diff.Type {
+		case models.Added:
+			report.Added++
+			count.Added++
+		case models.Removed:
+			report.Removed++
+			count.Removed++
+		case models.Modified:
+			report.Modified++
+			count.Modified++
+		}
+
+		title := sectionTitle(diff)
+		section, ok := sections[title]
+		if !ok {
+			section = &htmlSection{Title: title}
+			sections[title] = section
+			report.Sections = append(report.Sections, section)
+		}
+		section.Differences = append(section.Differences, newHTMLDifference(diff))
+	}
+
+	for _, count := range counts {
+		report.ByType = append(report.ByType, *count)
+	}
+	sort.Slice(report.ByType, func(i, j int) bool { return report.ByType[i].ObjectType < report.ByType[j].ObjectType })
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sectionTitle groups table-level objects under their table and everything
+// else by object type
+func sectionTitle(diff models.Difference) string {
+	owner := ownerOf(diff)
+	title := plural(owner.ObjectType)
+	if owner.ObjectType == "Table" {
+		title = "Table " + owner.Identity.Name
+	}
+	if owner.Identity.Schema != "" {
+		title = owner.Identity.Schema + ": " + title
+	}
+	return title
+}
+
+func plural(objectType string) string {
+	if strings.HasSuffix(objectType, "x") {
+		return objectType + "es"
+	}
+	return objectType + "s"
+}
+
+func newHTMLDifference(diff models.Difference) htmlDifference {
+	out := htmlDifference{
+		Change:      strings.ToLower(string(diff.Type)),
+		Marker:      marker(diff.Type),
+		ObjectType:  diff.ObjectType,
+		ObjectName:  diff.ObjectName,
+		Description: diff.Description,
+		Extra:       strings.TrimSpace(formatConstraintActions(diff)),
+	}
+	out.Search = strings.ToLower(strings.Join([]string{diff.ObjectType, diff.ObjectName, diff.Description, string(diff.Type)}, " "))
+
+	detail := Describe(diff)
+	out.Attributes, out.Bodies = detail.Attributes, detail.Bodies
+	return out
+}
+
+func marker(t models.DifferenceType) string {
+	switch t {
+	case models.Added:
+		return "+"
+	case models.Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// attributes flattens a compared object into display strings keyed by field
+// name, in field order. Results read back from JSON or YAML hold maps
+// rather than structs; their keys are sorted.
+func attributes(value interface{}) (map[string]string, []string) {
+	values := make(map[string]string)
+	if value == nil {
+		return values, nil
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return values, nil
+		}
+		v = v.Elem()
+	}
+
+	var names []string
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			names = append(names, field.Name)
+			values[field.Name] = displayValue(v.Field(i).Interface())
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			names = append(names, name)
+			values[name] = displayValue(v.MapIndex(key).Interface())
+		}
+		sort.Strings(names)
+	default:
+		names = []string{"Value"}
+		values["Value"] = displayValue(v.Interface())
+	}
+	return values, names
+}
+
+// displayValue renders a field for the attribute table, leaving zero values
+// empty so fields unset on both sides can be skipped
+func displayValue(value interface{}) string {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.IsZero() {
+		return ""
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if v.Kind() != reflect.Struct && v.Len() == 0 {
+			return ""
+		}
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// diffLines returns a line diff of two texts based on their longest common
+// subsequence of lines
+func diffLines(source, target string) []DiffLine {
+	a := strings.Split(source, "\n")
+	b := strings.Split(target, "\n")
+	if source == "" {
+		a = nil
+	}
+	if target == "" {
+		b = nil
+	}
+
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		var lines []DiffLine
+		for _, line := range a {
+			lines = append(lines, DiffLine{Kind: "del", Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{Kind: "add", Text: line})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Kind: "same", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Kind: "del", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Kind: "add", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Kind: "del", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Kind: "add", Text: b[j]})
+	}
+	return lines
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Schema Comparison Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #1f2328; background: #f6f8fa; }
+header { background: #24292f; color: #fff; padding: 16px 24px; }
+header h1 { margin: 0 0 8px; font-size: 20px; }
+header dl { display: grid; grid-template-columns: max-content 1fr; gap: 2px 12px; margin: 0; font-size: 13px; }
+header dt { color: #8c959f; }
+header dd { margin: 0; word-break: break-all; }
+main { padding: 16px 24px; }
+.cards { display: flex; gap: 12px; flex-wrap: wrap; margin-bottom: 16px; }
+.card { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 12px 16px; min-width: 120px; }
+.card .n { font-size: 28px; font-weight: 600; }
+.card.added .n { color: #1a7f37; }
+.card.removed .n { color: #cf222e; }
+.card.modified .n { color: #9a6700; }
+table { border-collapse: collapse; background: #fff; font-size: 13px; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.bytype { margin-bottom: 16px; }
+.toolbar { position: sticky; top: 0; background: #f6f8fa; padding: 8px 0; display: flex; gap: 12px; align-items: center; flex-wrap: wrap; z-index: 1; }
+.toolbar input[type=search] { flex: 1; min-width: 240px; padding: 6px 8px; font-size: 14px; border: 1px solid #d0d7de; border-radius: 6px; }
+details.section { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; margin-bottom: 8px; }
+details.section > summary { padding: 8px 12px; cursor: pointer; font-weight: 600; }
+details.section > summary .count { color: #57606a; font-weight: normal; }
+.diff { border-top: 1px solid #d0d7de; padding: 8px 12px; }
+.diff h3 { margin: 0 0 4px; font-size: 14px; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+.diff .desc { color: #57606a; margin: 0 0 8px; font-size: 13px; }
+.marker { display: inline-block; width: 1.2em; font-weight: 700; }
+.added .marker { color: #1a7f37; }
+.removed .marker { color: #cf222e; }
+.modified .marker { color: #9a6700; }
+.attrs td { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; white-space: pre-wrap; word-break: break-word; max-width: 520px; }
+.attrs tr.changed td { background: #fff8c5; }
+.body { margin: 8px 0; }
+.body h4 { margin: 0 0 4px; font-size: 13px; }
+.body pre { margin: 0; background: #fff; border: 1px solid #d0d7de; border-radius: 6px; overflow-x: auto; font-size: 12px; }
+.body pre span { display: block; padding: 0 8px; white-space: pre; }
+.body .add { background: #dafbe1; }
+.body .add::before { content: "+ "; }
+.body .del { background: #ffebe9; }
+.body .del::before { content: "- "; }
+.body .same::before { content: "  "; }
+.empty { color: #57606a; }
+[hidden] { display: none !important; }
+</style>
+</head>
+<body>
+<header>
+<h1>Schema Comparison Report</h1>
+<dl>
+<dt>Source</dt><dd>{{.Source}}</dd>
+<dt>Target</dt><dd>{{.Target}}</dd>
+<dt>Compared</dt><dd>{{.Time}}</dd>
+</dl>
+</header>
+<main>
+<div class="cards">
+<div class="card"><div class="n">{{.Total}}</div>differences</div>
+<div class="card added"><div class="n">{{.Added}}</div>added</div>
+<div class="card removed"><div class="n">{{.Removed}}</div>removed</div>
+<div class="card modified"><div class="n">{{.Modified}}</div>modified</div>
+</div>
+{{if .Identical}}<p class="empty">No differences found. The schemas are identical.</p>{{else}}
+<table class="bytype">
+<tr><th>Object type</th><th>Added</th><th>Removed</th><th>Modified</th></tr>
+{{range .ByType}}<tr><td>{{.ObjectType}}</td><td>{{.Added}}</td><td>{{.Removed}}</td><td>{{.Modified}}</td></tr>
+{{end}}</table>
+<div class="toolbar">
+<input type="search" id="filter" placeholder="Filter by object name, type or description" autocomplete="off">
+<label><input type="checkbox" class="change" value="added" checked> added</label>
+<label><input type="checkbox" class="change" value="removed" checked> removed</label>
+<label><input type="checkbox" class="change" value="modified" checked> modified</label>
+<button type="button" id="expand">Expand all</button>
+<button type="button" id="collapse">Collapse all</button>
+<span id="shown"></span>
+</div>
+{{range .Sections}}<details class="section">
+<summary>{{.Title}} <span class="count">({{len .Differences}})</span></summary>
+{{range .Differences}}<div class="diff {{.Change}}" data-change="{{.Change}}" data-search="{{.Search}}">
+<h3><span class="marker">{{.Marker}}</span>{{.ObjectType}}: {{.ObjectName}}</h3>
+<p class="desc">{{.Description}}{{if .Extra}} · {{.Extra}}{{end}}</p>
+{{if .Attributes}}<table class="attrs">
+<tr><th>Attribute</th><th>Source</th><th>Target</th></tr>
+{{range .Attributes}}<tr{{if .Changed}} class="changed"{{end}}><th>{{.Name}}</th><td>{{.Source}}</td><td>{{.Target}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Bodies}}<div class="body"><h4>{{.Name}}</h4><pre>{{range .Lines}}<span class="{{.Kind}}">{{.Text}}</span>{{end}}</pre></div>
+{{end}}</div>
+{{end}}</details>
+{{end}}{{end}}
+</main>
+<script>
+(function () {
+  var filter = document.getElementById("filter");
+  if (!filter) { return; }
+  var boxes = document.querySelectorAll("input.change");
+  var sections = document.querySelectorAll("details.section");
+  var shown = document.getElementById("shown");
+
+  function apply() {
+    var query = filter.value.trim().toLowerCase();
+    var changes = {};
+    boxes.forEach(function (box) { changes[box.value] = box.checked; });
+    var total = 0;
+    sections.forEach(function (section) {
+      var visible = 0;
+      section.querySelectorAll(".diff").forEach(function (diff) {
+        var match = changes[diff.dataset.change] && (query === "" || diff.dataset.search.indexOf(query) !== -1);
+        diff.hidden = !match;
+        if (match) { visible++; }
+      });
+      section.hidden = visible === 0;
+      if (query !== "" && visible > 0) { section.open = true; }
+      total += visible;
+    });
+    shown.textContent = total + " shown";
+  }
+
+  filter.addEventListener("input", apply);
+  boxes.forEach(function (box) { box.addEventListener("change", apply); });
+  document.getElementById("expand").addEventListener("click", function () {
+    sections.forEach(function (section) { section.open = true; });
+  });
+  document.getElementById("collapse").addEventListener("click", function () {
+    sections.forEach(function (section) { section.open = false; });
+  });
+  apply();
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,193 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// formatJUnit reports one test case per compared object, grouped into a
+// suite per object type, with a failure for each of its differences.
+// Suites and test cases are in hierarchical order.
+// Objects are taken from the compared schemas when the result has them, so
+// matching objects show up as passing tests; otherwise only objects with
+// differences are listed.
+func (f *Formatter) formatJUnit(result *models.ComparisonResult) ([]byte, error) {
+	var owners []objectOwner
+	cases := make(map[objectOwner]*junitTestCase)
+	add := func(owner objectOwner) *junitTestCase {
+		if c, ok := cases[owner]; ok {
+			return c
+		}
+		c := &junitTestCase{Name: owner.Identity.String(), ClassName: junitClassName(result, owner)}
+		cases[owner] = c
+		owners = append(owners, owner)
+		return c
+	}
+
+	for _, schema := range []*models.Schema{result.SourceSchema, result.TargetSchema} {
+		for _, owner := range schemaObjects(schema) {
+			add(owner)
+		}
+	}
+	for _, diff := range result.Differences {
+		c := add(ownerOf(diff))
+		c.Failures = append(c.Failures, junitFailure{
+			Type:    string(diff.Type),
+			Message: fmt.Sprintf("%s %s: %s", diff.ObjectType, diff.ObjectName, diff.Description),
+			Text:    failureDetails(diff),
+		})
+	}
+
+	sort.SliceStable(owners, func(i, j int) bool {
+		a := models.Difference{ObjectType: owners[i].ObjectType, Identity: owners[i].Identity}
+		b := models.Difference{ObjectType: owners[j].ObjectType, Identity: owners[j].Identity}
+		return models.CompareDifferences(a, b) < 0
+	})
+
+	report := junitTestSuites{Name: "schemalyzer"}
+	suites := make(map[string]int)
+	for _, owner := range owners {
+		name := plural(owner.ObjectType)
+		i, ok := suites[name]
+		if !ok {
+			i = len(report.Suites)
+			suites[name] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: name, Timestamp: junitTimestamp(result)})
+		}
+
+		c := cases[owner]
+		suite := &report.Suites[i]
+		suite.Cases = append(suite.Cases, *c)
+		suite.Tests++
+		report.Tests++
+		if len(c.Failures) > 0 {
+			suite.Failures++
+			report.Failures++
+		}
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func junitClassName(result *models.ComparisonResult, owner objectOwner) string {
+	schema := owner.Identity.Schema
+	if schema == "" && result.TargetSchema != nil {
+		schema = result.TargetSchema.Name
+	}
+	if schema == "" {
+		return "schemalyzer." + plural(owner.ObjectType)
+	}
+	return "schemalyzer." + schema + "." + plural(owner.ObjectType)
+}
+
+func junitTimestamp(result *models.ComparisonResult) string {
+	if result.ComparisonTime.IsZero() {
+		return ""
+	}
+	return result.ComparisonTime.Format("2006-01-02T15:04:05")
+}
+
+// schemaObjects lists the top-level objects of a schema under the same
+// identities the comparer reports them with
+func schemaObjects(schema *models.Schema) []objectOwner {
+	if schema == nil {
+		return nil
+	}
+
+	var owners []objectOwner
+	for _, t := range schema.Tables {
+		owners = append(owners, objectOwner{"Table", models.ObjectIdentity{Name: t.Name}})
+	}
+	for _, v := range schema.Views {
+		owners = append(owners, objectOwner{"View", models.ObjectIdentity{Name: v.Name}})
+	}
+	for _, s := range schema.Sequences {
+		owners = append(owners, objectOwner{"Sequence", models.ObjectIdentity{Name: s.Name}})
+	}
+	for _, p := range schema.Procedures {
+		owners = append(owners, objectOwner{"Procedure", models.ObjectIdentity{Name: p.Name, Signature: models.RoutineSignature(p.Parameters)}})
+	}
+	for _, fn := range schema.Functions {
+		owners = append(owners, objectOwner{"Function", models.ObjectIdentity{Name: fn.Name, Signature: models.RoutineSignature(fn.Parameters)}})
+	}
+	for _, s := range schema.Synonyms {
+		owners = append(owners, objectOwner{"Synonym", models.ObjectIdentity{Name: s.Name}})
+	}
+	for _, e := range schema.Extensions {
+		owners = append(owners, objectOwner{"Extension", models.ObjectIdentity{Name: e.Name}})
+	}
+	for _, e := range schema.Events {
+		owners = append(owners, objectOwner{"Event", models.ObjectIdentity{Name: e.Name}})
+	}
+	return owners
+}
+
+// failureDetails lists what changed between the two sides of a difference
+func failureDetails(diff models.Difference) string {
+	var b strings.Builder
+	b.WriteString(diff.Description)
+	b.WriteString("\n")
+
+	source, sourceNames := attributes(diff.Source)
+	target, targetNames := attributes(diff.Target)
+	switch diff.Type {
+	case models.Added:
+		writeAttributes(&b, "Target", target, targetNames)
+	case models.Removed:
+		writeAttributes(&b, "Source", source, sourceNames)
+	default:
+		for _, name := range sourceNames {
+			if source[name] != target[name] {
+				fmt.Fprintf(&b, "%s: %s -> %s\n", name, source[name], target[name])
+			}
+		}
+	}
+	if extra := formatConstraintActions(diff); extra != "" {
+		b.WriteString(strings.TrimSpace(extra) + "\n")
+	}
+	return b.String()
+}
+
+func writeAttributes(b *strings.Builder, side string, values map[string]string, names []string) {
+	for _, name := range names {
+		if values[name] != "" {
+			fmt.Fprintf(b, "%s %s: %s\n", side, name, values[name])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// DefaultMarkdownMaxBytes keeps markdown reports under the 65536 character
+// limit GitHub puts on pull request comments
+const DefaultMarkdownMaxBytes = 65000
+
+// markdownBodyLines bounds the lines of one body diff in a markdown report
+const markdownBodyLines = 200
+
+// markdownSection is the rendering of one table, or of one kind of
+// schema-level object, kept whole when the report is truncated
+type markdownSection struct {
+	title       string
+	differences int
+	text        string
+}
+
+// formatMarkdown renders a GitHub-flavored report for pull request
+// comments. Sections that would push the report past the size limit are
+// left out whole, and a note says how much was omitted.
+func (f *Formatter) formatMarkdown(result *models.ComparisonResult) ([]byte, error) {
+	var header strings.Builder
+	fmt.Fprintf(&header, "## Schema comparison: `%s` → `%s`\n\n", result.SourceDatabase, result.TargetDatabase)
+	if len(result.Differences) == 0 {
+		header.WriteString("✅ No differences found.\n")
+		return []byte(header.String()), nil
+	}
+
+	summary := f.generateSummary(result)
+	fmt.Fprintf(&header, "**%d differences**: %d added, %d removed, %d modified\n\n",
+		len(result.Differences), summary["added"], summary["removed"], summary["modified"])
+	header.WriteString(markdownSummaryTable(result.Differences))
+
+	// Differences arrive in hierarchical order, so sections do too
+	var sections []markdownSection
+	index := make(map[string][]models.Difference)
+	for _, diff := range result.Differences {
+		title := sectionTitle(diff)
+		if _, ok := index[title]; !ok {
+			sections = append(sections, markdownSection{title: title})
+		}
+		index[title] = append(index[title], diff)
+	}
+	for i := range sections {
+		diffs := index[sections[i].title]
+		sections[i].differences = len(diffs)
+		sections[i].text = markdownSectionText(sections[i].title, diffs)
+	}
+
+	maxBytes := f.maxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMarkdownMaxBytes
+	}
+
+	total := header.Len()
+	for _, section := range sections {
+		total += len(section.text)
+	}
+
+	var out strings.Builder
+	out.WriteString(header.String())
+	if total <= maxBytes {
+		for _, section := range sections {
+			out.WriteString(section.text)
+		}
+		return []byte(out.String()), nil
+	}
+
+	// Keep whole sections while leaving room for the truncation note, sized
+	// for the largest counts it can show
+	note := func(shown, omitted int) string {
+		return fmt.Sprintf("\n> [!WARNING]\n> Report truncated to fit %d bytes: showing %d of %d differences, %d sections omitted. Use `--format html` for the full report.\n",
+			maxBytes, shown, len(result.Differences), omitted)
+	}
+	budget := maxBytes - len(note(len(result.Differences), len(sections)))
+	shown, omitted := 0, 0
+	for _, section := range sections {
+		if out.Len()+len(section.text) > budget {
+			omitted++
+			continue
+		}
+		out.WriteString(section.text)
+		shown += section.differences
+	}
+	out.WriteString(note(shown, omitted))
+	return []byte(out.String()), nil
+}
+
+func markdownSummaryTable(diffs []models.Difference) string {
+	counts := make(map[string]map[models.DifferenceType]int)
+	var types []string
+	for _, diff := range diffs {
+		if _, ok := counts[diff.ObjectType]; !ok {
+			counts[diff.ObjectType] = make(map[models.DifferenceType]int)
+			types = append(types, diff.ObjectType)
+		}
+		counts[diff.ObjectType][diff.Type]++
+	}
+	sort.Strings(types)
+
+	var b strings.Builder
+	b.WriteString("| Object type | Added | Removed | Modified |\n|---|---:|---:|---:|\n")
+	for _, objectType := range types {
+		c := counts[objectType]
+		fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", objectType, c[models.Added], c[models.Removed], c[models.Modified])
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func markdownSectionText(title string, diffs []models.Difference) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", escapeMarkdown(title))
+
+	// Columns get a before/after table; everything else a list
+	var columns, others []models.Difference
+	for _, diff := range diffs {
+		if diff.ObjectType == "Column" {
+			columns = append(columns, diff)
+		} else {
+			others = append(others, diff)
+		}
+	}
+
+	if len(columns) > 0 {
+		b.WriteString("| | Column | Before | After |\n|---|---|---|---|\n")
+		for _, diff := range columns {
+			name := diff.Identity.Name
+			if name == "" {
+				name = diff.ObjectName
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", marker(diff.Type), name, columnCell(diff.Source), columnCell(diff.Target))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, diff := range others {
+		fmt.Fprintf(&b, "- `%s` %s `%s`: %s\n", marker(diff.Type), diff.ObjectType, diff.ObjectName, escapeMarkdown(diff.Description))
+		if extra := strings.TrimSpace(formatConstraintActions(diff)); extra != "" {
+			fmt.Fprintf(&b, "  %s\n", extra)
+		}
+		if diff.Type != models.Modified {
+			continue
+		}
+
+		source, names := attributes(diff.Source)
+		target, _ := attributes(diff.Target)
+		for _, name := range names {
+			s, t := source[name], target[name]
+			if s == t {
+				continue
+			}
+			if bodyFields[name] || strings.Contains(s, "\n") || strings.Contains(t, "\n") {
+				b.WriteString(markdownBodyDiff(name, s, t))
+			} else {
+				fmt.Fprintf(&b, "  - %s: %s → %s\n", name, codeSpan(s), codeSpan(t))
+			}
+		}
+	}
+	if len(others) > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// markdownBodyDiff renders a changed view or routine body as a collapsed
+// diff block
+func markdownBodyDiff(name, source, target string) string {
+	lines := diffLines(source, target)
+
+	// The fence must be longer than any run of backticks in the body
+	fence := "```"
+	for _, line := range lines {
+		for strings.Contains(line.Text, fence) {
+			fence += "`"
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n  <details><summary>%s diff</summary>\n\n  %sdiff\n", name, fence)
+	for i, line := range lines {
+		if i == markdownBodyLines {
+			fmt.Fprintf(&b, "  … %d more lines\n", len(lines)-i)
+			break
+		}
+		prefix := " "
+		switch line.Kind {
+		case "add":
+			prefix = "+"
+		case "del":
+			prefix = "-"
+		}
+		fmt.Fprintf(&b, "  %s%s\n", prefix, line.Text)
+	}
+	fmt.Fprintf(&b, "  %s\n\n  </details>\n\n", fence)
+	return b.String()
+}
+
+// columnCell summarizes a column as its type, nullability and default
+func columnCell(value interface{}) string {
+	var column *models.Column
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *models.Column:
+		column = v
+	case models.Column:
+		column = &v
+	default:
+		values, _ := attributes(value)
+		return codeSpan(values["DataType"])
+	}
+	if column == nil {
+		return ""
+	}
+
+	parts := []string{codeSpan(column.DataType)}
+	if !column.IsNullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if column.DefaultValue != nil {
+		parts = append(parts, "DEFAULT "+codeSpan(*column.DefaultValue))
+	}
+	return strings.Join(parts, " ")
+}
+
+// codeSpan renders a value as inline code that is safe in a table cell
+func codeSpan(value string) string {
+	if value == "" {
+		return "_none_"
+	}
+	value = strings.ReplaceAll(value, "|", `\|`)
+	value = strings.Join(strings.Fields(value), " ")
+	if strings.Contains(value, "`") {
+		return "`` " + value + " ``"
+	}
+	return "`" + value + "`"
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;", "*", `\*`, "_", `\_`)
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "schemalyzer"
+	toolURI      = "https://github.com/nechja/schemalyzer"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	// PartialFingerprints lets code scanning track a difference across runs
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel ranks a difference by how likely it is to break clients of
+// the target: objects missing from it are errors, changed ones warnings
+// and extra ones notes
+func sarifLevel(t models.DifferenceType) string {
+	switch t {
+	case models.Removed:
+		return "error"
+	case models.Modified:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifRuleID names the rule for one kind of difference, e.g. column/removed
+func sarifRuleID(diff models.Difference) string {
+	kind := strings.ToLower(strings.ReplaceAll(diff.ObjectType, " ", "-"))
+	return kind + "/" + strings.ToLower(string(diff.Type))
+}
+
+// formatSARIF reports one result per difference, with a rule for each
+// combination of object type and change. When the source of the comparison
+// is a schema file, such as a golden file, results point at it so code
+// scanning can attach them to the repository.
+func (f *Formatter) formatSARIF(result *models.ComparisonResult) ([]byte, error) {
+	rules := make(map[string]sarifRule)
+	for _, diff := range result.Differences {
+		id := sarifRuleID(diff)
+		if _, ok := rules[id]; !ok {
+			change := strings.ToLower(string(diff.Type))
+			rules[id] = sarifRule{
+				ID:                   id,
+				Name:                 strings.ReplaceAll(diff.ObjectType, " ", "") + strings.ToUpper(change[:1]) + change[1:],
+				ShortDescription:     sarifMessage{Text: fmt.Sprintf("%s %s", diff.ObjectType, change)},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(diff.Type)},
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	driver := sarifDriver{Name: toolName, InformationURI: toolURI, Rules: []sarifRule{}}
+	for i, id := range ids {
+		index[id] = i
+		driver.Rules = append(driver.Rules, rules[id])
+	}
+
+	var physical *sarifPhysicalLocation
+	if isSchemaFile(result.SourceDatabase) {
+		physical = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(result.SourceDatabase)}}
+	}
+
+	results := []sarifResult{}
+	for _, diff := range result.Differences {
+		id := sarifRuleID(diff)
+		shortName, name := diff.Identity.Name, diff.Identity.String()
+		if shortName == "" {
+			// Results loaded from older exports carry no identity
+			shortName, name = diff.ObjectName, diff.ObjectName
+		}
+		results = append(results, sarifResult{
+			RuleID:    id,
+			RuleIndex: index[id],
+			Level:     sarifLevel(diff.Type),
+			Message:   sarifMessage{Text: fmt.Sprintf("%s %s: %s", diff.ObjectType, diff.ObjectName, diff.Description)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: physical,
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               shortName,
+					FullyQualifiedName: name,
+					Kind:               strings.ToLower(diff.ObjectType),
+				}},
+			}},
+			PartialFingerprints: map[string]string{"schemaObject/v1": id + ":" + name},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// isSchemaFile reports whether a comparison side names a schema export
+// rather than a database
+func isSchemaFile(name string) bool {
+	if strings.Contains(name, "://") {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// NewComparisonView builds the template view of a comparison result
+func NewComparisonView(result *models.ComparisonResult) *models.ComparisonView {
+	view := &models.ComparisonView{
+		Source:       result.SourceDatabase,
+		Target:       result.TargetDatabase,
+		Time:         result.ComparisonTime,
+		Total:        len(result.Differences),
+		SourceSchema: result.SourceSchema,
+		TargetSchema: result.TargetSchema,
+		Differences:  make([]models.DifferenceView, 0, len(result.Differences)),
+	}
+
+	for _, diff := range sortedDifferences(result.Differences) {
+		switch diff.Type {
+		case models.Added:
+			view.Added++
+		case models.Removed:
+			view.Removed++
+		case models.Modified:
+			view.Modified++
+		}
+
+		d := models.DifferenceView{
+			Change:      strings.ToLower(string(diff.Type)),
+			ObjectType:  diff.ObjectType,
+			Name:        diff.ObjectName,
+			Identity:    diff.Identity,
+			Section:     sectionTitle(diff),
+			Description: diff.Description,
+			Source:      diff.Source,
+			Target:      diff.Target,
+		}
+		if owner := ownerOf(diff); owner.ObjectType == "Table" {
+			d.Table = owner.Identity.Name
+		}
+		if diff.Type == models.Modified {
+			source, names := attributes(diff.Source)
+			target, _ := attributes(diff.Target)
+			for _, name := range names {
+				if source[name] != target[name] {
+					d.Changes = append(d.Changes, models.AttributeChange{Name: name, Before: source[name], After: target[name]})
+				}
+			}
+		}
+		view.Differences = append(view.Differences, d)
+	}
+	return view
+}
+
+// Template is a user-defined report layout. Files named *.html or *.htm
+// (optionally followed by .tmpl) are parsed with html/template, which
+// escapes values for HTML; everything else with text/template.
+type Template struct {
+	name    string
+	execute func(w io.Writer, data interface{}) error
+}
+
+// ParseTemplate reads a template file
+func ParseTemplate(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	name := filepath.Base(path)
+	t := &Template{name: name}
+	if isHTMLTemplate(name) {
+		tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		t.execute = tmpl.Execute
+	} else {
+		tmpl, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(templateFuncs)).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		t.execute = tmpl.Execute
+	}
+	return t, nil
+}
+
+func isHTMLTemplate(name string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range []string{".tmpl", ".gotmpl", ".tpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	switch filepath.Ext(name) {
+	case ".html", ".htm":
+		return true
+	}
+	return false
+}
+
+// ExecuteComparison renders a comparison result through the template
+func (t *Template) ExecuteComparison(result *models.ComparisonResult) ([]byte, error) {
+	return t.run(NewComparisonView(result))
+}
+
+// ExecuteSchema renders a schema through the template
+func (t *Template) ExecuteSchema(schema *models.Schema) ([]byte, error) {
+	return t.run(&models.SchemaView{Schema: schema, Generated: time.Now()})
+}
+
+func (t *Template) run(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", t.name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// templateFuncs are the helpers available to every template
+var templateFuncs = map[string]interface{}{
+	"groupBy":     groupBy,
+	"where":       where,
+	"formatType":  formatType,
+	"marker":      func(change string) string { return marker(models.DifferenceType(strings.ToUpper(change))) },
+	"primaryKey":  primaryKey,
+	"foreignKeys": foreignKeys,
+	"json":        toJSON,
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+	"title":       titleCase,
+	"join":        strings.Join,
+	"contains":    strings.Contains,
+	"hasPrefix":   strings.HasPrefix,
+	"hasSuffix":   strings.HasSuffix,
+	"replace":     strings.ReplaceAll,
+	"trim":        strings.TrimSpace,
+	"repeat":      strings.Repeat,
+	"add":         func(a, b int) int { return a + b },
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// differenceKey returns the value of a difference that groupBy and where
+// select on
+func differenceKey(field string, d models.DifferenceView) (string, error) {
+	switch field {
+	case "section":
+		return d.Section, nil
+	case "table":
+		return d.Table, nil
+	case "type":
+		return d.ObjectType, nil
+	case "change":
+		return d.Change, nil
+	case "schema":
+		return d.Identity.Schema, nil
+	}
+	return "", fmt.Errorf("unknown field %q: use section, table, type, change or schema", field)
+}
+
+// groupBy groups differences by section, table, type, change or schema,
+// ordered by key
+func groupBy(field string, diffs []models.DifferenceView) ([]models.DifferenceGroup, error) {
+	index := make(map[string]int)
+	var groups []models.DifferenceGroup
+	for _, d := range diffs {
+		key, err := differenceKey(field, d)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, models.DifferenceGroup{Key: key})
+		}
+		groups[i].Differences = append(groups[i].Differences, d)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups, nil
+}
+
+// where keeps the differences whose field equals one of the values,
+// ignoring case
+func where(field string, values string, diffs []models.DifferenceView) ([]models.DifferenceView, error) {
+	wanted := make(map[string]bool)
+	for _, value := range strings.Split(values, ",") {
+		wanted[strings.ToLower(strings.TrimSpace(value))] = true
+	}
+
+	var kept []models.DifferenceView
+	for _, d := range diffs {
+		key, err := differenceKey(field, d)
+		if err != nil {
+			return nil, err
+		}
+		if wanted[strings.ToLower(key)] {
+			kept = append(kept, d)
+		}
+	}
+	return kept, nil
+}
+
+// formatType renders a column as its type, nullability and default, e.g.
+// "varchar(255) NOT NULL DEFAULT 'x'". It accepts a column or a
+// difference's Source or Target.
+func formatType(value interface{}) string {
+	var column *models.Column
+	switch v := value.(type) {
+	case *models.Column:
+		column = v
+	case models.Column:
+		column = &v
+	default:
+		values, _ := attributes(value)
+		return values["DataType"]
+	}
+	if column == nil {
+		return ""
+	}
+
+	parts := []string{column.DataType}
+	if !column.IsNullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if column.DefaultValue != nil {
+		parts = append(parts, "DEFAULT "+*column.DefaultValue)
+	}
+	return strings.Join(parts, " ")
+}
+
+// primaryKey returns the primary key columns of a table
+func primaryKey(table models.Table) []string {
+	for _, constraint := range table.Constraints {
+		if constraint.Type == models.PrimaryKey {
+			return constraint.Columns
+		}
+	}
+	var columns []string
+	for _, column := range table.Columns {
+		if column.IsPrimaryKey {
+			columns = append(columns, column.Name)
+		}
+	}
+	return columns
+}
+
+// foreignKeys returns the foreign key constraints of a table
+func foreignKeys(table models.Table) []models.Constraint {
+	var keys []models.Constraint
+	for _, constraint := range table.Constraints {
+		if constraint.Type == models.ForeignKey {
+			keys = append(keys, constraint)
+		}
+	}
+	return keys
+}
+
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
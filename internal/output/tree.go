@@ -0,0 +1,111 @@
+package output
+
+import (
+	"encoding/json"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TreeNode is one object in the tree-shaped JSON and YAML formats. Schemas
+// contain their objects and tables contain their columns, constraints,
+// indexes and other members; Differences are the changes to the object
+// itself.
+type TreeNode struct {
+	ObjectType  string              `json:"object_type" yaml:"object_type"`
+	Name        string              `json:"name" yaml:"name"`
+	Signature   string              `json:"signature,omitempty" yaml:"signature,omitempty"`
+	Differences []models.Difference `json:"differences,omitempty" yaml:"differences,omitempty"`
+	Children    []*TreeNode         `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+type treeReport struct {
+	SourceDatabase   string         `json:"source_database" yaml:"source_database"`
+	TargetDatabase   string         `json:"target_database" yaml:"target_database"`
+	ComparisonTime   string         `json:"comparison_time" yaml:"comparison_time"`
+	TotalDifferences int            `json:"total_differences" yaml:"total_differences"`
+	Summary          map[string]int `json:"summary" yaml:"summary"`
+	Objects          []*TreeNode    `json:"objects" yaml:"objects"`
+}
+
+// treeBuilder files differences under their schema, owning object and
+// member, creating each node the first time it is needed so nodes keep the
+// order of the sorted differences
+type treeBuilder struct {
+	roots []*TreeNode
+	nodes map[treeKey]*TreeNode
+}
+
+type treeKey struct {
+	parent     *TreeNode
+	objectType string
+	name       string
+	signature  string
+}
+
+func (b *treeBuilder) node(parent *TreeNode, objectType, name, signature string) *TreeNode {
+	key := treeKey{parent, objectType, name, signature}
+	if n, ok := b.nodes[key]; ok {
+		return n
+	}
+	n := &TreeNode{ObjectType: objectType, Name: name, Signature: signature}
+	b.nodes[key] = n
+	if parent == nil {
+		b.roots = append(b.roots, n)
+	} else {
+		parent.Children = append(parent.Children, n)
+	}
+	return n
+}
+
+// BuildTree nests the differences of a result under the objects they
+// belong to, in hierarchical order
+func BuildTree(result *models.ComparisonResult) []*TreeNode {
+	diffs := sortedDifferences(result.Differences)
+	b := &treeBuilder{nodes: make(map[treeKey]*TreeNode)}
+	for _, diff := range diffs {
+		if diff.ObjectType == "Schema" {
+			name := diff.Identity.Name
+			if name == "" {
+				name = diff.ObjectName
+			}
+			n := b.node(nil, "Schema", name, "")
+			n.Differences = append(n.Differences, diff)
+			continue
+		}
+
+		var parent *TreeNode
+		owner := ownerOf(diff)
+		if owner.Identity.Schema != "" {
+			parent = b.node(nil, "Schema", owner.Identity.Schema, "")
+		}
+		n := b.node(parent, owner.ObjectType, owner.Identity.Name, owner.Identity.Signature)
+		if diff.Identity.Table != "" {
+			n = b.node(n, diff.ObjectType, diff.Identity.Name, diff.Identity.Signature)
+		}
+		n.Differences = append(n.Differences, diff)
+	}
+	if b.roots == nil {
+		return []*TreeNode{}
+	}
+	return b.roots
+}
+
+func (f *Formatter) newTreeReport(result *models.ComparisonResult) treeReport {
+	return treeReport{
+		SourceDatabase:   result.SourceDatabase,
+		TargetDatabase:   result.TargetDatabase,
+		ComparisonTime:   result.ComparisonTime.Format("2006-01-02T15:04:05Z"),
+		TotalDifferences: len(result.Differences),
+		Summary:          f.generateSummary(result),
+		Objects:          BuildTree(result),
+	}
+}
+
+func (f *Formatter) formatJSONTree(result *models.ComparisonResult) ([]byte, error) {
+	return json.MarshalIndent(f.newTreeReport(result), "", "  ")
+}
+
+func (f *Formatter) formatYAMLTree(result *models.ComparisonResult) ([]byte, error) {
+	return yaml.Marshal(f.newTreeReport(result))
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,21 +19,36 @@ func NewLoader() *Loader {
 }
 
 func (l *Loader) LoadFromFile(path string) (*models.Schema, error) {
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
-	defer file.Close()
 	
+	var schema *models.Schema
+	db := &models.Database{}
 	ext := filepath.Ext(path)
 	switch ext {
 	case ".json":
-		return l.LoadFromJSON(file)
+		if schema, err = l.LoadFromJSON(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(data, db)
 	case ".yaml", ".yml":
-		return l.LoadFromYAML(file)
+		if schema, err = l.LoadFromYAML(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		_ = yaml.Unmarshal(data, db)
 	default:
 		return nil, fmt.Errorf("unsupported file extension: %s", ext)
 	}
+	
+	// A database snapshot decodes without error as an empty schema, which
+	// would report every live object as extra
+	if len(schema.Tables) == 0 && len(db.Schemas) > 0 {
+		return nil, fmt.Errorf("the file is a database snapshot, not a single schema; use it with --schemas")
+	}
+	
+	return schema, nil
 }
 
 func (l *Loader) LoadFromJSON(reader io.Reader) (*models.Schema, error) {
@@ -94,4 +110,63 @@ func (l *Loader) SaveToYAML(schema *models.Schema, writer io.Writer) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// LoadDatabaseFromFile loads a multi-schema snapshot written by SaveDatabaseToFile
+func (l *Loader) LoadDatabaseFromFile(path string) (*models.Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	
+	db := &models.Database{}
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".json":
+		if err := json.NewDecoder(file).Decode(db); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.NewDecoder(file).Decode(db); err != nil {
+			return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %s", ext)
+	}
+	
+	// A single-schema snapshot decodes without error but has no schemas,
+	// which would report every live schema as added
+	if len(db.Schemas) == 0 || db.DatabaseType == "" {
+		return nil, fmt.Errorf("the file is not a database snapshot; export one with --schemas")
+	}
+	
+	return db, nil
+}
+
+// SaveDatabaseToFile writes a multi-schema snapshot as JSON or YAML
+func (l *Loader) SaveDatabaseToFile(db *models.Database, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+	
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".json":
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(db); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.NewEncoder(file).Encode(db); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported file extension: %s", ext)
+	}
+	
+	return nil
+}
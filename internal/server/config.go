@@ -0,0 +1,99 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults applied by LoadConfig to settings left out of the file
+const (
+	DefaultListen         = ":8080"
+	DefaultRequestTimeout = time.Minute
+	DefaultMaxUploadBytes = 32 << 20
+)
+
+// Config is the server-side configuration. Clients refer to databases by
+// connection name and never see or send connection strings.
+type Config struct {
+	// Listen is the address the server listens on
+	Listen string `yaml:"listen"`
+	// RequestTimeout bounds every request, including its catalog queries
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// MaxUploadBytes limits the size of uploaded snapshots
+	MaxUploadBytes int64                 `yaml:"max_upload_bytes"`
+	Connections    map[string]Connection `yaml:"connections"`
+}
+
+// Connection is a named database the API can read
+type Connection struct {
+	Type string `yaml:"type"`
+	Conn string `yaml:"conn"`
+}
+
+// LoadConfig reads a server configuration file, expanding environment
+// variables in connection strings
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server config: %w", err)
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse server config: %w", err)
+	}
+	for name, connection := range config.Connections {
+		connection.Conn = os.ExpandEnv(connection.Conn)
+		config.Connections[name] = connection
+	}
+
+	config.applyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Listen == "" {
+		c.Listen = DefaultListen
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = DefaultRequestTimeout
+	}
+	if c.MaxUploadBytes == 0 {
+		c.MaxUploadBytes = DefaultMaxUploadBytes
+	}
+}
+
+// Validate reports connections with missing settings
+func (c *Config) Validate() error {
+	var errs []error
+	if c.RequestTimeout < 0 || c.MaxUploadBytes < 0 {
+		errs = append(errs, errors.New("request_timeout and max_upload_bytes must not be negative"))
+	}
+	for _, name := range c.connectionNames() {
+		if connection := c.Connections[name]; connection.Type == "" || connection.Conn == "" {
+			errs = append(errs, fmt.Errorf("connection %s: type and conn are required", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid server config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (c *Config) connectionNames() []string {
+	names := make([]string, 0, len(c.Connections))
+	for name := range c.Connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,412 @@
+// Package server exposes schema reading, fingerprinting, comparison and
+// documentation over a JSON HTTP API.
+//
+// Databases are addressed by the connection names in the server's
+// configuration; connection strings never leave the server.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"gopkg.in/yaml.v3"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// OpenFunc connects a reader to a configured database
+type OpenFunc func(ctx context.Context, dbType, conn string) (schemalyzer.Reader, error)
+
+// Server serves the API for one configuration
+type Server struct {
+	config *Config
+	open   OpenFunc
+	mux    *http.ServeMux
+}
+
+// New returns a server for the config's connections, connecting with open
+func New(config *Config, open OpenFunc) *Server {
+	s := &Server{config: config, open: open, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /api/v1/connections", s.handleConnections)
+	s.mux.HandleFunc("GET /api/v1/connections/{connection}/schemas", s.handleSchemas)
+	s.mux.HandleFunc("GET /api/v1/connections/{connection}/schemas/{schema}", s.handleExport)
+	s.mux.HandleFunc("GET /api/v1/connections/{connection}/schemas/{schema}/fingerprint", s.handleFingerprint)
+	s.mux.HandleFunc("GET /api/v1/connections/{connection}/schemas/{schema}/docs", s.handleDocs)
+	s.mux.HandleFunc("POST /api/v1/compare", s.handleCompare)
+	return s
+}
+
+// Handler returns the API handler, bounding every request by the
+// configured timeout
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), s.config.RequestTimeout)
+		defer cancel()
+		s.mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ListenAndServe serves the API until ctx is cancelled, then lets
+// in-flight requests finish
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.config.Listen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// connectionInfo is what the API reveals about a configured connection
+type connectionInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// schemaRef names the schema of one side of a comparison: either a
+// configured connection and schema, or an uploaded snapshot
+type schemaRef struct {
+	Connection string         `json:"connection,omitempty"`
+	Schema     string         `json:"schema,omitempty"`
+	Snapshot   *models.Schema `json:"snapshot,omitempty"`
+}
+
+type compareRequest struct {
+	Source     schemaRef `json:"source"`
+	Target     schemaRef `json:"target"`
+	Ignore     []string  `json:"ignore,omitempty"`
+	TablesOnly bool      `json:"tables_only,omitempty"`
+	// Format is a comparison output format; json when empty
+	Format string `json:"format,omitempty"`
+}
+
+// requestError is an error with the HTTP status it should be reported as
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
+	connections := []connectionInfo{}
+	for _, name := range s.config.connectionNames() {
+		connections = append(connections, connectionInfo{Name: name, Type: s.config.Connections[name].Type})
+	}
+	writeJSON(w, http.StatusOK, connections)
+}
+
+func (s *Server) handleSchemas(w http.ResponseWriter, r *http.Request) {
+	reader, err := s.connect(r.Context(), r.PathValue("connection"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer reader.Close()
+
+	schemas, err := schemalyzer.ListSchemas(r.Context(), reader)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if schemas == nil {
+		schemas = []string{}
+	}
+	writeJSON(w, http.StatusOK, schemas)
+}
+
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "yaml" {
+		writeError(w, badRequest("unsupported snapshot format %q (want json or yaml)", format))
+		return
+	}
+
+	schema, err := s.readSchema(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if format == "yaml" {
+		data, err := yaml.Marshal(schema)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(data)
+		return
+	}
+	writeJSON(w, http.StatusOK, schema)
+}
+
+func (s *Server) handleFingerprint(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	options := schemalyzer.FingerprintOptions{
+		TablesOnly: queryBool(query.Get("tables_only")),
+		Hash: schemalyzer.HashOptions{
+			IncludeComments:   queryBool(query.Get("include_comments")),
+			IgnoreColumnOrder: queryBool(query.Get("ignore_column_order")),
+			Bodies:            schemalyzer.BodyMode(query.Get("bodies")),
+			IncludeStatistics: queryBool(query.Get("include_stats")),
+		},
+	}
+	if err := options.Hash.Validate(); err != nil {
+		writeError(w, badRequest("%v", err))
+		return
+	}
+
+	reader, err := s.connect(r.Context(), r.PathValue("connection"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer reader.Close()
+
+	schema, err := schemalyzer.ReadSchema(r.Context(), reader, r.PathValue("schema"), schemalyzer.ReadOptions{TablesOnly: options.TablesOnly})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if options.Hash.IncludeStatistics {
+		if err := schemalyzer.CollectStatistics(r.Context(), reader, schema, schemalyzer.StatisticsOptions{RowCounts: true}); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	fingerprint, err := schemalyzer.Fingerprint(schema, options)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"connection":  r.PathValue("connection"),
+		"schema":      r.PathValue("schema"),
+		"fingerprint": fingerprint,
+		"options":     options.Hash.String(),
+		"tables_only": options.TablesOnly,
+	})
+}
+
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = string(schemalyzer.DocumentMarkdown)
+	}
+	format, err := schemalyzer.ParseDocumentFormat(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// A site is a directory of pages, which a single response cannot carry
+	if format == schemalyzer.DocumentHTMLSite {
+		writeError(w, fmt.Errorf("%w: %s", schemalyzer.ErrUnsupportedFormat, name))
+		return
+	}
+
+	schema, err := s.readSchema(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	content, err := schemalyzer.GenerateDocs(schema, schemalyzer.DocumentOptions{Format: format})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if format == schemalyzer.DocumentMarkdown {
+		contentType = "text/markdown; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write([]byte(content))
+}
+
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	var req compareRequest
+	body := http.MaxBytesReader(w, r.Body, s.config.MaxUploadBytes)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		writeError(w, badRequest("invalid compare request: %w", err))
+		return
+	}
+
+	format := schemalyzer.FormatJSON
+	if req.Format != "" {
+		format = schemalyzer.OutputFormat(req.Format)
+	}
+
+	source, err := s.resolve(r.Context(), "source", req.Source)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	target, err := s.resolve(r.Context(), "target", req.Target)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := schemalyzer.Compare(source, target, schemalyzer.CompareOptions{Ignore: req.Ignore, TablesOnly: req.TablesOnly})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result.SourceDatabase = req.Source.describe()
+	result.TargetDatabase = req.Target.describe()
+
+	data, err := schemalyzer.FormatResult(result, format)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch format {
+	case schemalyzer.FormatJSON, schemalyzer.FormatJSONTree:
+		w.Header().Set("Content-Type", "application/json")
+	case schemalyzer.FormatYAML, schemalyzer.FormatYAMLTree:
+		w.Header().Set("Content-Type", "application/yaml")
+	case schemalyzer.FormatHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case schemalyzer.FormatJUnit:
+		w.Header().Set("Content-Type", "application/xml")
+	case schemalyzer.FormatSARIF:
+		w.Header().Set("Content-Type", "application/sarif+json")
+	case schemalyzer.FormatMarkdown:
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	_, _ = w.Write(data)
+}
+
+// connect opens a reader for a configured connection
+func (s *Server) connect(ctx context.Context, name string) (schemalyzer.Reader, error) {
+	connection, ok := s.config.Connections[name]
+	if !ok {
+		return nil, &requestError{status: http.StatusNotFound, err: fmt.Errorf("unknown connection %q", name)}
+	}
+	return s.open(ctx, connection.Type, connection.Conn)
+}
+
+// readSchema reads the schema named by the request path, honouring ?tables_only
+func (s *Server) readSchema(r *http.Request) (*models.Schema, error) {
+	reader, err := s.connect(r.Context(), r.PathValue("connection"))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return schemalyzer.ReadSchema(r.Context(), reader, r.PathValue("schema"), schemalyzer.ReadOptions{
+		TablesOnly: queryBool(r.URL.Query().Get("tables_only")),
+	})
+}
+
+// resolve returns one side of a comparison
+func (s *Server) resolve(ctx context.Context, side string, ref schemaRef) (*models.Schema, error) {
+	switch {
+	case ref.Snapshot != nil && ref.Connection != "":
+		return nil, badRequest("%s: give either a connection or a snapshot, not both", side)
+	case ref.Snapshot != nil:
+		return ref.Snapshot, nil
+	case ref.Connection == "" || ref.Schema == "":
+		return nil, badRequest("%s: connection and schema, or a snapshot, are required", side)
+	}
+
+	reader, err := s.connect(ctx, ref.Connection)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	schema, err := schemalyzer.ReadSchema(ctx, reader, ref.Schema, schemalyzer.ReadOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", side, err)
+	}
+	return schema, nil
+}
+
+func (ref schemaRef) describe() string {
+	if ref.Snapshot != nil {
+		return "snapshot://" + ref.Snapshot.Name
+	}
+	return ref.Connection + "://" + ref.Schema
+}
+
+func queryBool(value string) bool {
+	b, _ := strconv.ParseBool(value)
+	return b
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+// writeError reports an error as {"error": "..."} with a status derived
+// from its type: bad input is 400, an oversized upload 413, an unreachable
+// or failing database 502 and an expired request timeout 504
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	var reqErr *requestError
+	var connectErr *schemalyzer.ConnectError
+	var readErr *schemalyzer.ReadError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+	case errors.As(err, &reqErr):
+		status = reqErr.status
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, schemalyzer.ErrUnsupportedFormat),
+		errors.Is(err, schemalyzer.ErrStatisticsUnsupported):
+		status = http.StatusBadRequest
+	case errors.As(err, &connectErr), errors.As(err, &readErr):
+		status = http.StatusBadGateway
+	}
+
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
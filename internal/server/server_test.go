@@ -0,0 +1,161 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves fixed schemas; a schema named "slow" blocks until the
+// request is cancelled
+type fakeReader struct {
+	schemas map[string]*models.Schema
+}
+
+func (r *fakeReader) Connect(ctx context.Context, connectionString string) error { return nil }
+func (r *fakeReader) Close() error                                               { return nil }
+
+func (r *fakeReader) ListSchemas(ctx context.Context) ([]string, error) {
+	return []string{"app"}, nil
+}
+
+func (r *fakeReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	if schemaName == "slow" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	schema, ok := r.schemas[schemaName]
+	if !ok {
+		return nil, errors.New("schema not found")
+	}
+	return schema, nil
+}
+
+func appSchema(idType string) *models.Schema {
+	return &models.Schema{
+		Name: "app",
+		Tables: []models.Table{
+			{Name: "users", Schema: "app", Columns: []models.Column{{Name: "id", DataType: idType, Position: 1}}},
+		},
+	}
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	config := &Config{
+		RequestTimeout: 200 * time.Millisecond,
+		MaxUploadBytes: 1 << 20,
+		Connections: map[string]Connection{
+			"prod":    {Type: "postgresql", Conn: "postgres://secret@prod/app"},
+			"staging": {Type: "postgresql", Conn: "postgres://secret@staging/app"},
+		},
+	}
+	open := func(ctx context.Context, dbType, conn string) (schemalyzer.Reader, error) {
+		idType := "integer"
+		if strings.Contains(conn, "staging") {
+			idType = "bigint"
+		}
+		return &fakeReader{schemas: map[string]*models.Schema{"app": appSchema(idType)}}, nil
+	}
+
+	server := httptest.NewServer(New(config, open).Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, server *httptest.Server, path string) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestConnectionsHideConnectionStrings(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := get(t, server, "/api/v1/connections")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `[{"name":"prod","type":"postgresql"},{"name":"staging","type":"postgresql"}]`, body)
+	assert.NotContains(t, body, "secret")
+}
+
+func TestReadEndpoints(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := get(t, server, "/api/v1/connections/prod/schemas")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `["app"]`, body)
+
+	resp, body = get(t, server, "/api/v1/connections/prod/schemas/app")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var snapshot models.Schema
+	require.NoError(t, json.Unmarshal([]byte(body), &snapshot))
+	assert.Equal(t, "users", snapshot.Tables[0].Name)
+
+	resp, body = get(t, server, "/api/v1/connections/prod/schemas/app/fingerprint?bodies=normalized")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"fingerprint": "v2:sha256:comments=0,order=1,bodies=normalized,stats=0:`)
+
+	resp, body = get(t, server, "/api/v1/connections/prod/schemas/app/docs?format=mermaid")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "erDiagram")
+}
+
+func TestErrorStatuses(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := map[string]int{
+		"/api/v1/connections/missing/schemas":                        http.StatusNotFound,
+		"/api/v1/connections/prod/schemas/app/docs?format=pdf":       http.StatusBadRequest,
+		"/api/v1/connections/prod/schemas/app/docs?format=html-site": http.StatusBadRequest,
+		"/api/v1/connections/prod/schemas/app/fingerprint?bodies=x":  http.StatusBadRequest,
+		"/api/v1/connections/prod/schemas/nope":                      http.StatusBadGateway,
+		"/api/v1/connections/prod/schemas/slow":                      http.StatusGatewayTimeout,
+	}
+	for path, status := range tests {
+		resp, body := get(t, server, path)
+		assert.Equal(t, status, resp.StatusCode, path)
+		assert.Contains(t, body, `"error"`, path)
+	}
+}
+
+func TestCompareConnectionWithUploadedSnapshot(t *testing.T) {
+	server := newTestServer(t)
+
+	request, err := json.Marshal(map[string]interface{}{
+		"source": map[string]interface{}{"connection": "prod", "schema": "app"},
+		"target": map[string]interface{}{"snapshot": appSchema("bigint")},
+		"format": "summary",
+	})
+	require.NoError(t, err)
+
+	resp, err := http.Post(server.URL+"/api/v1/compare", "application/json", bytes.NewReader(request))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "Source: prod://app")
+	assert.Contains(t, string(body), "Total Differences: 1")
+
+	resp, err = http.Post(server.URL+"/api/v1/compare", "application/json", strings.NewReader(`{"source":{"connection":"prod"}}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,654 @@
+// Package tui is an interactive terminal browser for comparison results.
+//
+// The browser shows the differences as a tree of schemas, tables and their
+// members next to a detail pane with the compared attributes and body
+// diffs. Differences can be filtered by change and object type, and objects
+// can be marked as ignored and written out as ignore patterns for the next
+// comparison.
+//
+// Browser holds all state and renders frames as strings, so it can be
+// driven without a terminal; Run connects it to one.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/internal/output"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// ANSI styles
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleGreen   = "\x1b[32m"
+	styleYellow  = "\x1b[33m"
+	styleCyan    = "\x1b[36m"
+)
+
+var changeTypes = []models.DifferenceType{models.Added, models.Removed, models.Modified}
+
+// row is one visible line of the tree
+type row struct {
+	node  *output.TreeNode
+	depth int
+	// count is the number of differences in the node and beneath it that
+	// pass the filters
+	count int
+}
+
+// Browser is the state of the terminal browser
+type Browser struct {
+	result *models.ComparisonResult
+	roots  []*output.TreeNode
+	parent map[*output.TreeNode]*output.TreeNode
+
+	expanded map[*output.TreeNode]bool
+	ignored  map[*output.TreeNode]bool
+	// unsaved is set when ignore marks changed since they were last written
+	unsaved bool
+
+	// shown filters by change type, objectType by object type ("" for all)
+	shown       map[models.DifferenceType]bool
+	objectTypes []string
+	objectType  string
+
+	rows         []row
+	cursor       int
+	offset       int
+	detailOffset int
+	detailPage   int
+
+	ignoreFile string
+	status     string
+	help       bool
+	quitArmed  bool
+}
+
+// NewBrowser opens a comparison result. Marked objects are written to
+// ignoreFile as ignore patterns.
+func NewBrowser(result *models.ComparisonResult, ignoreFile string) *Browser {
+	b := &Browser{
+		result:     result,
+		roots:      output.BuildTree(result),
+		parent:     make(map[*output.TreeNode]*output.TreeNode),
+		expanded:   make(map[*output.TreeNode]bool),
+		ignored:    make(map[*output.TreeNode]bool),
+		shown:      map[models.DifferenceType]bool{models.Added: true, models.Removed: true, models.Modified: true},
+		ignoreFile: ignoreFile,
+	}
+
+	seen := make(map[string]bool)
+	var walk func(parent *output.TreeNode, nodes []*output.TreeNode)
+	walk = func(parent *output.TreeNode, nodes []*output.TreeNode) {
+		for _, n := range nodes {
+			b.parent[n] = parent
+			// Schemas start open so their tables are listed
+			b.expanded[n] = n.ObjectType == "Schema"
+			for _, diff := range n.Differences {
+				if !seen[diff.ObjectType] {
+					seen[diff.ObjectType] = true
+					b.objectTypes = append(b.objectTypes, diff.ObjectType)
+				}
+			}
+			walk(n, n.Children)
+		}
+	}
+	walk(nil, b.roots)
+	sort.Strings(b.objectTypes)
+
+	b.refresh()
+	return b
+}
+
+// matches reports whether a difference passes the filters
+func (b *Browser) matches(diff models.Difference) bool {
+	return b.shown[diff.Type] && (b.objectType == "" || diff.ObjectType == b.objectType)
+}
+
+// ownDifferences returns the differences of the node itself that pass the
+// filters
+func (b *Browser) ownDifferences(n *output.TreeNode) []models.Difference {
+	var diffs []models.Difference
+	for _, diff := range n.Differences {
+		if b.matches(diff) {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+func (b *Browser) count(n *output.TreeNode) int {
+	total := len(b.ownDifferences(n))
+	for _, child := range n.Children {
+		total += b.count(child)
+	}
+	return total
+}
+
+// refresh rebuilds the visible rows after the filters or the expanded
+// nodes changed, keeping the selected node when it is still visible
+func (b *Browser) refresh() {
+	selected := b.selected()
+
+	b.rows = b.rows[:0]
+	var walk func(nodes []*output.TreeNode, depth int)
+	walk = func(nodes []*output.TreeNode, depth int) {
+		for _, n := range nodes {
+			count := b.count(n)
+			if count == 0 {
+				continue
+			}
+			b.rows = append(b.rows, row{node: n, depth: depth, count: count})
+			if b.expanded[n] {
+				walk(n.Children, depth+1)
+			}
+		}
+	}
+	walk(b.roots, 0)
+
+	for i, r := range b.rows {
+		if r.node == selected {
+			b.cursor = i
+			return
+		}
+	}
+	b.cursor = clamp(b.cursor, 0, len(b.rows)-1)
+}
+
+func (b *Browser) selected() *output.TreeNode {
+	if b.cursor < 0 || b.cursor >= len(b.rows) {
+		return nil
+	}
+	return b.rows[b.cursor].node
+}
+
+func (b *Browser) move(delta int) {
+	b.cursor = clamp(b.cursor+delta, 0, len(b.rows)-1)
+	b.detailOffset = 0
+}
+
+// HandleKey applies a key press and reports whether the browser should
+// close. Keys are single characters or names such as "up", "pgdown",
+// "enter", "esc" and "ctrl+c".
+func (b *Browser) HandleKey(key string) bool {
+	b.status = ""
+	if key != "q" && key != "esc" {
+		b.quitArmed = false
+	}
+	if b.help && key != "ctrl+c" {
+		b.help = false
+		return false
+	}
+
+	switch key {
+	case "q", "esc", "ctrl+c":
+		if b.unsaved && !b.quitArmed && key != "ctrl+c" {
+			b.quitArmed = true
+			b.status = "Ignore marks are not written: press w to write them, or q again to quit"
+			return false
+		}
+		return true
+	case "up", "k":
+		b.move(-1)
+	case "down", "j":
+		b.move(1)
+	case "home", "g":
+		b.move(-len(b.rows))
+	case "end", "G":
+		b.move(len(b.rows))
+	case "right", "l":
+		if n := b.selected(); n != nil && len(n.Children) > 0 {
+			b.expanded[n] = true
+			b.refresh()
+		}
+	case "left", "h":
+		n := b.selected()
+		if n == nil {
+			break
+		}
+		if b.expanded[n] && len(n.Children) > 0 {
+			b.expanded[n] = false
+			b.refresh()
+			break
+		}
+		if parent := b.parent[n]; parent != nil {
+			for i, r := range b.rows {
+				if r.node == parent {
+					b.move(i - b.cursor)
+					break
+				}
+			}
+		}
+	case "enter", " ":
+		if n := b.selected(); n != nil && len(n.Children) > 0 {
+			b.expanded[n] = !b.expanded[n]
+			b.refresh()
+		}
+	case "e", "c":
+		for n := range b.expanded {
+			b.expanded[n] = key == "e"
+		}
+		b.refresh()
+	case "a", "r", "m":
+		change := map[string]models.DifferenceType{"a": models.Added, "r": models.Removed, "m": models.Modified}[key]
+		b.shown[change] = !b.shown[change]
+		b.refresh()
+	case "t":
+		b.objectType = b.nextObjectType()
+		b.refresh()
+	case "i":
+		b.toggleIgnored()
+	case "w":
+		b.writeIgnoreFile()
+	case "pgdown", "ctrl+d", "J":
+		b.detailOffset += max(b.detailPage/2, 1)
+	case "pgup", "ctrl+u", "K":
+		b.detailOffset = max(b.detailOffset-max(b.detailPage/2, 1), 0)
+	case "?":
+		b.help = true
+	}
+	return false
+}
+
+// nextObjectType cycles the object type filter through every type present
+func (b *Browser) nextObjectType() string {
+	if b.objectType == "" {
+		if len(b.objectTypes) == 0 {
+			return ""
+		}
+		return b.objectTypes[0]
+	}
+	for i, objectType := range b.objectTypes {
+		if objectType == b.objectType && i+1 < len(b.objectTypes) {
+			return b.objectTypes[i+1]
+		}
+	}
+	return ""
+}
+
+func (b *Browser) toggleIgnored() {
+	n := b.selected()
+	if n == nil {
+		return
+	}
+	b.ignored[n] = !b.ignored[n]
+	if !b.ignored[n] {
+		delete(b.ignored, n)
+	}
+	b.unsaved = true
+	if b.ignored[n] {
+		b.status = "Ignoring " + ignorePattern(n)
+	} else {
+		b.status = "No longer ignoring " + ignorePattern(n)
+	}
+}
+
+// ignorePattern is the pattern that leaves a node out of comparisons.
+// Patterns match object names only, so ignoring a column ignores columns
+// of that name in every table.
+func ignorePattern(n *output.TreeNode) string {
+	return strings.ToLower(n.ObjectType) + ":" + n.Name
+}
+
+// IgnorePatterns returns the patterns of the objects marked as ignored, in
+// order and without duplicates
+func (b *Browser) IgnorePatterns() []string {
+	seen := make(map[string]bool)
+	var patterns []string
+	for n := range b.ignored {
+		pattern := ignorePattern(n)
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+func (b *Browser) writeIgnoreFile() {
+	patterns := b.IgnorePatterns()
+	var sb strings.Builder
+	sb.WriteString("# Ignore patterns written by schemalyzer compare --tui\n")
+	fmt.Fprintf(&sb, "# Use them with --ignore @%s\n", b.ignoreFile)
+	for _, pattern := range patterns {
+		sb.WriteString(pattern + "\n")
+	}
+
+	if err := os.WriteFile(b.ignoreFile, []byte(sb.String()), 0o644); err != nil {
+		b.status = fmt.Sprintf("failed to write ignore patterns: %v", err)
+		return
+	}
+	b.unsaved = false
+	b.status = fmt.Sprintf("Ignore patterns written to %s: %d", b.ignoreFile, len(patterns))
+}
+
+// View renders a frame of the given size, one line per row of the
+// terminal
+func (b *Browser) View(width, height int) string {
+	width, height = max(width, 40), max(height, 5)
+	bodyHeight := height - 2
+	b.detailPage = bodyHeight
+
+	lines := make([]string, 0, height)
+	lines = append(lines, styled(styleReverse, pad(b.title(), width)))
+
+	if b.help {
+		help := helpLines()
+		for i := 0; i < bodyHeight; i++ {
+			text := ""
+			if i < len(help) {
+				text = help[i]
+			}
+			lines = append(lines, pad(text, width))
+		}
+	} else {
+		treeWidth := clamp(width*2/5, 24, width-20)
+		detailWidth := width - treeWidth - 1
+		tree := b.treeLines(treeWidth, bodyHeight)
+		detail := b.detailLines(detailWidth, bodyHeight)
+		for i := 0; i < bodyHeight; i++ {
+			lines = append(lines, tree[i]+styled(styleDim, "│")+detail[i])
+		}
+	}
+
+	lines = append(lines, styled(styleReverse, pad(b.statusLine(), width)))
+	return strings.Join(lines, "\r\n")
+}
+
+func (b *Browser) title() string {
+	var added, removed, modified int
+	for _, diff := range b.result.Differences {
+		switch diff.Type {
+		case models.Added:
+			added++
+		case models.Removed:
+			removed++
+		case models.Modified:
+			modified++
+		}
+	}
+	return fmt.Sprintf(" %s → %s   %d differences: %d added, %d removed, %d modified",
+		b.result.SourceDatabase, b.result.TargetDatabase, len(b.result.Differences), added, removed, modified)
+}
+
+func (b *Browser) statusLine() string {
+	if b.status != "" {
+		return " " + b.status
+	}
+
+	var filters []string
+	for _, change := range changeTypes {
+		box := "[ ]"
+		if b.shown[change] {
+			box = "[x]"
+		}
+		filters = append(filters, box+" "+strings.ToLower(string(change)))
+	}
+	objectType := b.objectType
+	if objectType == "" {
+		objectType = "all"
+	}
+	return fmt.Sprintf(" %s   type: %s   ignored: %d   ? help  q quit", strings.Join(filters, " "), objectType, len(b.ignored))
+}
+
+func helpLines() []string {
+	return []string{
+		"",
+		styled(styleBold, " Keys"),
+		"",
+		"   ↑ ↓  j k       Move",
+		"   → ←  l h       Expand, collapse or go to the parent",
+		"   enter space    Expand or collapse",
+		"   e c            Expand or collapse everything",
+		"   a r m          Show or hide added, removed and modified differences",
+		"   t              Cycle the object type filter",
+		"   i              Mark or unmark the selected object as ignored",
+		"   w              Write the ignore patterns",
+		"   pgdn pgup      Scroll the detail pane (also J K, ctrl+d ctrl+u)",
+		"   q              Quit",
+		"",
+		" Ignore patterns match object names, so ignoring a column ignores",
+		" columns of that name in every table.",
+		"",
+		" Press any key to close this help.",
+	}
+}
+
+func (b *Browser) treeLines(width, height int) []string {
+	lines := make([]string, height)
+	if len(b.rows) == 0 {
+		message := "No differences"
+		if len(b.result.Differences) > 0 {
+			message = "No differences match the filters"
+		}
+		lines[0] = styled(styleDim, pad(" "+message, width))
+		for i := 1; i < height; i++ {
+			lines[i] = pad("", width)
+		}
+		return lines
+	}
+
+	// Keep the cursor in view
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+height {
+		b.offset = b.cursor - height + 1
+	}
+
+	for i := 0; i < height; i++ {
+		index := b.offset + i
+		if index >= len(b.rows) {
+			lines[i] = pad("", width)
+			continue
+		}
+		r := b.rows[index]
+
+		expander := "  "
+		if len(r.node.Children) > 0 {
+			expander = "▸ "
+			if b.expanded[r.node] {
+				expander = "▾ "
+			}
+		}
+		mark := " "
+		if own := b.ownDifferences(r.node); len(own) > 0 {
+			mark = markerOf(own)
+		}
+		text := strings.Repeat("  ", r.depth) + expander + mark + " " + nodeLabel(r.node)
+		if len(r.node.Children) > 0 {
+			text += fmt.Sprintf(" (%d)", r.count)
+		}
+		if b.ignored[r.node] {
+			text += " [ignored]"
+		}
+		text = pad(" "+text, width)
+
+		switch {
+		case index == b.cursor:
+			lines[i] = styled(styleReverse, text)
+		case b.ignored[r.node]:
+			lines[i] = styled(styleDim, text)
+		default:
+			lines[i] = styled(markerStyle(mark), text)
+		}
+	}
+	return lines
+}
+
+func (b *Browser) detailLines(width, height int) []string {
+	var content []string
+	add := func(style, text string) {
+		content = append(content, styled(style, pad(" "+text, width)))
+	}
+
+	n := b.selected()
+	if n != nil {
+		add(styleBold, nodeLabel(n))
+		if b.ignored[n] {
+			add(styleDim, "Ignored as "+ignorePattern(n))
+		}
+
+		for _, diff := range b.ownDifferences(n) {
+			add("", "")
+			mark := markerOf([]models.Difference{diff})
+			add(markerStyle(mark), mark+" "+diff.ObjectType+" "+diff.ObjectName)
+			add("", "  "+diff.Description)
+			content = append(content, detailText(output.Describe(diff), width)...)
+		}
+
+		var members []string
+		var walk func(nodes []*output.TreeNode)
+		walk = func(nodes []*output.TreeNode) {
+			for _, child := range nodes {
+				for _, diff := range b.ownDifferences(child) {
+					members = append(members, styled(markerStyle(markerOf([]models.Difference{diff})),
+						pad(fmt.Sprintf("   %s %s: %s", markerOf([]models.Difference{diff}), nodeLabel(child), diff.Description), width)))
+				}
+				walk(child.Children)
+			}
+		}
+		walk(n.Children)
+		if len(members) > 0 {
+			add("", "")
+			add(styleBold, "Contains")
+			content = append(content, members...)
+		}
+	}
+
+	b.detailOffset = clamp(b.detailOffset, 0, max(len(content)-height, 0))
+	lines := make([]string, height)
+	for i := range lines {
+		if index := b.detailOffset + i; index < len(content) {
+			lines[i] = content[index]
+		} else {
+			lines[i] = pad("", width)
+		}
+	}
+	return lines
+}
+
+// detailText lays out the attributes side by side and the body diffs
+// below them
+func detailText(detail output.Detail, width int) []string {
+	var lines []string
+	if len(detail.Attributes) > 0 {
+		nameWidth := 9
+		for _, attr := range detail.Attributes {
+			nameWidth = max(nameWidth, len(attr.Name))
+		}
+		nameWidth = min(nameWidth, 20)
+		valueWidth := max((width-nameWidth-7)/2, 8)
+
+		cell := func(marker, name, source, target string) string {
+			return " " + marker + " " + pad(name, nameWidth) + "  " + pad(flatten(source), valueWidth) + "  " + pad(flatten(target), valueWidth)
+		}
+		lines = append(lines, pad("", width))
+		lines = append(lines, styled(styleBold, pad(cell(" ", "Attribute", "Source", "Target"), width)))
+		for _, attr := range detail.Attributes {
+			if attr.Changed {
+				lines = append(lines, styled(styleYellow, pad(cell("*", attr.Name, attr.Source, attr.Target), width)))
+			} else {
+				lines = append(lines, pad(cell(" ", attr.Name, attr.Source, attr.Target), width))
+			}
+		}
+	}
+
+	for _, body := range detail.Bodies {
+		lines = append(lines, pad("", width))
+		lines = append(lines, styled(styleBold, pad(" "+body.Name, width)))
+		for _, line := range body.Lines {
+			switch line.Kind {
+			case "add":
+				lines = append(lines, styled(styleGreen, pad(" + "+line.Text, width)))
+			case "del":
+				lines = append(lines, styled(styleRed, pad(" - "+line.Text, width)))
+			default:
+				lines = append(lines, pad("   "+line.Text, width))
+			}
+		}
+	}
+	return lines
+}
+
+func nodeLabel(n *output.TreeNode) string {
+	label := n.ObjectType + " " + n.Name
+	if n.Signature != "" {
+		label += "(" + n.Signature + ")"
+	}
+	return label
+}
+
+// markerOf returns the change marker of a node's differences, "~" when
+// they differ in kind
+func markerOf(diffs []models.Difference) string {
+	mark := ""
+	for _, diff := range diffs {
+		m := "~"
+		switch diff.Type {
+		case models.Added:
+			m = "+"
+		case models.Removed:
+			m = "-"
+		}
+		if mark != "" && mark != m {
+			return "~"
+		}
+		mark = m
+	}
+	return mark
+}
+
+func markerStyle(mark string) string {
+	switch mark {
+	case "+":
+		return styleGreen
+	case "-":
+		return styleRed
+	case "~":
+		return styleYellow
+	}
+	return styleCyan
+}
+
+func styled(style, text string) string {
+	if style == "" {
+		return text
+	}
+	return style + text + styleReset
+}
+
+// flatten puts a value on one line for a table cell
+func flatten(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// pad truncates or pads text to exactly width runes
+func pad(text string, width int) string {
+	runes := []rune(strings.ReplaceAll(text, "\t", "    "))
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return string(runes) + strings.Repeat(" ", width-len(runes))
+}
+
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+func testResult() *models.ComparisonResult {
+	return &models.ComparisonResult{
+		SourceDatabase: "postgresql://legacy",
+		TargetDatabase: "postgresql://app",
+		Differences: []models.Difference{
+			{Type: models.Modified, ObjectType: "View", ObjectName: "active_users", Identity: models.ObjectIdentity{Name: "active_users"},
+				Source: &models.View{Name: "active_users", Definition: "SELECT id\nFROM users"}, Target: &models.View{Name: "active_users", Definition: "SELECT id\nFROM users\nWHERE active"},
+				Description: "View definition changed"},
+			{Type: models.Modified, ObjectType: "Column", ObjectName: "users.id", Identity: models.ObjectIdentity{Table: "users", Name: "id"},
+				Source: &models.Column{Name: "id", DataType: "integer"}, Target: &models.Column{Name: "id", DataType: "bigint"},
+				Description: "Column definition changed"},
+			{Type: models.Removed, ObjectType: "Column", ObjectName: "users.fax", Identity: models.ObjectIdentity{Table: "users", Name: "fax"},
+				Source: &models.Column{Name: "fax", DataType: "text"}, Description: "Column exists in source but not in target"},
+			{Type: models.Added, ObjectType: "Table", ObjectName: "audit_log", Identity: models.ObjectIdentity{Name: "audit_log"},
+				Target: &models.Table{Name: "audit_log"}, Description: "Table exists in target but not in source"},
+		},
+	}
+}
+
+func rowLabels(b *Browser) []string {
+	var labels []string
+	for _, r := range b.rows {
+		labels = append(labels, nodeLabel(r.node))
+	}
+	return labels
+}
+
+func TestBrowser_Navigation(t *testing.T) {
+	b := NewBrowser(testResult(), "")
+
+	want := []string{"Table audit_log", "Table users", "View active_users"}
+	if got := rowLabels(b); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+
+	b.HandleKey("down")
+	b.HandleKey("right")
+	want = []string{"Table audit_log", "Table users", "Column fax", "Column id", "View active_users"}
+	if got := rowLabels(b); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows after expanding users = %v, want %v", got, want)
+	}
+
+	// Left on a member goes to its table, then collapses it
+	b.HandleKey("down")
+	b.HandleKey("left")
+	if got := nodeLabel(b.selected()); got != "Table users" {
+		t.Errorf("selected %q after left, want the table", got)
+	}
+	b.HandleKey("left")
+	if len(b.rows) != 3 {
+		t.Errorf("got %d rows after collapsing, want 3", len(b.rows))
+	}
+}
+
+func TestBrowser_Filters(t *testing.T) {
+	b := NewBrowser(testResult(), "")
+
+	b.HandleKey("m")
+	want := []string{"Table audit_log", "Table users"}
+	if got := rowLabels(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows without modified = %v, want %v", got, want)
+	}
+	b.HandleKey("m")
+
+	// The type filter cycles through Column, Table and View, then all
+	b.HandleKey("t")
+	if b.objectType != "Column" {
+		t.Fatalf("object type = %q, want Column", b.objectType)
+	}
+	want = []string{"Table users"}
+	if got := rowLabels(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows for columns = %v, want %v", got, want)
+	}
+	for _, want := range []string{"Table", "View", ""} {
+		b.HandleKey("t")
+		if b.objectType != want {
+			t.Errorf("object type = %q, want %q", b.objectType, want)
+		}
+	}
+}
+
+func TestBrowser_IgnorePatterns(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schemalyzer.ignore")
+	b := NewBrowser(testResult(), file)
+
+	b.HandleKey("i") // audit_log
+	b.HandleKey("down")
+	b.HandleKey("right")
+	b.HandleKey("down")
+	b.HandleKey("i") // users.fax
+	if got, want := b.IgnorePatterns(), []string{"column:fax", "table:audit_log"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("patterns = %v, want %v", got, want)
+	}
+
+	if b.HandleKey("q") {
+		t.Fatal("quit with unwritten ignore marks")
+	}
+	b.HandleKey("w")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "column:fax\ntable:audit_log\n") {
+		t.Errorf("ignore file = %q", data)
+	}
+	if !b.HandleKey("q") {
+		t.Error("did not quit after writing")
+	}
+}
+
+func TestBrowser_View(t *testing.T) {
+	b := NewBrowser(testResult(), "")
+	b.HandleKey("end") // active_users
+
+	frame := b.View(100, 20)
+	lines := strings.Split(frame, "\r\n")
+	if len(lines) != 20 {
+		t.Fatalf("frame has %d lines, want 20", len(lines))
+	}
+	for _, want := range []string{"4 differences", "View active_users", "+ WHERE active", "[x] added"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame does not contain %q", want)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("\x1b[Aj\x1b[6~\r\x1b"))
+	want := []string{"up", "j", "pgdown", "enter", "esc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// CheckTerminal reports an error unless in and out are an interactive
+// terminal the browser can run on
+func CheckTerminal(in, out *os.File) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return errors.New("the browser needs an interactive terminal")
+	}
+	return nil
+}
+
+// Run shows the browser on the terminal until the user quits
+func Run(b *Browser, in, out *os.File) error {
+	if err := CheckTerminal(in, out); err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	// Switch to the alternate screen and hide the cursor, and undo both on
+	// the way out so the shell is left as it was
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	buf := make([]byte, 64)
+	for {
+		// The size is read on every frame, so resizing takes effect on the
+		// next key press
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		if _, err := fmt.Fprint(out, "\x1b[H"+strings.ReplaceAll(b.View(width, height), "\r\n", "\x1b[K\r\n")+"\x1b[K\x1b[J"); err != nil {
+			return fmt.Errorf("failed to draw: %w", err)
+		}
+
+		n, err := in.Read(buf)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read key: %w", err)
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			if b.HandleKey(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// escapeKeys names the escape sequences of the keys the browser uses
+var escapeKeys = map[string]string{
+	"[A":  "up",
+	"[B":  "down",
+	"[C":  "right",
+	"[D":  "left",
+	"[H":  "home",
+	"[F":  "end",
+	"OA":  "up",
+	"OB":  "down",
+	"OC":  "right",
+	"OD":  "left",
+	"OH":  "home",
+	"OF":  "end",
+	"[1~": "home",
+	"[4~": "end",
+	"[5~": "pgup",
+	"[6~": "pgdown",
+}
+
+// parseKeys splits the bytes of one read into key names. A read holds
+// several keys when they were typed or pasted faster than frames are drawn.
+func parseKeys(data []byte) []string {
+	var keys []string
+	for len(data) > 0 {
+		c := data[0]
+		switch {
+		case c == 0x1b:
+			key, size := parseEscape(data[1:])
+			if key != "" {
+				keys = append(keys, key)
+			}
+			data = data[1+size:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+		case c == 0x03:
+			keys = append(keys, "ctrl+c")
+		case c == 0x04:
+			keys = append(keys, "ctrl+d")
+		case c == 0x15:
+			keys = append(keys, "ctrl+u")
+		case c >= 0x20 && c < 0x7f:
+			keys = append(keys, string(c))
+		}
+		data = data[1:]
+	}
+	return keys
+}
+
+// parseEscape reads the rest of an escape sequence, returning its key name
+// and length. A lone escape is the Esc key.
+func parseEscape(data []byte) (string, int) {
+	if len(data) == 0 || (data[0] != '[' && data[0] != 'O') {
+		return "esc", 0
+	}
+	// Sequences end at the first byte in the range @ to ~
+	for i := 1; i < len(data); i++ {
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			if key, ok := escapeKeys[string(data[:i+1])]; ok {
+				return key, i + 1
+			}
+			return "", i + 1
+		}
+	}
+	return "", len(data)
+}
@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults applied by LoadConfig to settings left out of the file
+const (
+	DefaultInterval       = 5 * time.Minute
+	DefaultRetries        = 3
+	DefaultRetryBackoff   = time.Second
+	DefaultWebhookTimeout = 10 * time.Second
+)
+
+// Config describes what the watcher monitors and where it reports drift
+type Config struct {
+	// Interval is how often every target is fingerprinted
+	Interval time.Duration `yaml:"interval"`
+	// Retries is how many times a failed webhook delivery is retried
+	Retries int `yaml:"retries"`
+	// RetryBackoff is the delay before the first retry; it doubles on each
+	// further attempt
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+	// MetricsListen is the address /metrics is served on for Prometheus;
+	// empty disables it
+	MetricsListen string    `yaml:"metrics_listen"`
+	Targets       []Target  `yaml:"targets"`
+	Webhooks      []Webhook `yaml:"webhooks"`
+}
+
+// Target is a schema watched for drift from its golden file
+type Target struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"`
+	Conn       string   `yaml:"conn"`
+	Schema     string   `yaml:"schema"`
+	Golden     string   `yaml:"golden"`
+	Ignore     []string `yaml:"ignore"`
+	TablesOnly bool     `yaml:"tables_only"`
+}
+
+// Webhook is an HTTP endpoint that receives drift events as JSON POSTs
+type Webhook struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout time.Duration     `yaml:"timeout"`
+}
+
+// LoadConfig reads a watch configuration file. Environment variables in
+// connection strings, webhook URLs and headers are expanded, so secrets
+// can stay out of the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch config: %w", err)
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse watch config: %w", err)
+	}
+
+	for i := range config.Targets {
+		config.Targets[i].Conn = os.ExpandEnv(config.Targets[i].Conn)
+	}
+	for i := range config.Webhooks {
+		config.Webhooks[i].URL = os.ExpandEnv(config.Webhooks[i].URL)
+		for key, value := range config.Webhooks[i].Headers {
+			config.Webhooks[i].Headers[key] = os.ExpandEnv(value)
+		}
+	}
+
+	config.applyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.Retries == 0 {
+		c.Retries = DefaultRetries
+	}
+	if c.RetryBackoff == 0 {
+		c.RetryBackoff = DefaultRetryBackoff
+	}
+	for i := range c.Webhooks {
+		if c.Webhooks[i].Timeout == 0 {
+			c.Webhooks[i].Timeout = DefaultWebhookTimeout
+		}
+	}
+}
+
+// Validate reports missing or duplicate settings
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Interval < 0 || c.Retries < 0 || c.RetryBackoff < 0 {
+		errs = append(errs, errors.New("interval, retries and retry_backoff must not be negative"))
+	}
+	if len(c.Targets) == 0 {
+		errs = append(errs, errors.New("no targets configured"))
+	}
+
+	names := make(map[string]bool)
+	for i, target := range c.Targets {
+		if target.Name == "" {
+			errs = append(errs, fmt.Errorf("target %d: name required", i+1))
+			continue
+		}
+		if names[target.Name] {
+			errs = append(errs, fmt.Errorf("target %s: duplicate name", target.Name))
+		}
+		names[target.Name] = true
+		if target.Type == "" || target.Conn == "" || target.Schema == "" || target.Golden == "" {
+			errs = append(errs, fmt.Errorf("target %s: type, conn, schema and golden are required", target.Name))
+		}
+	}
+
+	for i, webhook := range c.Webhooks {
+		if webhook.URL == "" {
+			errs = append(errs, fmt.Errorf("webhook %d: url required", i+1))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid watch config: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/metrics"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+)
+
+// Metric names exported by the watcher
+const (
+	metricFingerprint  = "schemalyzer_schema_fingerprint_info"
+	metricTables       = "schemalyzer_schema_tables"
+	metricViews        = "schemalyzer_schema_views"
+	metricColumns      = "schemalyzer_schema_columns"
+	metricIndexes      = "schemalyzer_schema_indexes"
+	metricDrift        = "schemalyzer_drift"
+	metricDifferences  = "schemalyzer_drift_differences"
+	metricReadDuration = "schemalyzer_read_duration_seconds"
+	metricCheckErrors  = "schemalyzer_check_errors_total"
+	metricLastCheck    = "schemalyzer_last_check_timestamp_seconds"
+)
+
+// readBuckets spans fast local catalogs to slow remote ones, in seconds
+var readBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+func newMetrics() *metrics.Registry {
+	registry := metrics.NewRegistry()
+	registry.Register(metricFingerprint, "Current fingerprint of a target's schema, as a label", metrics.Gauge)
+	registry.Register(metricTables, "Tables in a target's schema", metrics.Gauge)
+	registry.Register(metricViews, "Views in a target's schema", metrics.Gauge)
+	registry.Register(metricColumns, "Table columns in a target's schema", metrics.Gauge)
+	registry.Register(metricIndexes, "Schema-level indexes in a target's schema", metrics.Gauge)
+	registry.Register(metricDrift, "Whether a target differed from its golden file at the last comparison", metrics.Gauge)
+	registry.Register(metricDifferences, "Differences from the golden file at the last comparison, by change and object type", metrics.Gauge)
+	registry.Register(metricReadDuration, "Time taken to read a target's schema", metrics.Histogram, readBuckets...)
+	registry.Register(metricCheckErrors, "Checks that failed to read, fingerprint or compare a target", metrics.Counter)
+	registry.Register(metricLastCheck, "Unix time of a target's last successful check", metrics.Gauge)
+	return registry
+}
+
+// Metrics returns the registry the watcher records into, ready to be
+// served as a Prometheus scrape target
+func (w *Watcher) Metrics() *metrics.Registry {
+	return w.metrics
+}
+
+func (w *Watcher) recordRead(target Target, elapsed time.Duration) {
+	w.metrics.Observe(metricReadDuration, metrics.Labels{"target": target.Name}, elapsed.Seconds())
+}
+
+func (w *Watcher) recordSchema(target Target, schema *models.Schema, fingerprint string) {
+	labels := metrics.Labels{"target": target.Name}
+
+	w.metrics.Delete(metricFingerprint, labels)
+	w.metrics.Set(metricFingerprint, metrics.Labels{"target": target.Name, "schema": target.Schema, "fingerprint": fingerprint}, 1)
+
+	stats := schemalyzer.Summarize(schema)
+	w.metrics.Set(metricTables, labels, float64(stats.TableCount))
+	w.metrics.Set(metricViews, labels, float64(stats.ViewCount))
+	w.metrics.Set(metricColumns, labels, float64(stats.TotalColumns))
+	w.metrics.Set(metricIndexes, labels, float64(stats.IndexCount))
+	w.metrics.Set(metricLastCheck, labels, float64(time.Now().Unix()))
+}
+
+func (w *Watcher) recordDifferences(target Target, diffs []models.DifferenceSummary) {
+	labels := metrics.Labels{"target": target.Name}
+	w.metrics.Delete(metricDifferences, labels)
+
+	drift := 0.0
+	if len(diffs) > 0 {
+		drift = 1
+	}
+	w.metrics.Set(metricDrift, labels, drift)
+
+	counts := make(map[[2]string]int)
+	for _, diff := range diffs {
+		counts[[2]string{string(diff.Type), diff.ObjectType}]++
+	}
+	for key, count := range counts {
+		w.metrics.Set(metricDifferences, metrics.Labels{"target": target.Name, "change": key[0], "object_type": key[1]}, float64(count))
+	}
+}
+
+func (w *Watcher) recordError(target Target) {
+	w.metrics.Add(metricCheckErrors, metrics.Labels{"target": target.Name}, 1)
+}
@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers events to every configured webhook
+type Notifier struct {
+	webhooks []Webhook
+	retries  int
+	backoff  time.Duration
+	client   *http.Client
+}
+
+// NewNotifier returns a notifier for the config's webhooks and retry settings
+func NewNotifier(config *Config) *Notifier {
+	return &Notifier{
+		webhooks: config.Webhooks,
+		retries:  config.Retries,
+		backoff:  config.RetryBackoff,
+		client:   &http.Client{},
+	}
+}
+
+// Notify posts an event to every webhook, retrying failed deliveries with
+// exponential backoff. It returns the failures of webhooks that never
+// accepted the event.
+func (n *Notifier) Notify(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	var errs []error
+	for _, webhook := range n.webhooks {
+		if err := n.deliver(ctx, webhook, event, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", webhook.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) deliver(ctx context.Context, webhook Webhook, event *Event, body []byte) error {
+	delay := n.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, webhook, event, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying: network errors, 429 and 5xx are, other statuses aren't
+func (n *Notifier) post(ctx context.Context, webhook Webhook, event *Event, body []byte) (bool, error) {
+	if webhook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, webhook.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "schemalyzer-watch")
+	req.Header.Set("X-Schemalyzer-Event", string(event.Type))
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
@@ -0,0 +1,258 @@
+// Package watch monitors schemas for drift from their golden files and
+// reports changes to webhooks.
+//
+// Every interval each target is read and fingerprinted. The full comparison
+// against the golden file only runs when the target's or the golden file's
+// fingerprint changed since the last check, and an event is only sent when
+// the set of differences, or what a changed object now looks like, changed, so a drifted schema raises one alert
+// rather than one per interval.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/metrics"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+)
+
+// maxBackoffFactor caps how far a failing target's polling interval grows
+const maxBackoffFactor = 16
+
+// EventType classifies a drift event
+type EventType string
+
+const (
+	// EventDrift reports a target that differs from its golden file, or
+	// whose differences changed since the last event
+	EventDrift EventType = "drift"
+	// EventResolved reports a drifted target that matches its golden file again
+	EventResolved EventType = "resolved"
+	// EventError reports a target that can't be read or compared
+	EventError EventType = "error"
+)
+
+// Event is the JSON body posted to webhooks
+type Event struct {
+	Type              EventType                  `json:"type"`
+	Target            string                     `json:"target"`
+	Schema            string                     `json:"schema"`
+	Golden            string                     `json:"golden"`
+	Time              time.Time                  `json:"time"`
+	Fingerprint       string                     `json:"fingerprint,omitempty"`
+	GoldenFingerprint string                     `json:"golden_fingerprint,omitempty"`
+	Summary           map[string]int             `json:"summary,omitempty"`
+	Differences       []models.DifferenceSummary `json:"differences,omitempty"`
+	Error             string                     `json:"error,omitempty"`
+}
+
+// ReadFunc reads the current schema of a target
+type ReadFunc func(ctx context.Context, target Target) (*models.Schema, error)
+
+// Watcher checks targets for drift and notifies webhooks
+type Watcher struct {
+	config   *Config
+	read     ReadFunc
+	notifier *Notifier
+	log      io.Writer
+	logMu    sync.Mutex
+	states   map[string]*targetState
+	metrics  *metrics.Registry
+}
+
+// targetState is what the watcher remembers about a target between checks
+type targetState struct {
+	// fingerprint and golden are the fingerprints seen at the last completed check
+	fingerprint string
+	golden      string
+	// alerted identifies the condition last reported, empty when in sync
+	alerted string
+	// failures counts consecutive failed checks, for backoff
+	failures  int
+	nextCheck time.Time
+}
+
+// New returns a watcher for the config's targets. Progress is logged to log.
+func New(config *Config, read ReadFunc, notifier *Notifier, log io.Writer) *Watcher {
+	states := make(map[string]*targetState, len(config.Targets))
+	for _, target := range config.Targets {
+		states[target.Name] = &targetState{}
+	}
+	return &Watcher{config: config, read: read, notifier: notifier, log: log, states: states, metrics: newMetrics()}
+}
+
+// Run checks every target immediately and then once per interval until ctx
+// is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.CheckAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll checks every target that isn't backing off, concurrently
+func (w *Watcher) CheckAll(ctx context.Context) {
+	now := time.Now()
+	var wg sync.WaitGroup
+	for _, target := range w.config.Targets {
+		if now.Before(w.states[target.Name].nextCheck) {
+			continue
+		}
+		wg.Add(1)
+		go func(target Target) {
+			defer wg.Done()
+			w.Check(ctx, target)
+		}(target)
+	}
+	wg.Wait()
+}
+
+// Check fingerprints one target, compares it with its golden file when
+// either side changed, and sends an event if the outcome differs from the
+// last one reported
+func (w *Watcher) Check(ctx context.Context, target Target) {
+	state := w.states[target.Name]
+
+	event, err := w.evaluate(ctx, target, state)
+	if err != nil {
+		// Forget the last outcome so the first successful check reports
+		// the target's condition again
+		state.fingerprint, state.golden = "", ""
+		state.failures++
+		state.nextCheck = time.Now().Add(w.backoff(state.failures))
+		w.recordError(target)
+		w.logf("%s: %v", target.Name, err)
+
+		event = w.newEvent(EventError, target)
+		event.Error = err.Error()
+		w.send(ctx, state, event, "error:"+event.Error)
+		return
+	}
+	state.failures = 0
+	state.nextCheck = time.Time{}
+
+	if event == nil {
+		return
+	}
+	switch event.Type {
+	case EventDrift:
+		w.logf("%s: drift, %d differences from %s", target.Name, len(event.Differences), target.Golden)
+		// The key covers what each object changed to, so a drift that
+		// changes again is reported again
+		w.send(ctx, state, event, "drift:"+models.DifferencesKey(event.Differences))
+	case EventResolved:
+		w.logf("%s: matches %s", target.Name, target.Golden)
+		if state.alerted != "" {
+			w.send(ctx, state, event, "")
+		}
+	}
+}
+
+// evaluate returns the target's current condition, or nil when neither
+// fingerprint changed since the last check
+func (w *Watcher) evaluate(ctx context.Context, target Target, state *targetState) (*Event, error) {
+	start := time.Now()
+	current, err := w.read(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	w.recordRead(target, time.Since(start))
+	golden, err := schemalyzer.LoadSchema(target.Golden)
+	if err != nil {
+		return nil, err
+	}
+
+	options := schemalyzer.FingerprintOptions{TablesOnly: target.TablesOnly}
+	fingerprint, err := schemalyzer.Fingerprint(current, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint schema: %w", err)
+	}
+	goldenFingerprint, err := schemalyzer.Fingerprint(golden, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint golden file: %w", err)
+	}
+	w.recordSchema(target, current, fingerprint)
+
+	if fingerprint == state.fingerprint && goldenFingerprint == state.golden {
+		return nil, nil
+	}
+
+	event := w.newEvent(EventResolved, target)
+	event.Fingerprint = fingerprint
+	event.GoldenFingerprint = goldenFingerprint
+
+	if fingerprint != goldenFingerprint {
+		result, err := schemalyzer.Compare(golden, current, schemalyzer.CompareOptions{
+			Ignore:     target.Ignore,
+			TablesOnly: target.TablesOnly,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare with golden file: %w", err)
+		}
+		if len(result.Differences) > 0 {
+			event.Type = EventDrift
+			event.Summary = make(map[string]int)
+			for _, diff := range result.Differences {
+				event.Summary[string(diff.Type)]++
+			}
+			event.Differences = models.SummarizeDifferences(result.Differences)
+		}
+	}
+
+	w.recordDifferences(target, event.Differences)
+	state.fingerprint = fingerprint
+	state.golden = goldenFingerprint
+	return event, nil
+}
+
+// send delivers an event unless the same condition was already reported.
+// A failed delivery forgets the check, so the next one reports it again.
+func (w *Watcher) send(ctx context.Context, state *targetState, event *Event, key string) {
+	if key != "" && key == state.alerted {
+		return
+	}
+	if err := w.notifier.Notify(ctx, event); err != nil {
+		w.logf("%s: failed to deliver %s event: %v", event.Target, event.Type, err)
+		state.fingerprint, state.golden = "", ""
+		return
+	}
+	state.alerted = key
+}
+
+func (w *Watcher) newEvent(eventType EventType, target Target) *Event {
+	return &Event{
+		Type:   eventType,
+		Target: target.Name,
+		Schema: target.Schema,
+		Golden: target.Golden,
+		Time:   time.Now().UTC(),
+	}
+}
+
+// backoff doubles the polling interval for each consecutive failure, up to
+// maxBackoffFactor times the interval
+func (w *Watcher) backoff(failures int) time.Duration {
+	factor := 1
+	for i := 1; i < failures && factor < maxBackoffFactor; i++ {
+		factor *= 2
+	}
+	return time.Duration(factor) * w.config.Interval
+}
+
+func (w *Watcher) logf(format string, args ...interface{}) {
+	w.logMu.Lock()
+	defer w.logMu.Unlock()
+	fmt.Fprintf(w.log, "%s "+format+"\n", append([]interface{}{time.Now().Format(time.RFC3339)}, args...)...)
+}
@@ -0,0 +1,268 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/schemalyzer"
+)
+
+// stub is a local webhook endpoint that records the events it accepts
+type stub struct {
+	mu       sync.Mutex
+	events   []Event
+	failures int
+	attempts int
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var event Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.events = append(s.events, event)
+}
+
+func (s *stub) received() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+func usersSchema(idType string) *models.Schema {
+	return &models.Schema{
+		Name: "app",
+		Tables: []models.Table{
+			{Name: "users", Columns: []models.Column{{Name: "id", DataType: idType, Position: 1}}},
+		},
+	}
+}
+
+// newWatcher returns a watcher over one target whose live schema is
+// whatever *live points at
+func newWatcher(t *testing.T, endpoint string, live **models.Schema) (*Watcher, Target) {
+	t.Helper()
+	golden := filepath.Join(t.TempDir(), "golden.json")
+	if err := schemalyzer.SaveSchema(usersSchema("integer"), golden); err != nil {
+		t.Fatalf("Failed to write golden file: %v", err)
+	}
+
+	config := &Config{
+		Interval:     time.Minute,
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+		Targets:      []Target{{Name: "prod", Type: "fake", Conn: "fake", Schema: "app", Golden: golden}},
+		Webhooks:     []Webhook{{URL: endpoint}},
+	}
+	read := func(ctx context.Context, target Target) (*models.Schema, error) {
+		if *live == nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return *live, nil
+	}
+	return New(config, read, NewNotifier(config), io.Discard), config.Targets[0]
+}
+
+func TestWatcherDeduplicatesDriftAlerts(t *testing.T) {
+	endpoint := &stub{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	live := usersSchema("integer")
+	watcher, target := newWatcher(t, server.URL, &live)
+	ctx := context.Background()
+
+	watcher.Check(ctx, target)
+	if events := endpoint.received(); len(events) != 0 {
+		t.Fatalf("A schema matching its golden file should not alert, got %+v", events)
+	}
+
+	live = usersSchema("bigint")
+	watcher.Check(ctx, target)
+	watcher.Check(ctx, target)
+
+	events := endpoint.received()
+	if len(events) != 1 {
+		t.Fatalf("Expected one drift alert for repeated checks, got %d", len(events))
+	}
+	drift := events[0]
+	if drift.Type != EventDrift || drift.Target != "prod" || len(drift.Differences) != 1 {
+		t.Errorf("Unexpected drift event: %+v", drift)
+	}
+	if drift.Differences[0].ObjectName != "users.id" || drift.Summary["MODIFIED"] != 1 {
+		t.Errorf("Expected users.id to be reported modified, got %+v", drift.Differences)
+	}
+
+	live = usersSchema("integer")
+	watcher.Check(ctx, target)
+	events = endpoint.received()
+	if len(events) != 2 || events[1].Type != EventResolved {
+		t.Fatalf("Expected a resolved event after the drift was fixed, got %+v", events)
+	}
+}
+
+func TestWatcherReportsDriftThatChangesAgain(t *testing.T) {
+	endpoint := &stub{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	live := usersSchema("bigint")
+	watcher, target := newWatcher(t, server.URL, &live)
+	ctx := context.Background()
+
+	watcher.Check(ctx, target)
+	live = usersSchema("text")
+	watcher.Check(ctx, target)
+
+	events := endpoint.received()
+	if len(events) != 2 || events[0].Type != EventDrift || events[1].Type != EventDrift {
+		t.Fatalf("Expected a second drift alert after users.id changed again, got %+v", events)
+	}
+	if !strings.Contains(string(events[1].Differences[0].Target), `"text"`) {
+		t.Errorf("Expected the second alert to carry the new column type, got %s", events[1].Differences[0].Target)
+	}
+}
+
+func TestWatcherReportsErrorsOnceAndBacksOff(t *testing.T) {
+	endpoint := &stub{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	var live *models.Schema
+	watcher, target := newWatcher(t, server.URL, &live)
+	ctx := context.Background()
+
+	watcher.Check(ctx, target)
+	watcher.Check(ctx, target)
+
+	events := endpoint.received()
+	if len(events) != 1 || events[0].Type != EventError {
+		t.Fatalf("Expected one error event, got %+v", events)
+	}
+
+	state := watcher.states[target.Name]
+	if wait := time.Until(state.nextCheck); wait <= time.Minute {
+		t.Errorf("Expected the second failure to back off beyond one interval, got %s", wait)
+	}
+
+	live = usersSchema("integer")
+	watcher.Check(ctx, target)
+	events = endpoint.received()
+	if len(events) != 2 || events[1].Type != EventResolved {
+		t.Errorf("Expected recovery to be reported, got %+v", events)
+	}
+}
+
+func TestNotifierRetriesFailedDeliveries(t *testing.T) {
+	endpoint := &stub{failures: 2}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	config := &Config{Retries: 2, RetryBackoff: time.Millisecond, Webhooks: []Webhook{{URL: server.URL}}}
+	if err := NewNotifier(config).Notify(context.Background(), &Event{Type: EventDrift, Target: "prod"}); err != nil {
+		t.Fatalf("Expected the third attempt to succeed: %v", err)
+	}
+	if endpoint.attempts != 3 || len(endpoint.received()) != 1 {
+		t.Errorf("Expected 3 attempts and 1 delivery, got %d and %d", endpoint.attempts, len(endpoint.received()))
+	}
+
+	endpoint.failures = 5
+	if err := NewNotifier(config).Notify(context.Background(), &Event{Type: EventDrift}); err == nil {
+		t.Error("Expected delivery to fail once retries are exhausted")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("WATCH_TEST_DSN", "postgres://watch@localhost/app")
+	path := filepath.Join(t.TempDir(), "watch.yaml")
+	config := `
+interval: 30s
+targets:
+  - name: prod
+    type: postgresql
+    conn: ${WATCH_TEST_DSN}
+    schema: public
+    golden: expected.yaml
+webhooks:
+  - url: http://localhost:9000/hook
+`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if loaded.Interval != 30*time.Second || loaded.Retries != DefaultRetries {
+		t.Errorf("Unexpected settings: %+v", loaded)
+	}
+	if loaded.Targets[0].Conn != "postgres://watch@localhost/app" {
+		t.Errorf("Expected the connection string to be expanded, got %s", loaded.Targets[0].Conn)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("targets:\n  - name: prod\n  - name: prod\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadConfig(invalid)
+	if err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Errorf("Expected a duplicate name error, got %v", err)
+	}
+}
+
+func TestWatcherRecordsMetrics(t *testing.T) {
+	endpoint := &stub{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	live := usersSchema("bigint")
+	watcher, target := newWatcher(t, server.URL, &live)
+	watcher.Check(context.Background(), target)
+
+	var exposition strings.Builder
+	if _, err := watcher.Metrics().WriteTo(&exposition); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	for _, line := range []string{
+		`schemalyzer_drift{target="prod"} 1`,
+		`schemalyzer_drift_differences{change="MODIFIED",object_type="Column",target="prod"} 1`,
+		`schemalyzer_schema_tables{target="prod"} 1`,
+		`schemalyzer_read_duration_seconds_count{target="prod"} 1`,
+	} {
+		if !strings.Contains(exposition.String(), line+"\n") {
+			t.Errorf("Expected %q in metrics:\n%s", line, exposition.String())
+		}
+	}
+
+	live = usersSchema("integer")
+	watcher.Check(context.Background(), target)
+	exposition.Reset()
+	if _, err := watcher.Metrics().WriteTo(&exposition); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	if strings.Contains(exposition.String(), "schemalyzer_drift_differences{") {
+		t.Errorf("Expected difference counts to clear once in sync:\n%s", exposition.String())
+	}
+}
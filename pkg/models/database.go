@@ -0,0 +1,20 @@
+package models
+
+// Database is a snapshot of several schemas of one database together with
+// the objects that belong to the database as a whole
+type Database struct {
+	DatabaseType DatabaseType
+	Schemas      []Schema
+	Extensions   []Extension `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+}
+
+// Schema returns the schema with the given name, or nil if the snapshot
+// does not contain it
+func (d *Database) Schema(name string) *Schema {
+	for i := range d.Schemas {
+		if d.Schemas[i].Name == name {
+			return &d.Schemas[i]
+		}
+	}
+	return nil
+}
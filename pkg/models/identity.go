@@ -0,0 +1,46 @@
+package models
+
+import "strings"
+
+// ObjectIdentity canonically identifies a schema object. Objects that live
+// under a table (columns, constraints, indexes, triggers) carry the table
+// name, and overloadable routines carry a signature of their argument types.
+type ObjectIdentity struct {
+	// Schema is only set when comparing whole databases. A single-schema
+	// comparison leaves it empty, as the source and target schemas may have
+	// different names.
+	Schema    string `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Table     string `yaml:"table,omitempty" json:"table,omitempty"`
+	Name      string `yaml:"name" json:"name"`
+	Signature string `yaml:"signature,omitempty" json:"signature,omitempty"`
+}
+
+// String renders the identity as schema.table.name(signature), omitting
+// empty parts.
+func (id ObjectIdentity) String() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{id.Schema, id.Table, id.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	name := strings.Join(parts, ".")
+	if id.Signature != "" {
+		name += "(" + id.Signature + ")"
+	}
+	return name
+}
+
+// RoutineSignature builds the identity signature of a function or procedure
+// from the data types of its input arguments.
+func RoutineSignature(params []Parameter) string {
+	var types []string
+	for _, p := range params {
+		if p.Direction == Out {
+			continue
+		}
+		types = append(types, p.DataType)
+	}
+	return strings.Join(types, ", ")
+}
@@ -8,7 +8,7 @@ import (
 // IgnorePattern represents a pattern to ignore during schema comparison
 type IgnorePattern struct {
 	Pattern    string
-	ObjectType string // "table", "column", "constraint", "index", "view", "sequence", "procedure", "function", "trigger", or "*" for all
+	ObjectType string // "schema", "table", "column", "constraint", "index", "view", "sequence", "procedure", "function", "trigger", "policy", "synonym", "extension", "event", or "*" for all
 	Regex      *regexp.Regexp
 }
 
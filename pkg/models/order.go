@@ -0,0 +1,145 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// objectTypeOrder ranks top-level objects in the order reports list them
+var objectTypeOrder = map[string]int{
+	"Schema":    0,
+	"Table":     1,
+	"View":      2,
+	"Index":     3,
+	"Sequence":  4,
+	"Procedure": 5,
+	"Function":  6,
+	"Trigger":   7,
+	"Synonym":   8,
+	"Extension": 9,
+	"Event":     10,
+}
+
+// memberTypeOrder ranks the differences reported under a table, with the
+// table's own difference first
+var memberTypeOrder = map[string]int{
+	"Table":         0,
+	"Column":        1,
+	"Constraint":    2,
+	"Index":         3,
+	"Policy":        4,
+	"Trigger":       5,
+	"Row Security":  6,
+	"Table Comment": 7,
+}
+
+var changeOrder = map[DifferenceType]int{
+	Removed:  0,
+	Added:    1,
+	Modified: 2,
+}
+
+// differenceKey places a difference in the schema hierarchy: the schema,
+// the top-level object it belongs to, and the member of that object
+type differenceKey struct {
+	schema     string
+	ownerRank  int
+	ownerType  string
+	owner      string
+	memberRank int
+	member     string
+	signature  string
+}
+
+func keyOf(d Difference) differenceKey {
+	id := d.Identity
+	k := differenceKey{schema: id.Schema, signature: id.Signature}
+
+	switch {
+	case d.ObjectType == "Schema":
+		k.schema = id.Name
+		if k.schema == "" {
+			k.schema = d.ObjectName
+		}
+		k.ownerType = "Schema"
+	case id.Name == "" && id.Table == "":
+		// Results loaded from older exports carry no identity, only a
+		// name such as "users.email"
+		k.ownerType, k.owner = d.ObjectType, d.ObjectName
+		switch table, name, ok := strings.Cut(d.ObjectName, "."); {
+		case d.ObjectType == "Table" || d.ObjectType == "Table Comment" || d.ObjectType == "Row Security":
+			k.ownerType = "Table"
+		case ok && memberTypeOrder[d.ObjectType] > 0:
+			k.ownerType, k.owner, k.member = "Table", table, name
+		}
+	case id.Table != "":
+		k.ownerType, k.owner = "Table", id.Table
+		k.member = id.Name
+	case d.ObjectType == "Table" || d.ObjectType == "Table Comment" || d.ObjectType == "Row Security":
+		k.ownerType, k.owner = "Table", id.Name
+	default:
+		k.ownerType, k.owner = d.ObjectType, id.Name
+	}
+
+	k.ownerRank = rank(objectTypeOrder, k.ownerType)
+	if k.ownerType == "Table" {
+		k.memberRank = rank(memberTypeOrder, d.ObjectType)
+	}
+	return k
+}
+
+func rank(order map[string]int, objectType string) int {
+	if r, ok := order[objectType]; ok {
+		return r
+	}
+	return len(order)
+}
+
+// CompareDifferences orders differences hierarchically: by schema, then
+// top-level objects by type and name, with the columns, constraints,
+// indexes and other members of a table right after the table itself. It
+// returns a negative number when a sorts before b, a positive number when
+// after, and zero when they are the same difference.
+func CompareDifferences(a, b Difference) int {
+	ka, kb := keyOf(a), keyOf(b)
+	if c := strings.Compare(ka.schema, kb.schema); c != 0 {
+		return c
+	}
+	if ka.ownerRank != kb.ownerRank {
+		return ka.ownerRank - kb.ownerRank
+	}
+	if c := strings.Compare(ka.ownerType, kb.ownerType); c != 0 {
+		return c
+	}
+	if c := strings.Compare(ka.owner, kb.owner); c != 0 {
+		return c
+	}
+	if ka.memberRank != kb.memberRank {
+		return ka.memberRank - kb.memberRank
+	}
+	if c := strings.Compare(a.ObjectType, b.ObjectType); c != 0 {
+		return c
+	}
+	if c := strings.Compare(ka.member, kb.member); c != 0 {
+		return c
+	}
+	if c := strings.Compare(ka.signature, kb.signature); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.ObjectName, b.ObjectName); c != 0 {
+		return c
+	}
+	if changeOrder[a.Type] != changeOrder[b.Type] {
+		return changeOrder[a.Type] - changeOrder[b.Type]
+	}
+	return strings.Compare(a.Description, b.Description)
+}
+
+// SortDifferences puts differences in the hierarchical order of
+// CompareDifferences, so the same comparison always reports them the same
+// way
+func SortDifferences(diffs []Difference) {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return CompareDifferences(diffs[i], diffs[j]) < 0
+	})
+}
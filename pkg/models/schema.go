@@ -20,6 +20,9 @@ type Schema struct {
 	Procedures   []Procedure
 	Functions    []Function
 	Triggers     []Trigger
+	Synonyms     []Synonym    `yaml:"synonyms,omitempty" json:"synonyms,omitempty"`
+	Extensions   []Extension  `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+	Events       []Event      `yaml:"events,omitempty" json:"events,omitempty"`
 	Stats        *SchemaStats `yaml:"stats,omitempty" json:"stats,omitempty"`
 }
 
@@ -31,6 +34,10 @@ type Table struct {
 	Indexes     []Index
 	Comment     string
 	RowCount    *int64 `yaml:"row_count,omitempty" json:"row_count,omitempty"`
+	// Row-level security (PostgreSQL)
+	RowSecurityEnabled bool     `yaml:"row_security,omitempty" json:"row_security,omitempty"`
+	RowSecurityForced  bool     `yaml:"force_row_security,omitempty" json:"force_row_security,omitempty"`
+	Policies           []Policy `yaml:"policies,omitempty" json:"policies,omitempty"`
 }
 
 type Column struct {
@@ -50,11 +57,22 @@ type Constraint struct {
 	Name             string
 	Type             ConstraintType
 	Columns          []string
+	// ReferencedSchema is only set when a foreign key points into another schema
+	ReferencedSchema string `yaml:"referenced_schema,omitempty" json:"referenced_schema,omitempty"`
 	ReferencedTable  string
 	ReferencedColumn []string
 	OnUpdate         string `yaml:"on_update,omitempty" json:"on_update,omitempty"`
 	OnDelete         string `yaml:"on_delete,omitempty" json:"on_delete,omitempty"`
 	CheckExpression  string
+	// ExclusionDefinition holds the index method and element/operator pairs
+	// of an EXCLUDE constraint, e.g. "USING gist (room_id WITH =, during WITH &&)"
+	ExclusionDefinition string `yaml:"exclusion,omitempty" json:"exclusion,omitempty"`
+	// State flags are stored negatively so snapshots taken before they
+	// existed read back as enforced, immediate constraints
+	IsDeferrable        bool `yaml:"deferrable,omitempty" json:"deferrable,omitempty"`
+	IsInitiallyDeferred bool `yaml:"initially_deferred,omitempty" json:"initially_deferred,omitempty"`
+	IsDisabled          bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	IsNotValidated      bool `yaml:"not_validated,omitempty" json:"not_validated,omitempty"`
 }
 
 type ConstraintType string
@@ -65,8 +83,19 @@ const (
 	Unique     ConstraintType = "UNIQUE"
 	Check      ConstraintType = "CHECK"
 	NotNull    ConstraintType = "NOT_NULL"
+	Exclude    ConstraintType = "EXCLUDE"
 )
 
+// Policy is a PostgreSQL row-level security policy
+type Policy struct {
+	Name          string
+	Command       string
+	Roles         []string
+	IsRestrictive bool `yaml:"restrictive,omitempty" json:"restrictive,omitempty"`
+	Using         string
+	WithCheck     string
+}
+
 type Index struct {
 	Name      string
 	TableName string
@@ -146,10 +175,38 @@ const (
 	After  TriggerTiming = "AFTER"
 )
 
+// Synonym is an Oracle alias for an object, possibly in another schema or
+// reached through a database link
+type Synonym struct {
+	Schema       string
+	Name         string
+	TargetOwner  string
+	TargetObject string
+	DBLink       string `yaml:"db_link,omitempty" json:"db_link,omitempty"`
+}
+
+// Extension is an installed PostgreSQL extension such as pgcrypto or postgis
+type Extension struct {
+	Name    string
+	Version string
+	Schema  string
+}
+
+// Event is a MySQL scheduled event
+type Event struct {
+	Schema       string
+	Name         string
+	Schedule     string
+	Status       string
+	OnCompletion string
+	Body         string
+}
+
 type Difference struct {
 	Type        DifferenceType
 	ObjectType  string
 	ObjectName  string
+	Identity    ObjectIdentity
 	Source      interface{}
 	Target      interface{}
 	Description string
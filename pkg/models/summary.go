@@ -0,0 +1,99 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DifferenceSummary is a difference as reported by watch events and fleet
+// reports: what changed and what it changed to, without the source object
+type DifferenceSummary struct {
+	Type        DifferenceType `json:"type"`
+	ObjectType  string         `json:"object_type"`
+	ObjectName  string         `json:"object_name"`
+	Description string         `json:"description"`
+	// Target is the canonical JSON of the object after the change, with
+	// its schema name left out; empty for removed objects
+	Target json.RawMessage `json:"target,omitempty"`
+}
+
+// SummarizeDifferences summarizes differences sorted by object type, object
+// name, change and description, so equal sets summarize identically
+func SummarizeDifferences(diffs []Difference) []DifferenceSummary {
+	summaries := make([]DifferenceSummary, len(diffs))
+	for i, diff := range diffs {
+		summaries[i] = DifferenceSummary{
+			Type:        diff.Type,
+			ObjectType:  diff.ObjectType,
+			ObjectName:  diff.ObjectName,
+			Description: diff.Description,
+			Target:      canonicalObject(diff.Target),
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.ObjectType != b.ObjectType {
+			return a.ObjectType < b.ObjectType
+		}
+		if a.ObjectName != b.ObjectName {
+			return a.ObjectName < b.ObjectName
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Description != b.Description {
+			return a.Description < b.Description
+		}
+		return bytes.Compare(a.Target, b.Target) < 0
+	})
+	return summaries
+}
+
+// DifferencesKey identifies a set of summarized differences by what
+// changed and what it changed to, so the same column widened to bigint in
+// one place and to text in another keys differently
+func DifferencesKey(summaries []DifferenceSummary) string {
+	hash := sha256.New()
+	for _, s := range summaries {
+		fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s\x00%s\n", s.Type, s.ObjectType, s.ObjectName, s.Description, s.Target)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// canonicalObject encodes a compared object as JSON with sorted keys and
+// without Schema fields, which name the schema the object was read from
+// rather than anything about the object itself
+func canonicalObject(object interface{}) json.RawMessage {
+	data, err := json.Marshal(object)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	data, err = json.Marshal(withoutSchema(decoded))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func withoutSchema(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		delete(v, "Schema")
+		for key, value := range v {
+			v[key] = withoutSchema(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = withoutSchema(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// ComparisonView is the data a comparison template is executed against
+type ComparisonView struct {
+	// Source and Target describe the compared sides, e.g. "postgresql://public"
+	Source string
+	Target string
+	Time   time.Time
+	// Total, Added, Removed and Modified count the differences
+	Total    int
+	Added    int
+	Removed  int
+	Modified int
+	// Differences are in the hierarchical order every output format uses:
+	// by schema, then object type and name, with a table's objects after it
+	Differences []DifferenceView
+	// SourceSchema and TargetSchema are nil for whole-database comparisons
+	SourceSchema *Schema
+	TargetSchema *Schema
+}
+
+// DifferenceView is one difference as seen by a template
+type DifferenceView struct {
+	// Change is "added", "removed" or "modified"
+	Change     string
+	ObjectType string
+	// Name is the qualified object name, e.g. "users.email"
+	Name     string
+	Identity ObjectIdentity
+	// Table is the table the object belongs to, empty for schema-level objects
+	Table string
+	// Section groups table-level objects under their table and everything
+	// else by object type, e.g. "Table users" or "Views"
+	Section     string
+	Description string
+	// Source and Target are the compared objects, e.g. a Column; one
+	// of them is nil for added and removed objects
+	Source interface{}
+	Target interface{}
+	// Changes lists the attributes that differ, for modified objects
+	Changes []AttributeChange
+}
+
+// AttributeChange is one changed attribute of a modified object
+type AttributeChange struct {
+	Name   string
+	Before string
+	After  string
+}
+
+// DifferenceGroup is a set of differences sharing a key, from groupBy
+type DifferenceGroup struct {
+	Key         string
+	Differences []DifferenceView
+}
+
+// SchemaView is the data a documentation template is executed against. It
+// embeds the schema, so templates use .Name, .Tables, .Views and so on.
+type SchemaView struct {
+	*Schema
+	Generated time.Time
+}
@@ -0,0 +1,292 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// closeTimeout bounds how long Close waits for a plugin to exit
+const closeTimeout = 5 * time.Second
+
+// Lookup finds the plugin executable for a database type on PATH
+func Lookup(dbType string) (string, error) {
+	return exec.LookPath(ExecutablePrefix + dbType)
+}
+
+// Client is a schema reader backed by an external plugin. It implements the
+// same methods as the built-in readers, including the statistics methods.
+type Client struct {
+	path string
+	args []string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	out    *bufio.Reader
+	nextID int64
+	info   *Info
+	// broken is set once a call was abandoned mid-response, after which
+	// the stream can no longer be trusted
+	broken error
+}
+
+// NewClient returns a client that starts the plugin at path on Connect
+func NewClient(path string, args ...string) *Client {
+	return &Client{path: path, args: args}
+}
+
+// NewStreamClient returns a client that talks to an already running plugin
+// over the given streams. It is mainly useful for testing plugins in-process.
+func NewStreamClient(out io.Reader, in io.WriteCloser) *Client {
+	return &Client{in: in, out: bufio.NewReader(out)}
+}
+
+// Info returns what the plugin reported in its handshake
+func (c *Client) Info() Info {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.info == nil {
+		return Info{}
+	}
+	return *c.info
+}
+
+func (c *Client) start() error {
+	cmd := exec.Command(c.path, c.args...)
+	cmd.Stderr = os.Stderr
+
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", c.path, err)
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", c.path, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", c.path, err)
+	}
+
+	c.cmd = cmd
+	c.in = in
+	c.out = bufio.NewReader(out)
+	return nil
+}
+
+// Connect starts the plugin if needed, performs the handshake and asks the
+// plugin to connect to the database
+func (c *Client) Connect(ctx context.Context, connectionString string) error {
+	c.mu.Lock()
+	if c.in == nil {
+		if err := c.start(); err != nil {
+			c.mu.Unlock()
+			return err
+		}
+	}
+	handshaken := c.info != nil
+	c.mu.Unlock()
+
+	if !handshaken {
+		var info Info
+		if err := c.call(ctx, MethodHandshake, HandshakeParams{ProtocolVersion: ProtocolVersion}, &info); err != nil {
+			return fmt.Errorf("plugin handshake failed: %w", err)
+		}
+		if err := checkVersion(info); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.info = &info
+		c.mu.Unlock()
+	}
+
+	if err := c.call(ctx, MethodConnect, ConnectParams{ConnectionString: connectionString}, nil); err != nil {
+		return fmt.Errorf("failed to connect through plugin: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) ListSchemas(ctx context.Context) ([]string, error) {
+	var schemas []string
+	if err := c.call(ctx, MethodListSchemas, nil, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	return schemas, nil
+}
+
+func (c *Client) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	var schema models.Schema
+	if err := c.call(ctx, MethodGetSchema, SchemaParams{Schema: schemaName}, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// GetTableRowCount returns the row count for a specific table
+func (c *Client) GetTableRowCount(ctx context.Context, schemaName, tableName string) (int64, error) {
+	if err := c.requireStatistics(); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := c.call(ctx, MethodGetTableRowCount, TableParams{Schema: schemaName, Table: tableName}, &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetColumnSamples returns sample distinct values for a column
+func (c *Client) GetColumnSamples(ctx context.Context, schemaName, tableName, columnName string, limit int) ([]string, error) {
+	if err := c.requireStatistics(); err != nil {
+		return nil, err
+	}
+
+	var samples []string
+	params := ColumnParams{Schema: schemaName, Table: tableName, Column: columnName, Limit: limit}
+	if err := c.call(ctx, MethodGetColumnSamples, params, &samples); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+func (c *Client) requireStatistics() error {
+	if !c.Info().HasCapability(CapabilityStatistics) {
+		return fmt.Errorf("plugin %s does not support statistics", c.Info().Name)
+	}
+	return nil
+}
+
+// Close asks the plugin to shut down and waits for it to exit, killing it
+// if it does not exit in time
+func (c *Client) Close() error {
+	c.mu.Lock()
+	started := c.in != nil
+	c.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	callErr := c.call(ctx, MethodClose, nil, nil)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.in.Close()
+	c.in = nil
+
+	if c.cmd != nil {
+		done := make(chan error, 1)
+		go func() { done <- c.cmd.Wait() }()
+		select {
+		case err := <-done:
+			if err != nil && callErr == nil {
+				callErr = fmt.Errorf("plugin exited with error: %w", err)
+			}
+		case <-ctx.Done():
+			c.cmd.Process.Kill()
+			<-done
+		}
+		c.cmd = nil
+	}
+
+	return callErr
+}
+
+// Call sends a raw protocol request and decodes its result. The typed
+// methods should be preferred; Call exists for probing plugins, as the
+// conformance harness does.
+func (c *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	return c.call(ctx, method, params, result)
+}
+
+// call sends one request and decodes the matching response into result.
+// Calls are serialized since the protocol answers requests in turn.
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.broken != nil {
+		return c.broken
+	}
+	if c.in == nil {
+		return errors.New("plugin is not connected")
+	}
+
+	c.nextID++
+	req := Request{ID: c.nextID, Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", method, err)
+		}
+		req.Params = raw
+	}
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+	if _, err := c.in.Write(append(line, '\n')); err != nil {
+		return c.fail(fmt.Errorf("failed to send %s request: %w", method, err))
+	}
+
+	type reply struct {
+		resp Response
+		err  error
+	}
+	replies := make(chan reply, 1)
+	go func() {
+		var r reply
+		line, err := c.out.ReadBytes('\n')
+		if err != nil {
+			r.err = err
+		} else {
+			r.err = json.Unmarshal(line, &r.resp)
+		}
+		replies <- r
+	}()
+
+	var r reply
+	select {
+	case <-ctx.Done():
+		// The response may still arrive, so the stream is out of step
+		return c.fail(fmt.Errorf("plugin %s call interrupted: %w", method, ctx.Err()))
+	case r = <-replies:
+	}
+
+	if r.err != nil {
+		return c.fail(fmt.Errorf("failed to read %s response: %w", method, r.err))
+	}
+	if r.resp.ID != req.ID {
+		return c.fail(fmt.Errorf("plugin answered request %d while %d was pending", r.resp.ID, req.ID))
+	}
+	if r.resp.Error != nil {
+		return r.resp.Error
+	}
+	if result != nil && len(r.resp.Result) > 0 {
+		if err := json.Unmarshal(r.resp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// fail marks the stream unusable and stops the plugin process. The caller
+// holds c.mu.
+func (c *Client) fail(err error) error {
+	c.broken = err
+	if c.cmd != nil && c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	return err
+}
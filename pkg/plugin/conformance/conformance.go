@@ -0,0 +1,165 @@
+// Package conformance checks that a schema reader plugin follows the stdio
+// protocol. Plugin authors call Run from a test:
+//
+//	func TestConformance(t *testing.T) {
+//		client := plugin.NewClient("./schemalyzer-reader-mydb")
+//		conformance.Run(t, client, "mydb://localhost/test")
+//	}
+//
+// The database behind the connection string must contain at least one schema.
+package conformance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/plugin"
+)
+
+// callTimeout bounds each protocol call so a hung plugin fails the test
+// rather than stalling it
+const callTimeout = 30 * time.Second
+
+// missingSchema is a schema name no real database should contain
+const missingSchema = "schemalyzer_conformance_missing_schema"
+
+var validConstraintTypes = map[models.ConstraintType]bool{
+	models.PrimaryKey: true,
+	models.ForeignKey: true,
+	models.Unique:     true,
+	models.Check:      true,
+	models.Exclude:    true,
+}
+
+// Run connects to the plugin, exercises every protocol method and closes it
+func Run(t *testing.T, client *plugin.Client, connectionString string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	if err := client.Connect(ctx, connectionString); err != nil {
+		t.Fatalf("handshake and connect: %v", err)
+	}
+
+	t.Run("handshake", func(t *testing.T) {
+		info := client.Info()
+		if info.ProtocolVersion != plugin.ProtocolVersion {
+			t.Errorf("protocol version %d, want %d", info.ProtocolVersion, plugin.ProtocolVersion)
+		}
+		if info.Name == "" {
+			t.Error("plugin reported no name")
+		}
+	})
+
+	var schemas []string
+	t.Run("list_schemas", func(t *testing.T) {
+		var err error
+		schemas, err = client.ListSchemas(ctx)
+		if err != nil {
+			t.Fatalf("list_schemas: %v", err)
+		}
+		if len(schemas) == 0 {
+			t.Fatal("list_schemas returned no schemas")
+		}
+	})
+
+	t.Run("get_schema", func(t *testing.T) {
+		for _, name := range schemas {
+			schema, err := client.GetSchema(ctx, name)
+			if err != nil {
+				t.Errorf("get_schema %s: %v", name, err)
+				continue
+			}
+			checkSchema(t, name, schema)
+		}
+	})
+
+	t.Run("get_schema_missing", func(t *testing.T) {
+		if _, err := client.GetSchema(ctx, missingSchema); err == nil {
+			t.Errorf("get_schema %s succeeded, want an error", missingSchema)
+		}
+	})
+
+	t.Run("unknown_method", func(t *testing.T) {
+		if err := client.Call(ctx, "schemalyzer_conformance_unknown", nil, nil); err == nil {
+			t.Error("unknown method succeeded, want an error response")
+		}
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		if !client.Info().HasCapability(plugin.CapabilityStatistics) {
+			t.Skip("plugin does not advertise statistics")
+		}
+		for _, name := range schemas {
+			schema, err := client.GetSchema(ctx, name)
+			if err != nil || len(schema.Tables) == 0 {
+				continue
+			}
+			count, err := client.GetTableRowCount(ctx, name, schema.Tables[0].Name)
+			if err != nil {
+				t.Errorf("get_table_row_count: %v", err)
+			} else if count < 0 {
+				t.Errorf("get_table_row_count returned %d", count)
+			}
+			return
+		}
+		t.Skip("no tables to count")
+	})
+
+	t.Run("close", func(t *testing.T) {
+		if err := client.Close(); err != nil {
+			t.Errorf("close: %v", err)
+		}
+	})
+}
+
+// checkSchema verifies the structural invariants the comparer relies on
+func checkSchema(t *testing.T, name string, schema *models.Schema) {
+	t.Helper()
+
+	if !strings.EqualFold(schema.Name, name) {
+		t.Errorf("get_schema %s returned schema named %q", name, schema.Name)
+	}
+	if schema.DatabaseType == "" {
+		t.Errorf("schema %s has no database_type", name)
+	}
+
+	tables := make(map[string]bool)
+	for _, table := range schema.Tables {
+		if table.Name == "" {
+			t.Errorf("schema %s has a table with no name", name)
+			continue
+		}
+		if tables[table.Name] {
+			t.Errorf("schema %s lists table %s twice", name, table.Name)
+		}
+		tables[table.Name] = true
+
+		columns := make(map[string]bool)
+		for _, column := range table.Columns {
+			if column.Name == "" || column.DataType == "" {
+				t.Errorf("table %s.%s has a column without name or data type", name, table.Name)
+			}
+			if columns[column.Name] {
+				t.Errorf("table %s.%s lists column %s twice", name, table.Name, column.Name)
+			}
+			columns[column.Name] = true
+		}
+
+		for _, constraint := range table.Constraints {
+			if !validConstraintTypes[constraint.Type] {
+				t.Errorf("constraint %s on %s.%s has unknown type %q", constraint.Name, name, table.Name, constraint.Type)
+			}
+		}
+	}
+
+	for _, view := range schema.Views {
+		if view.Name == "" {
+			t.Errorf("schema %s has a view with no name", name)
+		}
+	}
+}
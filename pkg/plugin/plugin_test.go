@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubReader struct {
+	block chan struct{}
+}
+
+func (s *stubReader) Connect(ctx context.Context, connectionString string) error {
+	if connectionString == "bad" {
+		return errors.New("authentication failed")
+	}
+	return nil
+}
+
+func (s *stubReader) ListSchemas(ctx context.Context) ([]string, error) {
+	return []string{"app"}, nil
+}
+
+func (s *stubReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	if s.block != nil {
+		<-s.block
+	}
+	return &models.Schema{Name: schemaName, DatabaseType: "stub"}, nil
+}
+
+func (s *stubReader) Close() error { return nil }
+
+func serveStub(t *testing.T, reader Reader) *Client {
+	t.Helper()
+	requests, requestWriter := io.Pipe()
+	responseReader, responses := io.Pipe()
+	go func() {
+		Serve(context.Background(), requests, responses, "stub", reader)
+		responses.Close()
+	}()
+	return NewStreamClient(responseReader, requestWriter)
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	client := serveStub(t, &stubReader{})
+	ctx := context.Background()
+
+	assert.NoError(t, client.Connect(ctx, "stub://"))
+	assert.Equal(t, "stub", client.Info().Name)
+	assert.False(t, client.Info().HasCapability(CapabilityStatistics))
+
+	schemas, err := client.ListSchemas(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, schemas)
+
+	schema, err := client.GetSchema(ctx, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Schema{Name: "app", DatabaseType: "stub"}, schema)
+
+	_, err = client.GetTableRowCount(ctx, "app", "users")
+	assert.ErrorContains(t, err, "does not support statistics")
+
+	assert.NoError(t, client.Close())
+}
+
+func TestClientReportsPluginErrors(t *testing.T) {
+	client := serveStub(t, &stubReader{})
+
+	err := client.Connect(context.Background(), "bad")
+	assert.ErrorContains(t, err, "authentication failed")
+
+	var pluginErr *Error
+	assert.ErrorAs(t, err, &pluginErr)
+}
+
+func TestClientInterruptedCallBreaksStream(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	client := serveStub(t, &stubReader{block: block})
+	assert.NoError(t, client.Connect(context.Background(), "stub://"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.GetSchema(ctx, "app")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The late response would be mistaken for the next one, so later calls fail
+	_, err = client.ListSchemas(context.Background())
+	assert.ErrorContains(t, err, "interrupted")
+}
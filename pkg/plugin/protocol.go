@@ -0,0 +1,114 @@
+// Package plugin lets schemalyzer read schemas from databases it has no
+// built-in reader for. A plugin is an executable named
+// schemalyzer-reader-<type> found on PATH. schemalyzer starts it and speaks
+// newline-delimited JSON over its stdin and stdout: one Request per line in,
+// one Response per line out, strictly in turn. Anything the plugin writes to
+// stderr is passed through to the user.
+//
+// The first request is always "handshake", followed by "connect". The
+// plugin should exit after answering "close" or when stdin reaches EOF.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is the version of the stdio protocol spoken by this package
+const ProtocolVersion = 1
+
+// ExecutablePrefix is prepended to a database type to find its plugin
+const ExecutablePrefix = "schemalyzer-reader-"
+
+// Protocol methods
+const (
+	MethodHandshake        = "handshake"
+	MethodConnect          = "connect"
+	MethodListSchemas      = "list_schemas"
+	MethodGetSchema        = "get_schema"
+	MethodGetTableRowCount = "get_table_row_count"
+	MethodGetColumnSamples = "get_column_samples"
+	MethodClose            = "close"
+)
+
+// CapabilityStatistics is advertised by plugins that answer the row count
+// and column sample methods
+const CapabilityStatistics = "statistics"
+
+// Request is a single call from schemalyzer to the plugin
+type Request struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response answers the Request with the same ID. Exactly one of Result and
+// Error is set.
+type Response struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// Error is a failure reported by the plugin
+type Error struct {
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// HandshakeParams is sent with the handshake request
+type HandshakeParams struct {
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+// Info describes a plugin and is returned from the handshake
+type Info struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	Name            string   `json:"name"`
+	Capabilities    []string `json:"capabilities,omitempty"`
+}
+
+// HasCapability reports whether the plugin advertised capability
+func (i Info) HasCapability(capability string) bool {
+	for _, c := range i.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// ConnectParams is sent with the connect request
+type ConnectParams struct {
+	ConnectionString string `json:"connection_string"`
+}
+
+// SchemaParams is sent with the get_schema request
+type SchemaParams struct {
+	Schema string `json:"schema"`
+}
+
+// TableParams is sent with the get_table_row_count request
+type TableParams struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+}
+
+// ColumnParams is sent with the get_column_samples request
+type ColumnParams struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Limit  int    `json:"limit"`
+}
+
+// checkVersion rejects a plugin speaking a different protocol version
+func checkVersion(info Info) error {
+	if info.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("plugin %s speaks protocol version %d, expected %d", info.Name, info.ProtocolVersion, ProtocolVersion)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// Reader is what a Go plugin implements to be served by Serve. It has the
+// same shape as schemalyzer's built-in readers.
+type Reader interface {
+	Connect(ctx context.Context, connectionString string) error
+	GetSchema(ctx context.Context, schemaName string) (*models.Schema, error)
+	ListSchemas(ctx context.Context) ([]string, error)
+	Close() error
+}
+
+// StatisticsReader is optionally implemented by a Reader that can count rows
+// and sample column values. Serve advertises the statistics capability for it.
+type StatisticsReader interface {
+	GetTableRowCount(ctx context.Context, schemaName, tableName string) (int64, error)
+	GetColumnSamples(ctx context.Context, schemaName, tableName, columnName string, limit int) ([]string, error)
+}
+
+// Serve answers protocol requests read from in by calling reader, writing
+// responses to out. It returns after the close request or when in reaches
+// EOF, closing the reader either way. Go plugins typically call it from main
+// with os.Stdin and os.Stdout.
+func Serve(ctx context.Context, in io.Reader, out io.Writer, name string, reader Reader) error {
+	info := Info{ProtocolVersion: ProtocolVersion, Name: name}
+	if _, ok := reader.(StatisticsReader); ok {
+		info.Capabilities = append(info.Capabilities, CapabilityStatistics)
+	}
+
+	scanner := bufio.NewScanner(in)
+	// A single request is small, but allow generous lines for long strings
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			reader.Close()
+			return fmt.Errorf("failed to decode request: %w", err)
+		}
+
+		result, err := dispatch(ctx, reader, info, req)
+		resp := Response{ID: req.ID}
+		if err != nil {
+			resp.Error = &Error{Message: err.Error()}
+		} else {
+			raw, err := json.Marshal(result)
+			if err != nil {
+				resp.Error = &Error{Message: fmt.Sprintf("failed to encode result: %v", err)}
+			} else {
+				resp.Result = raw
+			}
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			reader.Close()
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+
+		if req.Method == MethodClose {
+			return nil
+		}
+	}
+
+	reader.Close()
+	return scanner.Err()
+}
+
+func dispatch(ctx context.Context, reader Reader, info Info, req Request) (interface{}, error) {
+	switch req.Method {
+	case MethodHandshake:
+		var params HandshakeParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return info, nil
+
+	case MethodConnect:
+		var params ConnectParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, reader.Connect(ctx, params.ConnectionString)
+
+	case MethodListSchemas:
+		return reader.ListSchemas(ctx)
+
+	case MethodGetSchema:
+		var params SchemaParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return reader.GetSchema(ctx, params.Schema)
+
+	case MethodGetTableRowCount:
+		stats, ok := reader.(StatisticsReader)
+		if !ok {
+			return nil, fmt.Errorf("statistics not supported")
+		}
+		var params TableParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return stats.GetTableRowCount(ctx, params.Schema, params.Table)
+
+	case MethodGetColumnSamples:
+		stats, ok := reader.(StatisticsReader)
+		if !ok {
+			return nil, fmt.Errorf("statistics not supported")
+		}
+		var params ColumnParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return stats.GetColumnSamples(ctx, params.Schema, params.Table, params.Column, params.Limit)
+
+	case MethodClose:
+		return nil, reader.Close()
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+}
+
+func decodeParams(req Request, params interface{}) error {
+	if len(req.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return fmt.Errorf("invalid %s params: %w", req.Method, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package schemalyzer
+
+import (
+	"fmt"
+
+	"github.com/nechja/schemalyzer/internal/compare"
+	"github.com/nechja/schemalyzer/internal/output"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// CompareOptions controls Compare and CompareDatabases
+type CompareOptions struct {
+	// Ignore holds ignore patterns such as 'table:temp_*' or '*_audit'
+	Ignore []string
+	// TablesOnly compares only tables and views
+	TablesOnly bool
+}
+
+// OutputFormat is a rendering of a comparison result
+type OutputFormat string
+
+// Comparison output formats
+const (
+	FormatJSON    OutputFormat = OutputFormat(output.FormatJSON)
+	FormatYAML    OutputFormat = OutputFormat(output.FormatYAML)
+	FormatText    OutputFormat = OutputFormat(output.FormatText)
+	FormatSummary OutputFormat = OutputFormat(output.FormatSummary)
+	// FormatHTML is a self-contained HTML page for reviewing in a browser
+	FormatHTML OutputFormat = OutputFormat(output.FormatHTML)
+	// FormatJUnit reports a test case per compared object for CI servers
+	FormatJUnit OutputFormat = OutputFormat(output.FormatJUnit)
+	// FormatSARIF reports a result per difference for code scanning tools
+	FormatSARIF OutputFormat = OutputFormat(output.FormatSARIF)
+	// FormatMarkdown is a GitHub-flavored report for pull request comments
+	FormatMarkdown OutputFormat = OutputFormat(output.FormatMarkdown)
+	// FormatJSONTree and FormatYAMLTree nest differences under the schemas
+	// and tables they belong to
+	FormatJSONTree OutputFormat = OutputFormat(output.FormatJSONTree)
+	FormatYAMLTree OutputFormat = OutputFormat(output.FormatYAMLTree)
+)
+
+// Compare returns the differences that turn source into target
+func Compare(source, target *models.Schema, opts CompareOptions) (*models.ComparisonResult, error) {
+	comparer, err := newComparer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.TablesOnly {
+		source = tablesOnly(source)
+		target = tablesOnly(target)
+	}
+	return comparer.Compare(source, target), nil
+}
+
+// CompareDatabases returns the differences that turn the source snapshot
+// into the target snapshot, pairing schemas by name
+func CompareDatabases(source, target *models.Database, opts CompareOptions) (*models.ComparisonResult, error) {
+	comparer, err := newComparer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.TablesOnly {
+		source = databaseTablesOnly(source)
+		target = databaseTablesOnly(target)
+	}
+	return comparer.CompareDatabases(source, target), nil
+}
+
+// DefaultMarkdownMaxBytes is the size a markdown report is truncated to
+// unless FormatOptions says otherwise
+const DefaultMarkdownMaxBytes = output.DefaultMarkdownMaxBytes
+
+// FormatOptions controls FormatResultWithOptions
+type FormatOptions struct {
+	// MaxBytes caps the size of a markdown report, which drops whole
+	// sections and says so to stay under it. Zero uses DefaultMarkdownMaxBytes.
+	MaxBytes int
+}
+
+// FormatResult renders a comparison result
+func FormatResult(result *models.ComparisonResult, format OutputFormat) ([]byte, error) {
+	return FormatResultWithOptions(result, format, FormatOptions{})
+}
+
+// FormatResultWithOptions renders a comparison result with output limits
+func FormatResultWithOptions(result *models.ComparisonResult, format OutputFormat, opts FormatOptions) ([]byte, error) {
+	switch format {
+	case FormatJSON, FormatYAML, FormatText, FormatSummary, FormatHTML, FormatJUnit, FormatSARIF, FormatMarkdown, FormatJSONTree, FormatYAMLTree:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	data, err := output.NewFormatter(output.OutputFormat(format)).WithMaxBytes(opts.MaxBytes).Format(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format output: %w", err)
+	}
+	return data, nil
+}
+
+func newComparer(opts CompareOptions) (*compare.Comparer, error) {
+	if len(opts.Ignore) == 0 {
+		return compare.NewComparer(), nil
+	}
+
+	ignoreConfig, err := models.NewIgnoreConfig(opts.Ignore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ignore patterns: %w", err)
+	}
+	return compare.NewComparerWithIgnore(ignoreConfig), nil
+}
@@ -0,0 +1,113 @@
+package schemalyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nechja/schemalyzer/internal/docs"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// DocumentFormat is a documentation or diagram format
+type DocumentFormat string
+
+// Documentation formats. "md" and "dot" are accepted as aliases of
+// markdown and graphviz.
+const (
+	DocumentMarkdown DocumentFormat = "markdown"
+	DocumentPlantUML DocumentFormat = "plantuml"
+	DocumentMermaid  DocumentFormat = "mermaid"
+	DocumentGraphViz DocumentFormat = "graphviz"
+	DocumentD2       DocumentFormat = "d2"
+	// DocumentHTMLSite is a directory of pages, written with WriteSite
+	DocumentHTMLSite DocumentFormat = "html-site"
+)
+
+// DocumentOptions controls GenerateDocs
+type DocumentOptions struct {
+	Format DocumentFormat
+	// TablesOnly documents only tables and views
+	TablesOnly bool
+}
+
+// ParseDocumentFormat resolves a format name, case-insensitively and
+// including aliases, so callers can reject a bad format before reading
+func ParseDocumentFormat(name string) (DocumentFormat, error) {
+	switch format := DocumentFormat(strings.ToLower(name)); format {
+	case DocumentMarkdown, DocumentPlantUML, DocumentMermaid, DocumentGraphViz, DocumentD2, DocumentHTMLSite:
+		return format, nil
+	case "md":
+		return DocumentMarkdown, nil
+	case "dot":
+		return DocumentGraphViz, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+	}
+}
+
+// GenerateDocs renders schema documentation in opts.Format
+func GenerateDocs(schema *models.Schema, opts DocumentOptions) (string, error) {
+	format, err := ParseDocumentFormat(string(opts.Format))
+	if err != nil {
+		return "", err
+	}
+
+	var generator docs.DocumentGenerator
+	switch format {
+	case DocumentHTMLSite:
+		return "", fmt.Errorf("%w: %s is a directory of pages, use WriteSite", ErrUnsupportedFormat, format)
+	case DocumentPlantUML:
+		generator = docs.NewPlantUMLGenerator()
+	case DocumentMermaid:
+		generator = docs.NewMermaidGenerator()
+	case DocumentGraphViz:
+		generator = docs.NewGraphVizGenerator()
+	case DocumentD2:
+		generator = docs.NewD2Generator()
+	default:
+		generator = docs.NewMarkdownDocGenerator()
+	}
+
+	if opts.TablesOnly {
+		schema = tablesOnly(schema)
+	}
+
+	content, err := generator.Generate(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate documentation: %w", err)
+	}
+	return content, nil
+}
+
+// WriteSite writes the schema as a static HTML site into dir, with
+// index.html as its entry point. Existing files in dir are overwritten but
+// not removed, so a fresh directory avoids pages of dropped objects.
+func WriteSite(schema *models.Schema, dir string, opts DocumentOptions) error {
+	if opts.TablesOnly {
+		schema = tablesOnly(schema)
+	}
+
+	files, err := docs.NewSiteGenerator().Generate(schema)
+	if err != nil {
+		return fmt.Errorf("failed to generate documentation: %w", err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(file, files[path], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package schemalyzer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nechja/schemalyzer/internal/history"
+)
+
+var (
+	// ErrUnsupportedDatabase is returned for a database type with neither a
+	// built-in reader nor a plugin
+	ErrUnsupportedDatabase = errors.New("unsupported database type")
+	// ErrUnsupportedFormat is returned for an unknown output or documentation format
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	// ErrSnapshotUnsupported is returned when a reader can't take consistent snapshots
+	ErrSnapshotUnsupported = errors.New("consistent snapshots not supported for this database type")
+	// ErrStatisticsUnsupported is returned when a reader can't count rows or sample columns
+	ErrStatisticsUnsupported = errors.New("statistics not supported for this database type")
+	// ErrRevisionNotFound is returned when a History revision reference matches nothing
+	ErrRevisionNotFound = history.ErrRevisionNotFound
+)
+
+// ConnectError reports a failure to connect a reader
+type ConnectError struct {
+	DatabaseType string
+	Err          error
+}
+
+func (e *ConnectError) Error() string {
+	return fmt.Sprintf("failed to connect to %s database: %v", e.DatabaseType, e.Err)
+}
+
+func (e *ConnectError) Unwrap() error {
+	return e.Err
+}
+
+// ReadError reports a failure to read a schema, the schemas matching a
+// pattern, or the schema list when both are empty
+type ReadError struct {
+	Schema  string
+	Pattern string
+	Err     error
+}
+
+func (e *ReadError) Error() string {
+	switch {
+	case e.Pattern != "":
+		return fmt.Sprintf("failed to read schemas matching %s: %v", e.Pattern, e.Err)
+	case e.Schema != "":
+		return fmt.Sprintf("failed to read schema %s: %v", e.Schema, e.Err)
+	default:
+		return fmt.Sprintf("failed to list schemas: %v", e.Err)
+	}
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// FileError reports a failure to load or save a snapshot or document file
+type FileError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("failed to %s %s: %v", e.Op, e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
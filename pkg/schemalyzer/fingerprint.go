@@ -0,0 +1,330 @@
+package schemalyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nechja/schemalyzer/internal/fingerprint"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// FingerprintAlgorithm names the hash used by Fingerprint
+const FingerprintAlgorithm = "SHA256"
+
+// HashOptions selects what a fingerprint covers. Its zero value reproduces
+// the default settings of earlier releases, whose bare version 1 digests
+// are nonetheless not comparable with current fingerprints.
+type HashOptions struct {
+	// IncludeComments hashes table and column comments
+	IncludeComments bool
+	// IgnoreColumnOrder leaves column positions out, so reordered columns
+	// hash the same
+	IgnoreColumnOrder bool
+	// Bodies controls routine bodies and view definitions; empty means raw
+	Bodies BodyMode
+	// IncludeStatistics hashes table row counts when the schema has them
+	IncludeStatistics bool
+}
+
+// Validate reports an unknown body mode
+func (o HashOptions) Validate() error {
+	return o.internal().Validate()
+}
+
+// String encodes the options as they appear in a fingerprint, e.g.
+// "comments=0,order=1,bodies=raw,stats=0"
+func (o HashOptions) String() string {
+	return o.internal().String()
+}
+
+func (o HashOptions) internal() fingerprint.Options {
+	return fingerprint.Options{
+		IncludeComments:   o.IncludeComments,
+		IgnoreColumnOrder: o.IgnoreColumnOrder,
+		Bodies:            fingerprint.BodyMode(o.Bodies),
+		IncludeStatistics: o.IncludeStatistics,
+	}
+}
+
+func hashOptionsOf(o fingerprint.Options) HashOptions {
+	return HashOptions{
+		IncludeComments:   o.IncludeComments,
+		IgnoreColumnOrder: o.IgnoreColumnOrder,
+		Bodies:            BodyMode(o.Bodies),
+		IncludeStatistics: o.IncludeStatistics,
+	}
+}
+
+// BodyMode controls how routine bodies and view definitions are hashed
+type BodyMode string
+
+// Body modes
+const (
+	// BodiesRaw hashes bodies exactly as the catalog returns them
+	BodiesRaw BodyMode = "raw"
+	// BodiesNormalized collapses runs of whitespace before hashing
+	BodiesNormalized BodyMode = "normalized"
+	// BodiesExcluded leaves bodies out of the hash entirely
+	BodiesExcluded BodyMode = "none"
+)
+
+// ParsedFingerprint is a fingerprint split into version, options and digest
+type ParsedFingerprint struct {
+	Version   int
+	Algorithm string
+	Options   HashOptions
+	Digest    string
+}
+
+// String renders the fingerprint as "v2:sha256:<options>:<digest>"
+func (f ParsedFingerprint) String() string {
+	return f.internal().String()
+}
+
+func (f ParsedFingerprint) internal() fingerprint.Fingerprint {
+	return fingerprint.Fingerprint{Version: f.Version, Algorithm: f.Algorithm, Options: f.Options.internal(), Digest: f.Digest}
+}
+
+// IncompatibleFingerprintsError is returned when two fingerprints were
+// produced with different HashOptions, or when one is a bare digest from
+// an earlier release that hashed schemas differently
+type IncompatibleFingerprintsError struct {
+	Source        HashOptions
+	Target        HashOptions
+	SourceVersion int
+	TargetVersion int
+}
+
+func (e *IncompatibleFingerprintsError) Error() string {
+	return (&fingerprint.IncompatibleError{Source: e.Source.internal(), Target: e.Target.internal(),
+		SourceVersion: e.SourceVersion, TargetVersion: e.TargetVersion}).Error()
+}
+
+// fingerprintError converts the fingerprint package's errors to the ones
+// declared here
+func fingerprintError(err error) error {
+	var incompatible *fingerprint.IncompatibleError
+	if errors.As(err, &incompatible) {
+		return &IncompatibleFingerprintsError{Source: hashOptionsOf(incompatible.Source), Target: hashOptionsOf(incompatible.Target),
+			SourceVersion: incompatible.SourceVersion, TargetVersion: incompatible.TargetVersion}
+	}
+	return err
+}
+
+// FingerprintOptions controls Fingerprint and FingerprintDatabase
+type FingerprintOptions struct {
+	// TablesOnly hashes only tables and views
+	TablesOnly bool
+	// Hash selects what the hash covers and is recorded in the fingerprint
+	Hash HashOptions
+}
+
+// Fingerprint returns a self-describing hash of the schema's structure,
+// "v2:sha256:<options>:<digest>", that is stable across object ordering
+// and reader runs
+func Fingerprint(schema *models.Schema, opts FingerprintOptions) (string, error) {
+	if err := opts.Hash.Validate(); err != nil {
+		return "", err
+	}
+	if opts.TablesOnly {
+		schema = tablesOnly(schema)
+	}
+
+	fp, err := fingerprint.NewHasherWithOptions(opts.Hash.internal()).Fingerprint(schema)
+	if err != nil {
+		return "", err
+	}
+	return fp.String(), nil
+}
+
+// FingerprintDatabase returns a self-describing hash of a multi-schema snapshot
+func FingerprintDatabase(db *models.Database, opts FingerprintOptions) (string, error) {
+	if err := opts.Hash.Validate(); err != nil {
+		return "", err
+	}
+	if opts.TablesOnly {
+		db = databaseTablesOnly(db)
+	}
+
+	fp, err := fingerprint.NewHasherWithOptions(opts.Hash.internal()).DatabaseFingerprint(db)
+	if err != nil {
+		return "", err
+	}
+	return fp.String(), nil
+}
+
+// ParseFingerprint parses a fingerprint. Bare hex digests from earlier
+// releases parse as version 1 with the default options; they only compare
+// with other version 1 digests.
+func ParseFingerprint(s string) (ParsedFingerprint, error) {
+	fp, err := fingerprint.ParseFingerprint(s)
+	if err != nil {
+		return ParsedFingerprint{}, err
+	}
+	return ParsedFingerprint{Version: fp.Version, Algorithm: fp.Algorithm, Options: hashOptionsOf(fp.Options), Digest: fp.Digest}, nil
+}
+
+// CompareFingerprints reports whether two fingerprints describe the same
+// schema. Fingerprints made with different options, or a bare digest from
+// an earlier release against a current fingerprint, return an
+// *IncompatibleFingerprintsError rather than a false mismatch.
+func CompareFingerprints(source, target string) (bool, error) {
+	sourceFingerprint, err := ParseFingerprint(source)
+	if err != nil {
+		return false, fmt.Errorf("source: %w", err)
+	}
+	targetFingerprint, err := ParseFingerprint(target)
+	if err != nil {
+		return false, fmt.Errorf("target: %w", err)
+	}
+	match, err := fingerprint.Compare(sourceFingerprint.internal(), targetFingerprint.internal())
+	return match, fingerprintError(err)
+}
+
+// Manifest records a hash per object, rolled up Merkle-style into a root,
+// so two manifests can be diffed without reading either database again
+type Manifest struct {
+	Version   int           `json:"version"`
+	Algorithm string        `json:"algorithm"`
+	Scope     ManifestScope `json:"scope"`
+	Name      string        `json:"name,omitempty"`
+	// Fingerprint is the fingerprint of the manifested schema or database.
+	// Its options apply to every entry.
+	Fingerprint string          `json:"fingerprint"`
+	Root        string          `json:"root"`
+	Objects     []ManifestEntry `json:"objects"`
+}
+
+// ManifestScope says whether a manifest covers one schema or a whole database
+type ManifestScope string
+
+// Manifest scopes
+const (
+	ManifestSchema   ManifestScope = "schema"
+	ManifestDatabase ManifestScope = "database"
+)
+
+// ManifestEntry is one object of a Manifest. Self hashes the object's own
+// attributes and is only set on entries with children.
+type ManifestEntry struct {
+	Type      string          `json:"type"`
+	Table     string          `json:"table,omitempty"`
+	Name      string          `json:"name"`
+	Signature string          `json:"signature,omitempty"`
+	Hash      string          `json:"hash"`
+	Self      string          `json:"self,omitempty"`
+	Children  []ManifestEntry `json:"children,omitempty"`
+}
+
+func manifestOf(m *fingerprint.Manifest) *Manifest {
+	return &Manifest{
+		Version:     m.Version,
+		Algorithm:   m.Algorithm,
+		Scope:       ManifestScope(m.Scope),
+		Name:        m.Name,
+		Fingerprint: m.Fingerprint,
+		Root:        m.Root,
+		Objects:     manifestEntriesOf(m.Objects),
+	}
+}
+
+func manifestEntriesOf(entries []fingerprint.ManifestEntry) []ManifestEntry {
+	if entries == nil {
+		return nil
+	}
+	converted := make([]ManifestEntry, len(entries))
+	for i, e := range entries {
+		converted[i] = ManifestEntry{Type: e.Type, Table: e.Table, Name: e.Name, Signature: e.Signature,
+			Hash: e.Hash, Self: e.Self, Children: manifestEntriesOf(e.Children)}
+	}
+	return converted
+}
+
+func (m *Manifest) internal() *fingerprint.Manifest {
+	return &fingerprint.Manifest{
+		Version:     m.Version,
+		Algorithm:   m.Algorithm,
+		Scope:       fingerprint.ManifestScope(m.Scope),
+		Name:        m.Name,
+		Fingerprint: m.Fingerprint,
+		Root:        m.Root,
+		Objects:     internalEntries(m.Objects),
+	}
+}
+
+func internalEntries(entries []ManifestEntry) []fingerprint.ManifestEntry {
+	if entries == nil {
+		return nil
+	}
+	converted := make([]fingerprint.ManifestEntry, len(entries))
+	for i, e := range entries {
+		converted[i] = fingerprint.ManifestEntry{Type: e.Type, Table: e.Table, Name: e.Name, Signature: e.Signature,
+			Hash: e.Hash, Self: e.Self, Children: internalEntries(e.Children)}
+	}
+	return converted
+}
+
+// FingerprintManifest returns the per-object manifest of a schema. Its
+// Fingerprint field equals what Fingerprint returns for the same schema.
+func FingerprintManifest(schema *models.Schema, opts FingerprintOptions) (*Manifest, error) {
+	if err := opts.Hash.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.TablesOnly {
+		schema = tablesOnly(schema)
+	}
+	manifest, err := fingerprint.NewHasherWithOptions(opts.Hash.internal()).GenerateManifest(schema)
+	if err != nil {
+		return nil, err
+	}
+	return manifestOf(manifest), nil
+}
+
+// FingerprintDatabaseManifest returns the per-object manifest of a multi-schema snapshot
+func FingerprintDatabaseManifest(db *models.Database, opts FingerprintOptions) (*Manifest, error) {
+	if err := opts.Hash.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.TablesOnly {
+		db = databaseTablesOnly(db)
+	}
+	manifest, err := fingerprint.NewHasherWithOptions(opts.Hash.internal()).GenerateDatabaseManifest(db)
+	if err != nil {
+		return nil, err
+	}
+	return manifestOf(manifest), nil
+}
+
+// DiffManifests lists the objects that differ between two manifests
+func DiffManifests(source, target *Manifest) ([]models.Difference, error) {
+	differences, err := fingerprint.DiffManifests(source.internal(), target.internal())
+	return differences, fingerprintError(err)
+}
+
+// LoadManifest reads a manifest written by SaveManifest or `fingerprint --manifest`
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &FileError{Op: "load manifest", Path: path, Err: err}
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, &FileError{Op: "load manifest", Path: path, Err: err}
+	}
+	return manifest, nil
+}
+
+// SaveManifest writes a manifest as indented JSON
+func SaveManifest(manifest *Manifest, path string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		return &FileError{Op: "save manifest", Path: path, Err: err}
+	}
+	return nil
+}
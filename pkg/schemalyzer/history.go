@@ -0,0 +1,104 @@
+package schemalyzer
+
+import (
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/history"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// DefaultHistoryDir is where the CLI keeps its snapshot store
+const DefaultHistoryDir = ".schemalyzer"
+
+// History is a local, content-addressed store of schema snapshots, one
+// line of revisions per environment
+type History struct {
+	store *history.Store
+}
+
+// Revision is one saved snapshot of an environment
+type Revision struct {
+	// ID is the SHA256 of the revision record itself
+	ID string `json:"id"`
+	// Parent is the previous revision of the same environment
+	Parent      string `json:"parent,omitempty"`
+	Environment string `json:"environment"`
+	Schema      string `json:"schema"`
+	// Object is the content hash of the snapshot
+	Object  string    `json:"object"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message,omitempty"`
+}
+
+// ShortID returns the abbreviated revision ID used in listings
+func (r *Revision) ShortID() string {
+	return (*history.Revision)(r).ShortID()
+}
+
+// HistoryChange is a difference attributed to the revision that introduced it
+type HistoryChange struct {
+	Revision   *Revision
+	Difference models.Difference
+}
+
+// OpenHistory opens the snapshot store in dir, creating it if needed
+func OpenHistory(dir string) (*History, error) {
+	store, err := history.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &History{store: store}, nil
+}
+
+// Dir returns the store's root directory
+func (h *History) Dir() string {
+	return h.store.Dir()
+}
+
+// Save stores a snapshot of an environment and records it as that
+// environment's newest revision
+func (h *History) Save(env string, snapshot *models.Schema, message string) (*Revision, error) {
+	rev, err := h.store.Save(env, snapshot, message)
+	return (*Revision)(rev), err
+}
+
+// Log lists revisions newest first, limited to one environment unless env is empty
+func (h *History) Log(env string) ([]*Revision, error) {
+	revisions, err := h.store.Log(env)
+	if err != nil {
+		return nil, err
+	}
+	log := make([]*Revision, len(revisions))
+	for i, rev := range revisions {
+		log[i] = (*Revision)(rev)
+	}
+	return log, nil
+}
+
+// Resolve finds the revision a reference names: a revision ID or a prefix
+// of one, an environment name for its newest revision, or "env~N" for the
+// Nth revision before that
+func (h *History) Resolve(ref string) (*Revision, error) {
+	rev, err := h.store.Resolve(ref)
+	return (*Revision)(rev), err
+}
+
+// Load reads the snapshot a revision points at
+func (h *History) Load(rev *Revision) (*models.Schema, error) {
+	return h.store.Load((*history.Revision)(rev))
+}
+
+// Blame returns every change to an object in env, oldest first, each
+// attributed to the revision it first appeared in. Naming a table also
+// matches the columns, constraints and indexes under it.
+func (h *History) Blame(env, object string) ([]HistoryChange, error) {
+	changes, err := h.store.Blame(env, object)
+	if err != nil {
+		return nil, err
+	}
+	blame := make([]HistoryChange, len(changes))
+	for i, change := range changes {
+		blame[i] = HistoryChange{Revision: (*Revision)(change.Revision), Difference: change.Difference}
+	}
+	return blame, nil
+}
@@ -0,0 +1,299 @@
+// Package schemalyzer is the stable Go API behind the schemalyzer CLI. It
+// constructs schema readers, compares and fingerprints schemas, loads and
+// saves snapshot files and generates documentation, so services can do
+// everything the CLI does without shelling out to it.
+//
+// Functions return typed errors (ConnectError, ReadError, FileError) or wrap
+// the sentinel errors declared in errors.go, so callers can tell a bad
+// connection string from an unsupported database type with errors.As and
+// errors.Is. Nothing in this package writes to stdout or stderr or exits the
+// process.
+package schemalyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nechja/schemalyzer/internal/database"
+	"github.com/nechja/schemalyzer/internal/database/mysql"
+	"github.com/nechja/schemalyzer/internal/database/oracle"
+	"github.com/nechja/schemalyzer/internal/database/postgres"
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/nechja/schemalyzer/pkg/plugin"
+)
+
+// Reader reads schemas from a live database. Readers returned by NewReader
+// and Open must be closed by the caller. Any implementation can be passed
+// to the functions of this package.
+type Reader interface {
+	Connect(ctx context.Context, connectionString string) error
+	GetSchema(ctx context.Context, schemaName string) (*models.Schema, error)
+	ListSchemas(ctx context.Context) ([]string, error)
+	Close() error
+}
+
+// SnapshotReader is implemented by readers that can pin every read between
+// BeginSnapshot and EndSnapshot to a single point in time
+type SnapshotReader interface {
+	BeginSnapshot(ctx context.Context) error
+	EndSnapshot() error
+}
+
+// StatisticsReader is implemented by readers that can count rows and
+// sample column values for CollectStatistics
+type StatisticsReader interface {
+	GetTableRowCount(ctx context.Context, schemaName, tableName string) (int64, error)
+	GetColumnSamples(ctx context.Context, schemaName, tableName, columnName string, limit int) ([]string, error)
+}
+
+// ReaderOptions tunes the connection pool and query limits of a reader
+type ReaderOptions struct {
+	// MaxOpenConns caps open connections; 0 is unlimited
+	MaxOpenConns int
+	// MaxIdleConns caps connections kept idle in the pool
+	MaxIdleConns int
+	// ConnMaxLifetime recycles connections older than this; 0 keeps them
+	ConnMaxLifetime time.Duration
+	// StatementTimeout aborts any single catalog query after this long; 0 disables
+	StatementTimeout time.Duration
+	// Consistent makes Open pin every read to one point-in-time snapshot
+	Consistent bool
+}
+
+// DefaultReaderOptions returns the pool settings used by the CLI
+func DefaultReaderOptions() ReaderOptions {
+	defaults := database.DefaultReaderOptions()
+	return ReaderOptions{
+		MaxOpenConns:     defaults.MaxOpenConns,
+		MaxIdleConns:     defaults.MaxIdleConns,
+		ConnMaxLifetime:  defaults.ConnMaxLifetime,
+		StatementTimeout: defaults.StatementTimeout,
+	}
+}
+
+// ReadOptions controls what ReadSchema and ReadDatabase keep
+type ReadOptions struct {
+	// TablesOnly drops sequences, routines and triggers, keeping tables and views
+	TablesOnly bool
+	// Concurrency is the number of schemas ReadDatabase reads in parallel;
+	// 0 uses DefaultSchemaConcurrency
+	Concurrency int
+}
+
+// StatisticsOptions selects the statistics CollectStatistics gathers
+type StatisticsOptions struct {
+	// Summary records table, view, column and index counts
+	Summary bool
+	// RowCounts records the row count of every table
+	RowCounts bool
+	// Samples records up to SampleSize sample values per column
+	Samples    bool
+	SampleSize int
+}
+
+// DefaultSchemaConcurrency is the number of schemas ReadDatabase reads in parallel by default
+const DefaultSchemaConcurrency = database.DefaultSchemaConcurrency
+
+// NewReader returns an unconnected reader for dbType. Types without a
+// built-in reader are served by a schemalyzer-reader-<type> plugin on PATH.
+func NewReader(dbType string, opts ReaderOptions) (Reader, error) {
+	options := database.ReaderOptions{
+		MaxOpenConns:     opts.MaxOpenConns,
+		MaxIdleConns:     opts.MaxIdleConns,
+		ConnMaxLifetime:  opts.ConnMaxLifetime,
+		StatementTimeout: opts.StatementTimeout,
+	}
+
+	switch models.DatabaseType(dbType) {
+	case models.PostgreSQL:
+		return postgres.NewPostgresReaderWithOptions(options), nil
+	case models.MySQL:
+		return mysql.NewMySQLReaderWithOptions(options), nil
+	case models.Oracle:
+		return oracle.NewOracleReaderWithOptions(options), nil
+	default:
+		path, err := plugin.Lookup(dbType)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s (no %s%s plugin found on PATH)", ErrUnsupportedDatabase, dbType, plugin.ExecutablePrefix, dbType)
+		}
+		return plugin.NewClient(path), nil
+	}
+}
+
+// Open creates a reader for dbType and connects it. With opts.Consistent
+// set it also begins a point-in-time snapshot, released by Close. The reader
+// is closed again if any step fails.
+func Open(ctx context.Context, dbType, connectionString string, opts ReaderOptions) (Reader, error) {
+	reader, err := NewReader(dbType, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reader.Connect(ctx, connectionString); err != nil {
+		reader.Close()
+		return nil, &ConnectError{DatabaseType: dbType, Err: err}
+	}
+
+	if opts.Consistent {
+		if err := BeginSnapshot(ctx, reader); err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	return reader, nil
+}
+
+// BeginSnapshot pins every later read of reader to a single point in time.
+// The snapshot is released when the reader is closed.
+func BeginSnapshot(ctx context.Context, reader Reader) error {
+	snapshotReader, ok := reader.(SnapshotReader)
+	if !ok {
+		return ErrSnapshotUnsupported
+	}
+	if err := snapshotReader.BeginSnapshot(ctx); err != nil {
+		return fmt.Errorf("failed to begin consistent snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListSchemas lists the schemas visible to reader
+func ListSchemas(ctx context.Context, reader Reader) ([]string, error) {
+	schemas, err := reader.ListSchemas(ctx)
+	if err != nil {
+		return nil, &ReadError{Err: err}
+	}
+	return schemas, nil
+}
+
+// ListSchemasMatching lists the schemas visible to reader whose names match
+// the glob pattern, ignoring case
+func ListSchemasMatching(ctx context.Context, reader Reader, pattern string) ([]string, error) {
+	schemas, err := database.ListSchemasMatching(ctx, reader, pattern)
+	if err != nil {
+		return nil, &ReadError{Pattern: pattern, Err: err}
+	}
+	return schemas, nil
+}
+
+// ReadSchema reads one schema
+func ReadSchema(ctx context.Context, reader Reader, schemaName string, opts ReadOptions) (*models.Schema, error) {
+	schema, err := reader.GetSchema(ctx, schemaName)
+	if err != nil {
+		return nil, &ReadError{Schema: schemaName, Err: err}
+	}
+
+	if opts.TablesOnly {
+		schema = tablesOnly(schema)
+	}
+	return schema, nil
+}
+
+// ReadDatabase reads every schema matching the glob pattern into one
+// database snapshot
+func ReadDatabase(ctx context.Context, reader Reader, pattern string, opts ReadOptions) (*models.Database, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultSchemaConcurrency
+	}
+
+	db, err := database.GetDatabase(ctx, reader, pattern, concurrency)
+	if err != nil {
+		return nil, &ReadError{Pattern: pattern, Err: err}
+	}
+
+	if opts.TablesOnly {
+		db = databaseTablesOnly(db)
+	}
+	return db, nil
+}
+
+// Summarize counts the tables, views, columns and indexes of a schema
+func Summarize(schema *models.Schema) *models.SchemaStats {
+	totalColumns := 0
+	for _, table := range schema.Tables {
+		totalColumns += len(table.Columns)
+	}
+
+	return &models.SchemaStats{
+		TableCount:   len(schema.Tables),
+		ViewCount:    len(schema.Views),
+		TotalColumns: totalColumns,
+		IndexCount:   len(schema.Indexes),
+		GeneratedAt:  time.Now(),
+	}
+}
+
+// CollectStatistics adds the statistics selected by opts to schema. It
+// keeps going when a single table fails and returns those failures joined,
+// so a partial result is still usable.
+func CollectStatistics(ctx context.Context, reader Reader, schema *models.Schema, opts StatisticsOptions) error {
+	if opts.Summary {
+		schema.Stats = Summarize(schema)
+	}
+
+	if !opts.RowCounts && !opts.Samples {
+		return nil
+	}
+
+	statsReader, ok := reader.(StatisticsReader)
+	if !ok {
+		return ErrStatisticsUnsupported
+	}
+
+	var errs []error
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+
+		if opts.RowCounts {
+			count, err := statsReader.GetTableRowCount(ctx, schema.Name, table.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get row count for table %s: %w", table.Name, err))
+			} else {
+				table.RowCount = &count
+			}
+		}
+
+		if opts.Samples {
+			for j := range table.Columns {
+				column := &table.Columns[j]
+				samples, err := statsReader.GetColumnSamples(ctx, schema.Name, table.Name, column.Name, opts.SampleSize)
+				if err != nil {
+					// Columns that can't be sampled (e.g. LOBs) are skipped
+					continue
+				}
+				column.Samples = samples
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// tablesOnly returns a copy of the schema with only tables and views
+func tablesOnly(schema *models.Schema) *models.Schema {
+	return &models.Schema{
+		Name:         schema.Name,
+		DatabaseType: schema.DatabaseType,
+		Tables:       schema.Tables,
+		Views:        schema.Views,
+		Sequences:    []models.Sequence{},
+		Functions:    []models.Function{},
+		Procedures:   []models.Procedure{},
+		Triggers:     []models.Trigger{},
+	}
+}
+
+// databaseTablesOnly applies tablesOnly to every schema of a snapshot
+func databaseTablesOnly(db *models.Database) *models.Database {
+	filtered := &models.Database{
+		DatabaseType: db.DatabaseType,
+		Schemas:      make([]models.Schema, 0, len(db.Schemas)),
+	}
+	for i := range db.Schemas {
+		filtered.Schemas = append(filtered.Schemas, *tablesOnly(&db.Schemas[i]))
+	}
+	return filtered
+}
@@ -0,0 +1,286 @@
+package schemalyzer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nechja/schemalyzer/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves fixed schemas and fails row counts for tables named in failRowCount
+type fakeReader struct {
+	schemas      map[string]*models.Schema
+	failRowCount map[string]bool
+}
+
+func (r *fakeReader) Connect(ctx context.Context, connectionString string) error { return nil }
+func (r *fakeReader) Close() error                                               { return nil }
+
+func (r *fakeReader) ListSchemas(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range r.schemas {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (r *fakeReader) GetSchema(ctx context.Context, schemaName string) (*models.Schema, error) {
+	schema, ok := r.schemas[schemaName]
+	if !ok {
+		return nil, errors.New("schema not found")
+	}
+	return schema, nil
+}
+
+func (r *fakeReader) GetTableRowCount(ctx context.Context, schemaName, tableName string) (int64, error) {
+	if r.failRowCount[tableName] {
+		return 0, errors.New("permission denied")
+	}
+	return 42, nil
+}
+
+func (r *fakeReader) GetColumnSamples(ctx context.Context, schemaName, tableName, columnName string, limit int) ([]string, error) {
+	return []string{"a", "b"}[:limit], nil
+}
+
+func testSchema() *models.Schema {
+	return &models.Schema{
+		Name:         "app",
+		DatabaseType: models.PostgreSQL,
+		Tables: []models.Table{
+			{
+				Name:   "users",
+				Schema: "app",
+				Columns: []models.Column{
+					{Name: "id", DataType: "integer", Position: 1},
+					{Name: "email", DataType: "text", IsNullable: true, Position: 2},
+				},
+			},
+			{
+				Name:    "temp_import",
+				Schema:  "app",
+				Columns: []models.Column{{Name: "line", DataType: "text", Position: 1}},
+			},
+		},
+		Functions: []models.Function{{Schema: "app", Name: "touch", ReturnType: "trigger"}},
+	}
+}
+
+func TestNewReaderUnsupportedDatabase(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := NewReader("cobol", DefaultReaderOptions())
+	assert.ErrorIs(t, err, ErrUnsupportedDatabase)
+	assert.Contains(t, err.Error(), "schemalyzer-reader-cobol")
+}
+
+func TestOpenReturnsConnectError(t *testing.T) {
+	_, err := Open(context.Background(), string(models.MySQL), "not a dsn", DefaultReaderOptions())
+
+	var connectErr *ConnectError
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, "mysql", connectErr.DatabaseType)
+}
+
+func TestReadSchemaTablesOnly(t *testing.T) {
+	reader := &fakeReader{schemas: map[string]*models.Schema{"app": testSchema()}}
+
+	schema, err := ReadSchema(context.Background(), reader, "app", ReadOptions{TablesOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, schema.Tables, 2)
+	assert.Empty(t, schema.Functions)
+
+	_, err = ReadSchema(context.Background(), reader, "missing", ReadOptions{})
+	var readErr *ReadError
+	require.ErrorAs(t, err, &readErr)
+	assert.Equal(t, "missing", readErr.Schema)
+}
+
+func TestListSchemasMatching(t *testing.T) {
+	reader := &fakeReader{schemas: map[string]*models.Schema{
+		"TENANT_001": testSchema(),
+		"tenant_002": testSchema(),
+		"public":     testSchema(),
+	}}
+
+	schemas, err := ListSchemasMatching(context.Background(), reader, "tenant_*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"TENANT_001", "tenant_002"}, schemas)
+
+	_, err = ListSchemasMatching(context.Background(), reader, "tenant_[")
+	var readErr *ReadError
+	require.ErrorAs(t, err, &readErr)
+	assert.Equal(t, "tenant_[", readErr.Pattern)
+}
+
+func TestCompareAppliesOptions(t *testing.T) {
+	source := testSchema()
+	target := testSchema()
+	target.Tables = target.Tables[:1]
+	target.Functions = nil
+
+	result, err := Compare(source, target, CompareOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.Differences, 2)
+
+	result, err = Compare(source, target, CompareOptions{Ignore: []string{"table:temp_*"}, TablesOnly: true})
+	require.NoError(t, err)
+	assert.Empty(t, result.Differences)
+}
+
+func TestFingerprintTablesOnly(t *testing.T) {
+	withFunction := testSchema()
+	withoutFunction := testSchema()
+	withoutFunction.Functions = nil
+
+	full, err := Fingerprint(withFunction, FingerprintOptions{})
+	require.NoError(t, err)
+	other, err := Fingerprint(withoutFunction, FingerprintOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, full, other)
+
+	full, err = Fingerprint(withFunction, FingerprintOptions{TablesOnly: true})
+	require.NoError(t, err)
+	other, err = Fingerprint(withoutFunction, FingerprintOptions{TablesOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, full, other)
+}
+
+func TestCompareFingerprintsRefusesIncompatibleOptions(t *testing.T) {
+	plain, err := Fingerprint(testSchema(), FingerprintOptions{})
+	require.NoError(t, err)
+	normalized, err := Fingerprint(testSchema(), FingerprintOptions{Hash: HashOptions{Bodies: BodiesNormalized}})
+	require.NoError(t, err)
+
+	_, err = CompareFingerprints(plain, normalized)
+	var incompatible *IncompatibleFingerprintsError
+	assert.ErrorAs(t, err, &incompatible)
+
+	// A bare digest from an earlier release hashed the schema differently
+	parsed, err := ParseFingerprint(plain)
+	require.NoError(t, err)
+	_, err = CompareFingerprints(parsed.Digest, plain)
+	require.ErrorAs(t, err, &incompatible)
+	assert.Equal(t, 1, incompatible.SourceVersion)
+	assert.ErrorContains(t, err, "regenerate the stored fingerprint")
+
+	_, err = Fingerprint(testSchema(), FingerprintOptions{Hash: HashOptions{Bodies: "pretty"}})
+	assert.Error(t, err)
+}
+
+func TestSaveAndLoadSnapshots(t *testing.T) {
+	dir := t.TempDir()
+
+	schemaPath := filepath.Join(dir, "app.yaml")
+	require.NoError(t, SaveSchema(testSchema(), schemaPath))
+	loaded, err := LoadSchema(schemaPath)
+	require.NoError(t, err)
+	result, err := Compare(testSchema(), loaded, CompareOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Differences)
+
+	db := &models.Database{DatabaseType: models.PostgreSQL, Schemas: []models.Schema{*testSchema()}}
+	dbPath := filepath.Join(dir, "db.json")
+	require.NoError(t, SaveDatabase(db, dbPath))
+	loadedDB, err := LoadDatabase(dbPath)
+	require.NoError(t, err)
+	assert.Len(t, loadedDB.Schemas, 1)
+
+	// A single-schema file is not silently read as an empty database
+	_, err = LoadDatabase(schemaPath)
+	assert.ErrorContains(t, err, "not a database snapshot")
+
+	// Nor is a database snapshot read as an empty schema
+	_, err = LoadSchema(dbPath)
+	assert.ErrorContains(t, err, "--schemas")
+	yamlDBPath := filepath.Join(dir, "db.yaml")
+	require.NoError(t, SaveDatabase(db, yamlDBPath))
+	_, err = LoadSchema(yamlDBPath)
+	assert.ErrorContains(t, err, "--schemas")
+
+	_, err = LoadSchema(filepath.Join(dir, "missing.json"))
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	assert.Equal(t, "load schema", fileErr.Op)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCollectStatisticsKeepsPartialResults(t *testing.T) {
+	reader := &fakeReader{failRowCount: map[string]bool{"temp_import": true}}
+	schema := testSchema()
+
+	err := CollectStatistics(context.Background(), reader, schema, StatisticsOptions{
+		Summary:    true,
+		RowCounts:  true,
+		Samples:    true,
+		SampleSize: 2,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "temp_import")
+
+	require.NotNil(t, schema.Stats)
+	assert.Equal(t, 3, schema.Stats.TotalColumns)
+	require.NotNil(t, schema.Tables[0].RowCount)
+	assert.Equal(t, int64(42), *schema.Tables[0].RowCount)
+	assert.Nil(t, schema.Tables[1].RowCount)
+	assert.Equal(t, []string{"a", "b"}, schema.Tables[0].Columns[0].Samples)
+}
+
+func TestGenerateDocsFormats(t *testing.T) {
+	format, err := ParseDocumentFormat("DOT")
+	require.NoError(t, err)
+	assert.Equal(t, DocumentGraphViz, format)
+
+	content, err := GenerateDocs(testSchema(), DocumentOptions{Format: "md"})
+	require.NoError(t, err)
+	assert.Contains(t, content, "users")
+
+	_, err = GenerateDocs(testSchema(), DocumentOptions{Format: "pdf"})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = FormatResult(&models.ComparisonResult{}, "xml")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestWriteSite(t *testing.T) {
+	schema := testSchema()
+	schema.Tables = append(schema.Tables, models.Table{
+		Name:    "orders",
+		Schema:  "app",
+		Columns: []models.Column{{Name: "user_id", DataType: "integer", Position: 1, Samples: []string{"7", "42"}}},
+		Constraints: []models.Constraint{{
+			Name: "orders_user_fk", Type: models.ForeignKey, Columns: []string{"user_id"},
+			ReferencedTable: "users", ReferencedColumn: []string{"id"},
+		}},
+	})
+
+	_, err := GenerateDocs(schema, DocumentOptions{Format: DocumentHTMLSite})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteSite(schema, dir, DocumentOptions{Format: DocumentHTMLSite}))
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), `href="tables/orders.html"`)
+	assert.Contains(t, string(index), `href="functions/touch.html"`)
+	assert.FileExists(t, filepath.Join(dir, "style.css"))
+
+	// The foreign key links both ways and both tables draw it
+	orders, err := os.ReadFile(filepath.Join(dir, "tables", "orders.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(orders), `href="../tables/users.html"`)
+	assert.Contains(t, string(orders), "<svg")
+	assert.Contains(t, string(orders), "7, 42")
+
+	users, err := os.ReadFile(filepath.Join(dir, "tables", "users.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(users), "Referenced by")
+	assert.Contains(t, string(users), `href="../tables/orders.html"`)
+	assert.Contains(t, string(users), "<svg")
+}
@@ -0,0 +1,40 @@
+package schemalyzer
+
+import (
+	"github.com/nechja/schemalyzer/internal/schema"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// LoadSchema loads a single-schema snapshot from a .json, .yaml or .yml file
+func LoadSchema(path string) (*models.Schema, error) {
+	s, err := schema.NewLoader().LoadFromFile(path)
+	if err != nil {
+		return nil, &FileError{Op: "load schema", Path: path, Err: err}
+	}
+	return s, nil
+}
+
+// SaveSchema writes a single-schema snapshot, choosing JSON or YAML by extension
+func SaveSchema(s *models.Schema, path string) error {
+	if err := schema.NewLoader().SaveToFile(s, path); err != nil {
+		return &FileError{Op: "save schema", Path: path, Err: err}
+	}
+	return nil
+}
+
+// LoadDatabase loads a multi-schema snapshot written by SaveDatabase
+func LoadDatabase(path string) (*models.Database, error) {
+	db, err := schema.NewLoader().LoadDatabaseFromFile(path)
+	if err != nil {
+		return nil, &FileError{Op: "load database", Path: path, Err: err}
+	}
+	return db, nil
+}
+
+// SaveDatabase writes a multi-schema snapshot, choosing JSON or YAML by extension
+func SaveDatabase(db *models.Database, path string) error {
+	if err := schema.NewLoader().SaveDatabaseToFile(db, path); err != nil {
+		return &FileError{Op: "save database", Path: path, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package schemalyzer
+
+import (
+	"github.com/nechja/schemalyzer/internal/output"
+	"github.com/nechja/schemalyzer/pkg/models"
+)
+
+// Template is a user-defined report layout for comparison results or
+// schema documentation. Files named *.html or *.htm are parsed with
+// html/template and everything else with text/template. Comparison
+// templates are executed against a *models.ComparisonView and
+// documentation templates against a *models.SchemaView.
+type Template struct {
+	tmpl *output.Template
+}
+
+// LoadTemplate parses a template file
+func LoadTemplate(path string) (*Template, error) {
+	tmpl, err := output.ParseTemplate(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// ExecuteComparison renders a comparison result
+func (t *Template) ExecuteComparison(result *models.ComparisonResult) ([]byte, error) {
+	return t.tmpl.ExecuteComparison(result)
+}
+
+// ExecuteSchema renders schema documentation
+func (t *Template) ExecuteSchema(schema *models.Schema) ([]byte, error) {
+	return t.tmpl.ExecuteSchema(schema)
+}